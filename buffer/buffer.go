@@ -0,0 +1,270 @@
+// Package buffer computes the area within a distance of a geometry
+// assuming the geometry is in the 2d plane.
+package buffer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+)
+
+// Geometry returns the area within the distance of the geometry in the
+// units of the geometry. Polygons are shrunk if the distance is negative,
+// points and lines have no area so will return nil. The result will have
+// counter-clockwise outer rings and clockwise holes.
+func Geometry(g orb.Geometry, distance float64, opts ...Option) orb.MultiPolygon {
+	if g == nil {
+		return nil
+	}
+
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return point(g, distance, o)
+	case orb.MultiPoint:
+		var pieces []orb.MultiPolygon
+		for _, p := range g {
+			pieces = append(pieces, point(p, distance, o))
+		}
+		return overlay.UnionAll(pieces)
+	case orb.LineString:
+		return lineString(g, distance, o)
+	case orb.MultiLineString:
+		var pieces []orb.MultiPolygon
+		for _, ls := range g {
+			pieces = append(pieces, lineString(ls, distance, o))
+		}
+		return overlay.UnionAll(pieces)
+	case orb.Ring:
+		return polygon(orb.Polygon{g}, distance, o)
+	case orb.Polygon:
+		return polygon(g, distance, o)
+	case orb.MultiPolygon:
+		if distance < 0 {
+			// polygons shrink independently.
+			var result orb.MultiPolygon
+			for _, p := range g {
+				result = append(result, polygon(p, distance, o)...)
+			}
+			return result
+		}
+
+		var pieces []orb.MultiPolygon
+		for _, p := range g {
+			pieces = append(pieces, polygon(p, distance, o))
+		}
+		return overlay.UnionAll(pieces)
+	case orb.Collection:
+		var pieces []orb.MultiPolygon
+		for _, c := range g {
+			pieces = append(pieces, Geometry(c, distance, opts...))
+		}
+		return overlay.UnionAll(pieces)
+	case orb.Bound:
+		return polygon(g.ToPolygon(), distance, o)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func point(p orb.Point, distance float64, o *options) orb.MultiPolygon {
+	if distance <= 0 {
+		return nil
+	}
+
+	switch o.cap {
+	case CapRound:
+		return orb.MultiPolygon{{circle(p, distance, o.quadrantSeg)}}
+	case CapSquare:
+		b := orb.Bound{Min: p, Max: p}.Pad(distance)
+		return orb.MultiPolygon{b.ToPolygon()}
+	}
+
+	return nil
+}
+
+func lineString(ls orb.LineString, distance float64, o *options) orb.MultiPolygon {
+	if distance <= 0 || len(ls) == 0 {
+		return nil
+	}
+
+	// remove repeated points, they have no direction.
+	line := make(orb.LineString, 0, len(ls))
+	for _, p := range ls {
+		if len(line) == 0 || line[len(line)-1] != p {
+			line = append(line, p)
+		}
+	}
+
+	if len(line) == 1 {
+		return point(line[0], distance, o)
+	}
+
+	closed := len(line) > 2 && line[0] == line[len(line)-1]
+	pieces := sides(line, distance, o, closed)
+
+	if !closed {
+		pieces = append(pieces, caps(line, distance, o)...)
+	}
+
+	return overlay.UnionAll(pieces)
+}
+
+func polygon(p orb.Polygon, distance float64, o *options) orb.MultiPolygon {
+	area := overlay.Union(p, nil)
+	if distance == 0 || len(area) == 0 {
+		return area
+	}
+
+	var pieces []orb.MultiPolygon
+	for _, p := range area {
+		for _, r := range p {
+			pieces = append(pieces, sides(orb.LineString(r), math.Abs(distance), o, true)...)
+		}
+	}
+	boundary := overlay.UnionAll(pieces)
+
+	if distance > 0 {
+		return overlay.Union(area, boundary)
+	}
+
+	return overlay.Difference(area, boundary)
+}
+
+// sides returns the rectangles around each segment of the line along with
+// the joins where the segments meet.
+func sides(line orb.LineString, d float64, o *options, closed bool) []orb.MultiPolygon {
+	var pieces []orb.MultiPolygon
+	for i := 0; i < len(line)-1; i++ {
+		a, b := line[i], line[i+1]
+		if a == b {
+			continue
+		}
+
+		n := normal(a, b, d)
+		pieces = append(pieces, orb.MultiPolygon{{{
+			{a[0] - n[0], a[1] - n[1]},
+			{b[0] - n[0], b[1] - n[1]},
+			{b[0] + n[0], b[1] + n[1]},
+			{a[0] + n[0], a[1] + n[1]},
+			{a[0] - n[0], a[1] - n[1]},
+		}}})
+	}
+
+	for i := 1; i < len(line)-1; i++ {
+		pieces = append(pieces, join(line[i-1], line[i], line[i+1], d, o))
+	}
+
+	if closed && len(line) > 3 {
+		pieces = append(pieces, join(line[len(line)-2], line[0], line[1], d, o))
+	}
+
+	return pieces
+}
+
+// join returns the area that needs to be added to fill the gap on the
+// outside of the corner at b.
+func join(a, b, c orb.Point, d float64, o *options) orb.MultiPolygon {
+	if o.join == JoinRound {
+		return orb.MultiPolygon{{circle(b, d, o.quadrantSeg)}}
+	}
+
+	n1 := normal(a, b, d)
+	n2 := normal(b, c, d)
+
+	turn := cross(sub(b, a), sub(c, b))
+	if turn == 0 {
+		// straight or reversing, nothing to fill
+		return nil
+	}
+
+	// the gap is on the right of a left turn and vice versa.
+	if turn > 0 {
+		n1 = orb.Point{-n1[0], -n1[1]}
+		n2 = orb.Point{-n2[0], -n2[1]}
+	}
+
+	p1 := orb.Point{b[0] + n1[0], b[1] + n1[1]}
+	p2 := orb.Point{b[0] + n2[0], b[1] + n2[1]}
+
+	if o.join == JoinMitre {
+		// the mitre point is along the bisector of the two normals.
+		bisector := orb.Point{n1[0] + n2[0], n1[1] + n2[1]}
+		l := math.Sqrt(bisector[0]*bisector[0] + bisector[1]*bisector[1])
+		if l != 0 {
+			cos := l / (2 * d)
+			dist := d / cos
+			if dist <= o.mitreLimit*d {
+				m := orb.Point{b[0] + bisector[0]/l*dist, b[1] + bisector[1]/l*dist}
+				return orb.MultiPolygon{{{b, p1, m, p2, b}}}
+			}
+		}
+	}
+
+	return orb.MultiPolygon{{{b, p1, p2, b}}}
+}
+
+// caps returns the areas to add to the ends of the line.
+func caps(line orb.LineString, d float64, o *options) []orb.MultiPolygon {
+	switch o.cap {
+	case CapRound:
+		return []orb.MultiPolygon{
+			{{circle(line[0], d, o.quadrantSeg)}},
+			{{circle(line[len(line)-1], d, o.quadrantSeg)}},
+		}
+	case CapSquare:
+		return []orb.MultiPolygon{
+			squareCap(line[1], line[0], d),
+			squareCap(line[len(line)-2], line[len(line)-1], d),
+		}
+	}
+
+	return nil
+}
+
+// squareCap returns the half square extending past b in the direction of a to b.
+func squareCap(a, b orb.Point, d float64) orb.MultiPolygon {
+	n := normal(a, b, d)
+	e := orb.Point{n[1], -n[0]} // direction of a->b with length d
+
+	return orb.MultiPolygon{{{
+		{b[0] - n[0], b[1] - n[1]},
+		{b[0] - n[0] + e[0], b[1] - n[1] + e[1]},
+		{b[0] + n[0] + e[0], b[1] + n[1] + e[1]},
+		{b[0] + n[0], b[1] + n[1]},
+		{b[0] - n[0], b[1] - n[1]},
+	}}}
+}
+
+// circle returns a counter-clockwise ring approximating a circle.
+func circle(c orb.Point, r float64, quadrantSeg int) orb.Ring {
+	n := 4 * quadrantSeg
+	ring := make(orb.Ring, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		ring = append(ring, orb.Point{c[0] + r*math.Cos(a), c[1] + r*math.Sin(a)})
+	}
+
+	return append(ring, ring[0])
+}
+
+// normal returns the vector of length d to the left of the segment a->b.
+func normal(a, b orb.Point, d float64) orb.Point {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l := math.Sqrt(dx*dx + dy*dy)
+	return orb.Point{-dy / l * d, dx / l * d}
+}
+
+func sub(a, b orb.Point) orb.Point {
+	return orb.Point{a[0] - b[0], a[1] - b[1]}
+}
+
+func cross(a, b orb.Point) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
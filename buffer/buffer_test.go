@@ -0,0 +1,226 @@
+package buffer
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestGeometry(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Geometry(g, 1)
+		Geometry(g, -1)
+	}
+}
+
+// circleArea is the area of the circle approximation with the
+// given number of segments per quadrant.
+func circleArea(r float64, quadrantSeg int) float64 {
+	n := float64(4 * quadrantSeg)
+	return n / 2 * r * r * math.Sin(2*math.Pi/n)
+}
+
+func TestGeometry_Area(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	line := orb.LineString{{0, 0}, {10, 0}}
+	corner := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		distance float64
+		opts     []Option
+		area     float64
+	}{
+		{
+			name:     "point",
+			geom:     orb.Point{1, 1},
+			distance: 1,
+			area:     circleArea(1, 8),
+		},
+		{
+			name:     "point, square cap",
+			geom:     orb.Point{1, 1},
+			distance: 1,
+			opts:     []Option{CapStyle(CapSquare)},
+			area:     4,
+		},
+		{
+			name:     "point, flat cap",
+			geom:     orb.Point{1, 1},
+			distance: 1,
+			opts:     []Option{CapStyle(CapFlat)},
+			area:     0,
+		},
+		{
+			name:     "overlapping points",
+			geom:     orb.MultiPoint{{0, 0}, {1, 0}},
+			distance: 1,
+			opts:     []Option{CapStyle(CapSquare)},
+			area:     6,
+		},
+		{
+			name:     "line, round cap",
+			geom:     line,
+			distance: 1,
+			opts:     []Option{SegmentsPerQuadrant(16)},
+			area:     20 + circleArea(1, 16),
+		},
+		{
+			name:     "line, flat cap",
+			geom:     line,
+			distance: 1,
+			opts:     []Option{CapStyle(CapFlat)},
+			area:     20,
+		},
+		{
+			name:     "line, square cap",
+			geom:     line,
+			distance: 1,
+			opts:     []Option{CapStyle(CapSquare)},
+			area:     24,
+		},
+		{
+			name:     "corner, mitre join",
+			geom:     corner,
+			distance: 1,
+			opts:     []Option{CapStyle(CapFlat), JoinStyle(JoinMitre)},
+			area:     40,
+		},
+		{
+			name:     "corner, bevel join",
+			geom:     corner,
+			distance: 1,
+			opts:     []Option{CapStyle(CapFlat), JoinStyle(JoinBevel)},
+			area:     39.5,
+		},
+		{
+			name:     "corner, mitre over limit",
+			geom:     corner,
+			distance: 1,
+			opts:     []Option{CapStyle(CapFlat), JoinStyle(JoinMitre), MitreLimit(1.1)},
+			area:     39.5,
+		},
+		{
+			name:     "polygon, mitre join",
+			geom:     square,
+			distance: 1,
+			opts:     []Option{JoinStyle(JoinMitre)},
+			area:     144,
+		},
+		{
+			name:     "polygon, bevel join",
+			geom:     square,
+			distance: 1,
+			opts:     []Option{JoinStyle(JoinBevel)},
+			area:     142,
+		},
+		{
+			name:     "polygon, round join",
+			geom:     square,
+			distance: 1,
+			area:     140 + circleArea(1, 8),
+		},
+		{
+			name:     "polygon, shrink",
+			geom:     square,
+			distance: -1,
+			area:     64,
+		},
+		{
+			name:     "polygon, shrink to nothing",
+			geom:     square,
+			distance: -6,
+			area:     0,
+		},
+		{
+			name:     "polygon, zero",
+			geom:     square,
+			distance: 0,
+			area:     100,
+		},
+		{
+			name: "polygon with hole",
+			geom: orb.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}},
+			},
+			distance: 0.5,
+			opts:     []Option{JoinStyle(JoinMitre)},
+			area:     11*11 - 1,
+		},
+		{
+			name:     "bound",
+			geom:     orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}},
+			distance: 1,
+			opts:     []Option{JoinStyle(JoinMitre)},
+			area:     144,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Geometry(tc.geom, tc.distance, tc.opts...)
+			if a := planar.Area(result); math.Abs(a-tc.area) > 1e-6 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+				t.Logf("%v", result)
+			}
+
+			for _, p := range result {
+				if p[0].Orientation() != orb.CCW {
+					t.Errorf("outer ring should be ccw")
+				}
+			}
+		})
+	}
+}
+
+func TestGeometry_Lines(t *testing.T) {
+	t.Run("closed line", func(t *testing.T) {
+		ring := orb.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+		result := Geometry(ring, 1, JoinStyle(JoinMitre))
+
+		if len(result) != 1 || len(result[0]) != 2 {
+			t.Fatalf("should be a polygon with a hole: %v", result)
+		}
+
+		if a := planar.Area(result); math.Abs(a-(144-64)) > 1e-6 {
+			t.Errorf("incorrect area: %v", a)
+		}
+	})
+
+	t.Run("repeated points", func(t *testing.T) {
+		result := Geometry(orb.LineString{{0, 0}, {0, 0}}, 1, CapStyle(CapSquare))
+		if a := planar.Area(result); math.Abs(a-4) > 1e-6 {
+			t.Errorf("incorrect area: %v", a)
+		}
+	})
+
+	t.Run("negative distance", func(t *testing.T) {
+		result := Geometry(orb.LineString{{0, 0}, {1, 0}}, -1)
+		if result != nil {
+			t.Errorf("should be empty: %v", result)
+		}
+	})
+}
+
+func BenchmarkLineString(b *testing.B) {
+	for _, n := range []int{100, 500, 2000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			ls := make(orb.LineString, 0, n)
+			for i := 0; i < n; i++ {
+				x := float64(i) / 10
+				ls = append(ls, orb.Point{x, 10 * math.Sin(x)})
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				Geometry(ls, 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package buffer_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/buffer"
+	"github.com/paulmach/orb/planar"
+)
+
+func ExampleGeometry() {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	area := buffer.Geometry(ls, 1,
+		buffer.JoinStyle(buffer.JoinMitre),
+		buffer.CapStyle(buffer.CapFlat),
+	)
+
+	fmt.Println(planar.Area(area))
+	// Output:
+	// 40
+}
@@ -0,0 +1,23 @@
+package buffer
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Geo returns the area within the distance, in meters, of the lon/lat
+// geometry. The geometry is buffered in the mercator projection with the
+// distance scaled by the mercator scale factor at the center of its bound.
+// This is accurate for geometries covering a small range of latitudes.
+// The input geometry is not modified.
+func Geo(g orb.Geometry, meters float64, opts ...Option) orb.MultiPolygon {
+	if g == nil {
+		return nil
+	}
+
+	factor := project.MercatorScaleFactor(g.Bound().Center())
+	merc := project.Geometry(orb.Clone(g), project.WGS84.ToMercator)
+
+	result := Geometry(merc, meters*factor, opts...)
+	return project.MultiPolygon(result, project.Mercator.ToWGS84)
+}
@@ -0,0 +1,36 @@
+package buffer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestGeo(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Geo(g, 1)
+	}
+
+	t.Run("point", func(t *testing.T) {
+		p := orb.Point{-122.4163816, 37.7792782}
+		result := Geo(p, 100, SegmentsPerQuadrant(16))
+
+		for _, v := range result[0][0] {
+			if d := geo.DistanceHaversine(p, v); math.Abs(d-100) > 0.5 {
+				t.Errorf("incorrect distance: %v", d)
+			}
+		}
+	})
+
+	t.Run("does not modify input", func(t *testing.T) {
+		ls := orb.LineString{{-122.4163816, 37.7792782}, {-122.4162786, 37.7787626}}
+		c := ls.Clone()
+		Geo(ls, 10)
+
+		if !ls.Equal(c) {
+			t.Errorf("input was modified: %v", ls)
+		}
+	})
+}
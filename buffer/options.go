@@ -0,0 +1,86 @@
+package buffer
+
+// Join defines how the corners, where two segments meet, are buffered.
+type Join int8
+
+// The supported join styles.
+const (
+	// JoinRound rounds the corners with an arc of the buffer distance.
+	JoinRound Join = iota
+
+	// JoinMitre extends the sides of the buffer until they meet at a point.
+	// If the point is further than the mitre limit, a bevel is used instead.
+	JoinMitre
+
+	// JoinBevel cuts the corner with a straight line.
+	JoinBevel
+)
+
+// Cap defines how the ends of lines, and points, are buffered.
+type Cap int8
+
+// The supported end cap styles.
+const (
+	// CapRound ends the line with a half circle. Points become circles.
+	CapRound Cap = iota
+
+	// CapFlat ends the line exactly at its end point.
+	// Points have no buffer with this style.
+	CapFlat
+
+	// CapSquare ends the line with a half square. Points become squares.
+	CapSquare
+)
+
+type options struct {
+	join        Join
+	cap         Cap
+	quadrantSeg int
+	mitreLimit  float64
+}
+
+func defaultOptions() *options {
+	return &options{
+		join:        JoinRound,
+		cap:         CapRound,
+		quadrantSeg: 8,
+		mitreLimit:  5,
+	}
+}
+
+// An Option is a possible parameter to the buffer operations.
+type Option func(*options)
+
+// JoinStyle sets the join style for the corners. Default is JoinRound.
+func JoinStyle(j Join) Option {
+	return func(o *options) {
+		o.join = j
+	}
+}
+
+// CapStyle sets the end cap style of lines and points. Default is CapRound.
+func CapStyle(c Cap) Option {
+	return func(o *options) {
+		o.cap = c
+	}
+}
+
+// SegmentsPerQuadrant sets the number of segments used to approximate
+// a quarter circle for round joins and caps. Default is 8, values
+// less than 1 will be treated as 1.
+func SegmentsPerQuadrant(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.quadrantSeg = n
+	}
+}
+
+// MitreLimit sets the max distance, as a multiple of the buffer distance,
+// a mitre join can extend from the corner before being beveled. Default is 5.
+func MitreLimit(limit float64) Option {
+	return func(o *options) {
+		o.mitreLimit = limit
+	}
+}
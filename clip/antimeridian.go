@@ -0,0 +1,328 @@
+package clip
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+)
+
+// Antimeridian splits the lon/lat geometry where it crosses the antimeridian,
+// 180 degrees longitude, as recommended by RFC 7946 section 3.1.9.
+// A segment crosses the antimeridian if the longitude changes by more than
+// 180 degrees, e.g. from 179 to -179. The crossing point is found by linear
+// interpolation and added to both sides. Rings going around a pole, with the
+// interior on the left, are extended to the pole.
+// Lines and polygons are returned as a MultiLineString or MultiPolygon if they
+// cross, otherwise the input is returned. Bounds with Min longitude greater than
+// Max longitude are treated as wrapping and returned as a MultiPolygon.
+func Antimeridian(g orb.Geometry) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point, orb.MultiPoint:
+		return g
+	case orb.LineString:
+		if !crossesAntimeridian(g) {
+			return g
+		}
+		return AntimeridianMultiLineString(orb.MultiLineString{g})
+	case orb.MultiLineString:
+		for _, ls := range g {
+			if crossesAntimeridian(ls) {
+				return AntimeridianMultiLineString(g)
+			}
+		}
+		return g
+	case orb.Ring:
+		if !crossesAntimeridian(orb.LineString(g)) {
+			return g
+		}
+		return AntimeridianPolygon(orb.Polygon{g})
+	case orb.Polygon:
+		for _, r := range g {
+			if crossesAntimeridian(orb.LineString(r)) {
+				return AntimeridianPolygon(g)
+			}
+		}
+		return g
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				if crossesAntimeridian(orb.LineString(r)) {
+					return AntimeridianMultiPolygon(g)
+				}
+			}
+		}
+		return g
+	case orb.Collection:
+		c := make(orb.Collection, 0, len(g))
+		for _, cg := range g {
+			c = append(c, Antimeridian(cg))
+		}
+		return c
+	case orb.Bound:
+		if g.Min[0] <= g.Max[0] {
+			return g
+		}
+
+		west := orb.Bound{Min: g.Min, Max: orb.Point{180, g.Max[1]}}
+		east := orb.Bound{Min: orb.Point{-180, g.Min[1]}, Max: g.Max}
+		return orb.MultiPolygon{west.ToPolygon(), east.ToPolygon()}
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// AntimeridianMultiLineString splits the lines where they cross the antimeridian.
+// The lines are broken at ±180 longitude so they do not wrap around the world.
+func AntimeridianMultiLineString(mls orb.MultiLineString) orb.MultiLineString {
+	var result orb.MultiLineString
+	for _, ls := range mls {
+		if len(ls) == 0 {
+			continue
+		}
+
+		current := orb.LineString{ls[0]}
+		for i := 1; i < len(ls); i++ {
+			p, q := ls[i-1], ls[i]
+
+			if crosses(p[0], q[0]) {
+				from, to := 180.0, -180.0
+				if q[0] > p[0] {
+					from, to = -180, 180
+				}
+
+				// interpolate with q moved next to p.
+				qlon := q[0] + 2*from
+				t := (from - p[0]) / (qlon - p[0])
+				lat := p[1] + t*(q[1]-p[1])
+
+				current = appendPoint(current, orb.Point{from, lat})
+				if len(current) > 1 {
+					result = append(result, current)
+				}
+				current = orb.LineString{{to, lat}}
+			}
+
+			current = appendPoint(current, q)
+		}
+
+		if len(current) > 1 || len(ls) == 1 {
+			result = append(result, current)
+		}
+	}
+
+	return result
+}
+
+// AntimeridianPolygon splits the polygon where it crosses the antimeridian.
+func AntimeridianPolygon(p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 {
+		return nil
+	}
+
+	shell := unwrapRing(p[0], p[0][0][0], true)
+	if len(shell) == 0 {
+		return nil
+	}
+
+	b := shell.Bound()
+	center := b.Center()[0]
+
+	unwrapped := orb.Polygon{shell}
+	for _, r := range p[1:] {
+		if len(r) > 0 {
+			unwrapped = append(unwrapped, unwrapRing(r, center, false))
+		}
+	}
+
+	// intersect with each 360 degree wide part of the world and shift it back.
+	var result orb.MultiPolygon
+	first := math.Floor((b.Min[0] + 180) / 360)
+	last := math.Ceil((b.Max[0] - 180) / 360)
+	for k := first; k <= last; k++ {
+		shift := k * 360
+		window := orb.Bound{
+			Min: orb.Point{shift - 180, -90},
+			Max: orb.Point{shift + 180, 90},
+		}
+
+		if !window.Intersects(b) {
+			continue
+		}
+
+		if b.Min[0] >= window.Min[0] && b.Max[0] <= window.Max[0] {
+			result = append(result, shiftPolygon(unwrapped, window, shift))
+			continue
+		}
+
+		for _, part := range overlay.Intersection(unwrapped, window.ToPolygon()) {
+			result = append(result, shiftPolygon(part, window, shift))
+		}
+	}
+
+	return result
+}
+
+// AntimeridianMultiPolygon splits the polygons where they cross the antimeridian.
+func AntimeridianMultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	var result orb.MultiPolygon
+	for _, p := range mp {
+		result = append(result, AntimeridianPolygon(p)...)
+	}
+
+	return result
+}
+
+// CrossesAntimeridian returns true if the lon/lat geometry crosses the
+// antimeridian, i.e. it would be split by Antimeridian.
+func CrossesAntimeridian(g orb.Geometry) bool {
+	switch g := g.(type) {
+	case nil, orb.Point, orb.MultiPoint:
+		return false
+	case orb.LineString:
+		return crossesAntimeridian(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			if crossesAntimeridian(ls) {
+				return true
+			}
+		}
+		return false
+	case orb.Ring:
+		return crossesAntimeridian(orb.LineString(g))
+	case orb.Polygon:
+		for _, r := range g {
+			if crossesAntimeridian(orb.LineString(r)) {
+				return true
+			}
+		}
+		return false
+	case orb.MultiPolygon:
+		for _, p := range g {
+			if CrossesAntimeridian(p) {
+				return true
+			}
+		}
+		return false
+	case orb.Collection:
+		for _, c := range g {
+			if CrossesAntimeridian(c) {
+				return true
+			}
+		}
+		return false
+	case orb.Bound:
+		return g.Min[0] > g.Max[0]
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func crossesAntimeridian(ls orb.LineString) bool {
+	for i := 1; i < len(ls); i++ {
+		if crosses(ls[i-1][0], ls[i][0]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// crosses returns true if going from lon a to b crosses the antimeridian.
+// A change of exactly 360 degrees is an edge from -180 to 180, or the
+// reverse, and is not a crossing.
+func crosses(a, b float64) bool {
+	d := math.Abs(b - a)
+	return d > 180 && d != 360
+}
+
+// unwrapRing returns a copy of the ring with the longitudes changed so
+// consecutive points are never more than 180 degrees apart. The first point
+// is placed within 180 degrees of the reference longitude. If the ring goes
+// around a pole it is extended to that pole, if allowed.
+func unwrapRing(r orb.Ring, ref float64, pole bool) orb.Ring {
+	if len(r) == 0 {
+		return nil
+	}
+
+	result := make(orb.Ring, 0, len(r)+4)
+
+	offset := 360 * math.Round((ref-r[0][0])/360)
+	result = append(result, orb.Point{r[0][0] + offset, r[0][1]})
+	for i := 1; i < len(r); i++ {
+		offset += unwrapOffset(r[i-1][0], r[i][0])
+
+		result = append(result, orb.Point{r[i][0] + offset, r[i][1]})
+	}
+
+	if r[0] != r[len(r)-1] {
+		// close the ring with the unwrapped first point
+		offset += unwrapOffset(r[len(r)-1][0], r[0][0])
+
+		result = append(result, orb.Point{r[0][0] + offset, r[0][1]})
+	}
+
+	start, end := result[0], result[len(result)-1]
+	if start[0] == end[0] || !pole {
+		result[len(result)-1] = start
+		return result
+	}
+
+	// The ring goes around a pole. With the interior on the left, going
+	// east means it contains the north pole.
+	lat := 90.0
+	if end[0] < start[0] {
+		lat = -90
+	}
+
+	return append(result, orb.Point{end[0], lat}, orb.Point{start[0], lat}, start)
+}
+
+// unwrapOffset returns the change needed to b so it's next to a
+// if the edge crosses the antimeridian.
+func unwrapOffset(a, b float64) float64 {
+	if !crosses(a, b) {
+		return 0
+	}
+
+	if b > a {
+		return -360
+	}
+
+	return 360
+}
+
+// shiftPolygon moves the polygon back into the [-180, 180] range,
+// snapping points close to the window edge onto it.
+func shiftPolygon(p orb.Polygon, window orb.Bound, shift float64) orb.Polygon {
+	result := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		ring := make(orb.Ring, 0, len(r))
+		for _, point := range r {
+			x := point[0]
+			if math.Abs(x-window.Min[0]) < 1e-9 {
+				x = window.Min[0]
+			} else if math.Abs(x-window.Max[0]) < 1e-9 {
+				x = window.Max[0]
+			}
+
+			ring = append(ring, orb.Point{x - shift, point[1]})
+		}
+		result = append(result, ring)
+	}
+
+	return result
+}
+
+func appendPoint(ls orb.LineString, p orb.Point) orb.LineString {
+	if len(ls) > 0 && ls[len(ls)-1] == p {
+		return ls
+	}
+
+	return append(ls, p)
+}
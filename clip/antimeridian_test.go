@@ -0,0 +1,186 @@
+package clip
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestAntimeridian(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		Antimeridian(g)
+		CrossesAntimeridian(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		output orb.Geometry
+	}{
+		{
+			name:   "line not crossing",
+			input:  orb.LineString{{170, 0}, {179, 10}},
+			output: orb.LineString{{170, 0}, {179, 10}},
+		},
+		{
+			name:  "line crossing east",
+			input: orb.LineString{{170, 0}, {178, 10}, {-178, 20}, {-170, 20}},
+			output: orb.MultiLineString{
+				{{170, 0}, {178, 10}, {180, 15}},
+				{{-180, 15}, {-178, 20}, {-170, 20}},
+			},
+		},
+		{
+			name:  "line crossing west",
+			input: orb.LineString{{-178, 20}, {178, 10}},
+			output: orb.MultiLineString{
+				{{-178, 20}, {-180, 15}},
+				{{180, 15}, {178, 10}},
+			},
+		},
+		{
+			name:  "line crossing back and forth",
+			input: orb.LineString{{179, 0}, {-179, 0}, {179, 2}},
+			output: orb.MultiLineString{
+				{{179, 0}, {180, 0}},
+				{{-180, 0}, {-179, 0}, {-180, 1}},
+				{{180, 1}, {179, 2}},
+			},
+		},
+		{
+			name:  "line starting on the antimeridian",
+			input: orb.LineString{{180, 0}, {-179, 0}},
+			output: orb.MultiLineString{
+				{{-180, 0}, {-179, 0}},
+			},
+		},
+		{
+			name:   "line along the whole world",
+			input:  orb.LineString{{-180, 0}, {180, 0}},
+			output: orb.LineString{{-180, 0}, {180, 0}},
+		},
+		{
+			name:  "wrapping bound",
+			input: orb.Bound{Min: orb.Point{170, -20}, Max: orb.Point{-170, -10}},
+			output: orb.MultiPolygon{
+				orb.Bound{Min: orb.Point{170, -20}, Max: orb.Point{180, -10}}.ToPolygon(),
+				orb.Bound{Min: orb.Point{-180, -20}, Max: orb.Point{-170, -10}}.ToPolygon(),
+			},
+		},
+		{
+			name:   "bound",
+			input:  orb.Bound{Min: orb.Point{-170, -20}, Max: orb.Point{170, -10}},
+			output: orb.Bound{Min: orb.Point{-170, -20}, Max: orb.Point{170, -10}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Antimeridian(tc.input)
+			if !reflect.DeepEqual(result, tc.output) {
+				t.Errorf("incorrect result")
+				t.Logf("%v", result)
+				t.Logf("%v", tc.output)
+			}
+		})
+	}
+}
+
+func TestAntimeridianPolygon(t *testing.T) {
+	// a box around fiji
+	fiji := orb.Polygon{{{177, -19}, {-179, -19}, {-179, -16}, {177, -16}, {177, -19}}}
+
+	result := Antimeridian(fiji).(orb.MultiPolygon)
+	if len(result) != 2 {
+		t.Fatalf("should split into 2 polygons: %v", result)
+	}
+
+	west := orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{180, -16}}
+	east := orb.Bound{Min: orb.Point{-180, -19}, Max: orb.Point{-179, -16}}
+	for _, p := range result {
+		b := p.Bound()
+		if b != west && b != east {
+			t.Errorf("incorrect part: %v", b)
+		}
+	}
+
+	if a := planar.Area(result); math.Abs(a-12) > 1e-9 {
+		t.Errorf("incorrect area: %v", a)
+	}
+
+	if CrossesAntimeridian(result) {
+		t.Errorf("result should not cross")
+	}
+
+	// with a hole on the other side
+	fiji = append(fiji, orb.Ring{{-179.5, -18}, {-179.5, -17}, {179.5, -17}, {179.5, -18}, {-179.5, -18}})
+	result = Antimeridian(fiji).(orb.MultiPolygon)
+	if a := planar.Area(result); math.Abs(a-11) > 1e-9 {
+		t.Errorf("incorrect area with hole: %v", a)
+	}
+}
+
+func TestAntimeridianPolygon_pole(t *testing.T) {
+	// counter clockwise around the north pole, crossing the antimeridian.
+	ring := orb.Ring{{0, 80}, {90, 80}, {180, 80}, {-90, 80}, {0, 80}}
+
+	result := Antimeridian(orb.Polygon{ring}).(orb.MultiPolygon)
+	b := result.Bound()
+	if b.Min != (orb.Point{-180, 80}) || b.Max != (orb.Point{180, 90}) {
+		t.Errorf("should extend to the pole: %v", b)
+	}
+
+	if CrossesAntimeridian(result) {
+		t.Errorf("result should not cross: %v", result)
+	}
+
+	if a := planar.Area(result); math.Abs(a-3600) > 1e-9 {
+		t.Errorf("incorrect area: %v", a)
+	}
+}
+
+func TestCrossesAntimeridian(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   orb.Geometry
+		crosses bool
+	}{
+		{
+			name:    "point",
+			input:   orb.Point{180, 0},
+			crosses: false,
+		},
+		{
+			name:    "line",
+			input:   orb.LineString{{179, 0}, {-179, 0}},
+			crosses: true,
+		},
+		{
+			name:    "line not crossing",
+			input:   orb.LineString{{-179, 0}, {0, 0}, {179, 0}},
+			crosses: false,
+		},
+		{
+			name:    "collection",
+			input:   orb.Collection{orb.Point{1, 2}, orb.LineString{{179, 0}, {-179, 0}}},
+			crosses: true,
+		},
+		{
+			name:    "wrapping bound",
+			input:   orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 1}},
+			crosses: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := CrossesAntimeridian(tc.input); v != tc.crosses {
+				t.Errorf("incorrect: %v != %v", v, tc.crosses)
+			}
+		})
+	}
+}
@@ -1,4 +1,5 @@
-// Package clip is a library for clipping geometry to a bounding box.
+// Package clip is a library for clipping geometry to a bounding box
+// or an arbitrary polygon.
 package clip
 
 import (
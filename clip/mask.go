@@ -0,0 +1,304 @@
+package clip
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+// GeometryByPolygon will clip the geometry to the polygon mask using the
+// correct functions for the type. The mask can be concave and have holes.
+// Points on the boundary of the mask are considered inside.
+// 2d geometries will be returned as a Polygon or MultiPolygon.
+func GeometryByPolygon(mask orb.Polygon, g orb.Geometry) orb.Geometry {
+	if g == nil || len(mask) == 0 {
+		return nil
+	}
+
+	if !mask.Bound().Intersects(g.Bound()) {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		if maskContains(mask, g) {
+			return g
+		}
+
+		return nil
+	case orb.MultiPoint:
+		mp := MultiPointByPolygon(mask, g)
+		if len(mp) == 1 {
+			return mp[0]
+		}
+
+		if mp == nil {
+			return nil
+		}
+
+		return mp
+	case orb.LineString:
+		mls := LineStringByPolygon(mask, g)
+		if len(mls) == 1 {
+			return mls[0]
+		}
+
+		if mls == nil {
+			return nil
+		}
+
+		return mls
+	case orb.MultiLineString:
+		mls := MultiLineStringByPolygon(mask, g)
+		if len(mls) == 1 {
+			return mls[0]
+		}
+
+		if mls == nil {
+			return nil
+		}
+
+		return mls
+	case orb.Ring:
+		return polygonResult(PolygonByPolygon(mask, orb.Polygon{g}))
+	case orb.Polygon:
+		return polygonResult(PolygonByPolygon(mask, g))
+	case orb.MultiPolygon:
+		return polygonResult(MultiPolygonByPolygon(mask, g))
+	case orb.Collection:
+		c := CollectionByPolygon(mask, g)
+		if len(c) == 1 {
+			return c[0]
+		}
+
+		if c == nil {
+			return nil
+		}
+
+		return c
+	case orb.Bound:
+		return polygonResult(PolygonByPolygon(mask, g.ToPolygon()))
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func polygonResult(mp orb.MultiPolygon) orb.Geometry {
+	if len(mp) == 1 {
+		return mp[0]
+	}
+
+	if mp == nil {
+		return nil
+	}
+
+	return mp
+}
+
+// MultiPointByPolygon returns a new set with the points outside the polygon mask removed.
+func MultiPointByPolygon(mask orb.Polygon, mp orb.MultiPoint) orb.MultiPoint {
+	var result orb.MultiPoint
+	for _, p := range mp {
+		if maskContains(mask, p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// LineStringByPolygon clips the linestring to the polygon mask.
+// Parts of the line along the mask boundary are kept.
+func LineStringByPolygon(mask orb.Polygon, ls orb.LineString) orb.MultiLineString {
+	if len(mask) == 0 || !mask.Bound().Intersects(ls.Bound()) {
+		return nil
+	}
+
+	result := linePolygon(mask, ls)
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// MultiLineStringByPolygon clips the linestrings to the polygon mask
+// and returns a linestring union.
+func MultiLineStringByPolygon(mask orb.Polygon, mls orb.MultiLineString) orb.MultiLineString {
+	var result orb.MultiLineString
+	for _, ls := range mls {
+		r := LineStringByPolygon(mask, ls)
+		if len(r) != 0 {
+			result = append(result, r...)
+		}
+	}
+
+	return result
+}
+
+// PolygonByPolygon clips the polygon to the polygon mask. The result may
+// be split into many polygons if the mask is concave or has holes.
+// Unlike the bound versions, the input is not modified.
+func PolygonByPolygon(mask orb.Polygon, p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 || len(mask) == 0 {
+		return nil
+	}
+
+	return overlay.Intersection(p, mask)
+}
+
+// MultiPolygonByPolygon clips the multi polygon to the polygon mask
+// excluding any polygons that don't intersect the mask.
+func MultiPolygonByPolygon(mask orb.Polygon, mp orb.MultiPolygon) orb.MultiPolygon {
+	var result orb.MultiPolygon
+	for _, polygon := range mp {
+		result = append(result, PolygonByPolygon(mask, polygon)...)
+	}
+
+	return result
+}
+
+// CollectionByPolygon clips each element in the collection to the polygon mask.
+// It will exclude elements if they don't intersect the mask.
+func CollectionByPolygon(mask orb.Polygon, c orb.Collection) orb.Collection {
+	var result orb.Collection
+	for _, g := range c {
+		clipped := GeometryByPolygon(mask, g)
+		if clipped != nil {
+			result = append(result, clipped)
+		}
+	}
+
+	return result
+}
+
+// linePolygon will clip a line into a set of lines inside the polygon.
+// Each segment is split where it crosses the mask boundary and the pieces
+// with a midpoint inside the mask are kept.
+func linePolygon(mask orb.Polygon, in orb.LineString) orb.MultiLineString {
+	var out orb.MultiLineString
+
+	inside := false
+	for i := 0; i < len(in)-1; i++ {
+		a, b := in[i], in[i+1]
+		if a == b {
+			continue
+		}
+
+		ts := []float64{0, 1}
+		for _, r := range mask {
+			for j := 0; j < len(r)-1; j++ {
+				ts = appendCrossings(ts, a, b, r[j], r[j+1])
+			}
+		}
+		sort.Float64s(ts)
+
+		for k := 1; k < len(ts); k++ {
+			if ts[k] == ts[k-1] {
+				continue
+			}
+
+			mid := interpolate(a, b, (ts[k-1]+ts[k])/2)
+			if !maskContains(mask, mid) {
+				inside = false
+				continue
+			}
+
+			s, e := interpolate(a, b, ts[k-1]), interpolate(a, b, ts[k])
+			if !inside {
+				out = append(out, orb.LineString{s})
+			}
+
+			out[len(out)-1] = append(out[len(out)-1], e)
+			inside = true
+		}
+	}
+
+	return out
+}
+
+// appendCrossings adds the positions, as a fraction of the segment [a, b],
+// where the edge [c, d] touches the segment.
+func appendCrossings(ts []float64, a, b, c, d orb.Point) []float64 {
+	if (c[0] < a[0] && c[0] < b[0] && d[0] < a[0] && d[0] < b[0]) ||
+		(c[0] > a[0] && c[0] > b[0] && d[0] > a[0] && d[0] > b[0]) ||
+		(c[1] < a[1] && c[1] < b[1] && d[1] < a[1] && d[1] < b[1]) ||
+		(c[1] > a[1] && c[1] > b[1] && d[1] > a[1] && d[1] > b[1]) {
+		return ts
+	}
+
+	r := orb.Point{b[0] - a[0], b[1] - a[1]}
+	s := orb.Point{d[0] - c[0], d[1] - c[1]}
+	ca := orb.Point{c[0] - a[0], c[1] - a[1]}
+
+	denom := r[0]*s[1] - r[1]*s[0]
+	if denom == 0 {
+		if ca[0]*r[1]-ca[1]*r[0] != 0 {
+			return ts // parallel
+		}
+
+		// collinear, add the end points of the edge that are on the segment.
+		rr := r[0]*r[0] + r[1]*r[1]
+		for _, p := range []orb.Point{c, d} {
+			t := ((p[0]-a[0])*r[0] + (p[1]-a[1])*r[1]) / rr
+			if t > 0 && t < 1 {
+				ts = append(ts, t)
+			}
+		}
+
+		return ts
+	}
+
+	t := (ca[0]*s[1] - ca[1]*s[0]) / denom
+	u := (ca[0]*r[1] - ca[1]*r[0]) / denom
+	if t > 0 && t < 1 && u >= 0 && u <= 1 {
+		ts = append(ts, t)
+	}
+
+	return ts
+}
+
+// maskContains checks if the point is within the mask. Unlike
+// planar.PolygonContains, points on the boundary of a hole are also
+// considered inside.
+func maskContains(mask orb.Polygon, p orb.Point) bool {
+	if !planar.RingContains(mask[0], p) {
+		return false
+	}
+
+	for _, h := range mask[1:] {
+		if planar.RingContains(h, p) && !onRing(h, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func onRing(r orb.Ring, p orb.Point) bool {
+	for i := 0; i < len(r)-1; i++ {
+		if planar.DistanceFromSegmentSquared(r[i], r[i+1], p) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func interpolate(a, b orb.Point, t float64) orb.Point {
+	if t == 0 {
+		return a
+	}
+
+	if t == 1 {
+		return b
+	}
+
+	return orb.Point{
+		a[0] + t*(b[0]-a[0]),
+		a[1] + t*(b[1]-a[1]),
+	}
+}
@@ -0,0 +1,198 @@
+package clip
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// a "U" shaped mask with a hole in the base.
+//
+// +-+ +-+
+// | | | |
+// | +-+ |
+// | [ ] |
+// +-----+
+var uMask = orb.Polygon{
+	{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 2}, {1, 2}, {1, 3}, {0, 3}, {0, 0}},
+	{{1, 0.5}, {1, 1.5}, {2, 1.5}, {2, 0.5}, {1, 0.5}},
+}
+
+func TestGeometryByPolygon(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		GeometryByPolygon(uMask, g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		output orb.Geometry
+	}{
+		{
+			name:   "point inside",
+			input:  orb.Point{0.5, 0.5},
+			output: orb.Point{0.5, 0.5},
+		},
+		{
+			name:   "point in hole",
+			input:  orb.Point{1.5, 1},
+			output: nil,
+		},
+		{
+			name:   "point on hole boundary",
+			input:  orb.Point{1.5, 0.5},
+			output: orb.Point{1.5, 0.5},
+		},
+		{
+			name:   "point on outer boundary",
+			input:  orb.Point{0, 1},
+			output: orb.Point{0, 1},
+		},
+		{
+			name:   "point in notch",
+			input:  orb.Point{1.5, 2.5},
+			output: nil,
+		},
+		{
+			name:   "only one multipoint inside",
+			input:  orb.MultiPoint{{0.5, 0.5}, {1.5, 2.5}},
+			output: orb.Point{0.5, 0.5},
+		},
+		{
+			name:   "line through the notch",
+			input:  orb.LineString{{-1, 2.5}, {4, 2.5}},
+			output: orb.MultiLineString{{{0, 2.5}, {1, 2.5}}, {{2, 2.5}, {3, 2.5}}},
+		},
+		{
+			name:   "line outside",
+			input:  orb.LineString{{-1, 5}, {4, 5}},
+			output: nil,
+		},
+		{
+			name:   "polygon over the notch",
+			input:  orb.Bound{Min: orb.Point{0.5, 2.5}, Max: orb.Point{2.5, 3.5}},
+			output: orb.MultiPolygon{
+				{{{0.5, 2.5}, {1, 2.5}, {1, 3}, {0.5, 3}, {0.5, 2.5}}},
+				{{{2, 2.5}, {2.5, 2.5}, {2.5, 3}, {2, 3}, {2, 2.5}}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := GeometryByPolygon(uMask, tc.input)
+			if !orb.Equal(result, tc.output) {
+				t.Errorf("not equal")
+				t.Logf("%v", result)
+				t.Logf("%v", tc.output)
+			}
+		})
+	}
+}
+
+func TestLineStringByPolygon(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  orb.LineString
+		output orb.MultiLineString
+	}{
+		{
+			name:   "fully inside",
+			input:  orb.LineString{{0.5, 0.25}, {2.5, 0.25}, {2.5, 2.5}},
+			output: orb.MultiLineString{{{0.5, 0.25}, {2.5, 0.25}, {2.5, 2.5}}},
+		},
+		{
+			name:  "across the hole",
+			input: orb.LineString{{0.5, 1}, {2.5, 1}},
+			output: orb.MultiLineString{
+				{{0.5, 1}, {1, 1}},
+				{{2, 1}, {2.5, 1}},
+			},
+		},
+		{
+			name:   "along the boundary",
+			input:  orb.LineString{{-1, 0}, {4, 0}},
+			output: orb.MultiLineString{{{0, 0}, {3, 0}}},
+		},
+		{
+			name:   "along the hole boundary",
+			input:  orb.LineString{{0.5, 0.5}, {2.5, 0.5}},
+			output: orb.MultiLineString{{{0.5, 0.5}, {1, 0.5}, {2, 0.5}, {2.5, 0.5}}},
+		},
+		{
+			name:   "in and out through a vertex",
+			input:  orb.LineString{{-1, -1}, {0.5, 0.5}, {0.5, 0.25}},
+			output: orb.MultiLineString{{{0, 0}, {0.5, 0.5}, {0.5, 0.25}}},
+		},
+		{
+			name:   "repeated points",
+			input:  orb.LineString{{0.5, 0.5}, {0.5, 0.5}, {0.5, 0.25}},
+			output: orb.MultiLineString{{{0.5, 0.5}, {0.5, 0.25}}},
+		},
+		{
+			name:   "empty",
+			input:  orb.LineString{},
+			output: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := LineStringByPolygon(uMask, tc.input)
+			if !reflect.DeepEqual(result, tc.output) {
+				t.Errorf("incorrect clip")
+				t.Logf("%v", result)
+				t.Logf("%v", tc.output)
+			}
+		})
+	}
+}
+
+func TestMultiLineStringByPolygon(t *testing.T) {
+	mls := orb.MultiLineString{
+		{{-1, 2.5}, {4, 2.5}},
+		{{-1, 5}, {4, 5}},
+		{{0.5, 0.25}, {2.5, 0.25}},
+	}
+
+	result := MultiLineStringByPolygon(uMask, mls)
+	expected := orb.MultiLineString{
+		{{0, 2.5}, {1, 2.5}},
+		{{2, 2.5}, {3, 2.5}},
+		{{0.5, 0.25}, {2.5, 0.25}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("incorrect clip")
+		t.Logf("%v", result)
+		t.Logf("%v", expected)
+	}
+}
+
+func TestPolygonByPolygon(t *testing.T) {
+	p := orb.Polygon{{{-1, -1}, {4, -1}, {4, 4}, {-1, 4}, {-1, -1}}}
+
+	result := PolygonByPolygon(uMask, p)
+	if a, e := planar.Area(result), planar.Area(uMask); math.Abs(a-e) > 1e-9 {
+		t.Errorf("incorrect area: %v != %v", a, e)
+	}
+
+	if len(result) != 1 || len(result[0]) != 2 {
+		t.Errorf("should be the mask with its hole: %v", result)
+	}
+}
+
+func TestMultiPolygonByPolygon(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{5, 5}, {6, 5}, {6, 6}, {5, 6}, {5, 5}}},
+	}
+
+	result := MultiPolygonByPolygon(uMask, mp)
+	if len(result) != 1 {
+		t.Errorf("should remove polygon outside the mask: %v", result)
+	}
+}
@@ -0,0 +1,198 @@
+// Package ewkb is for encoding and decoding the PostGIS Extended Well Known
+// Binary (EWKB) format. It is WKB with flags in the geometry type for Z and M
+// values and an optional SRID after the type.
+package ewkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/internal/wkbcommon"
+)
+
+var (
+	// ErrNotEWKB is returned when unmarshalling EWKB and the data is not valid.
+	ErrNotEWKB = wkbcommon.ErrNotWKB
+
+	// ErrUnsupportedGeometry is returned when geometry type is not supported by this lib.
+	ErrUnsupportedGeometry = wkbcommon.ErrUnsupportedGeometry
+
+	// ErrInvalidZM is returned when marshalling a GeometryZM and the number of
+	// extra values does not match the points and layout.
+	ErrInvalidZM = errors.New("ewkb: extra values do not match the geometry and layout")
+)
+
+// DefaultByteOrder is the order used for marshalling or encoding
+// is none is specified.
+var DefaultByteOrder binary.ByteOrder = binary.LittleEndian
+
+// MustMarshal will encode the geometry and panic on error. Since there are
+// no Z or M values the only error is ErrUnsupportedGeometry, when the
+// geometry is not one of the orb types. Use MarshalZM to handle the
+// ErrInvalidZM error returned if the extra values do not match.
+func MustMarshal(geom orb.Geometry, srid int, byteOrder ...binary.ByteOrder) []byte {
+	d, err := Marshal(geom, srid, byteOrder...)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Marshal encodes the geometry with the given srid and byte order.
+// An srid of 0 will not be included in the output.
+func Marshal(geom orb.Geometry, srid int, byteOrder ...binary.ByteOrder) ([]byte, error) {
+	return MarshalZM(orb.GeometryZM{Geometry: geom}, srid, byteOrder...)
+}
+
+// MarshalZM encodes the geometry, including its Z and M values,
+// with the given srid and byte order. It returns ErrInvalidZM if the
+// number of extra values does not match the points and layout.
+func MarshalZM(g orb.GeometryZM, srid int, byteOrder ...binary.ByteOrder) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+
+	e := NewEncoder(buf)
+	e.SetSRID(srid)
+	if len(byteOrder) > 0 {
+		e.SetByteOrder(byteOrder[0])
+	}
+
+	err := e.EncodeZM(g)
+	if err != nil {
+		return nil, err
+	}
+
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal will decode the data into a geometry and return the srid,
+// 0 if not present. Any Z and M values are dropped.
+func Unmarshal(data []byte) (orb.Geometry, int, error) {
+	g, srid, err := UnmarshalZM(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return g.Geometry, srid, nil
+}
+
+// UnmarshalZM will decode the data into a geometry, keeping any Z and M
+// values, and return the srid, 0 if not present.
+func UnmarshalZM(data []byte) (orb.GeometryZM, int, error) {
+	g, srid, err := NewDecoder(bytes.NewReader(data)).DecodeZM()
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return orb.GeometryZM{}, 0, ErrNotEWKB
+	}
+
+	return g, srid, err
+}
+
+// An Encoder will encode a geometry as EWKB to the writer given at
+// creation time.
+type Encoder struct {
+	w     io.Writer
+	order binary.ByteOrder
+	srid  int
+}
+
+// NewEncoder creates a new Encoder for the given writer.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:     w,
+		order: DefaultByteOrder,
+	}
+}
+
+// SetByteOrder will override the default byte order set when
+// the encoder was created.
+func (e *Encoder) SetByteOrder(bo binary.ByteOrder) {
+	e.order = bo
+}
+
+// SetSRID will set the srid included with each geometry.
+// An srid of 0, the default, will not be included.
+func (e *Encoder) SetSRID(srid int) {
+	e.srid = srid
+}
+
+// Encode will write the geometry encoded as EWKB to the given writer.
+func (e *Encoder) Encode(geom orb.Geometry) error {
+	return e.EncodeZM(orb.GeometryZM{Geometry: geom})
+}
+
+// EncodeZM will write the geometry, including its Z and M values,
+// encoded as EWKB to the given writer.
+func (e *Encoder) EncodeZM(g orb.GeometryZM) error {
+	if isNil(g.Geometry) {
+		return nil
+	}
+
+	// without extra values other geometry types
+	// are reported as unsupported when encoding.
+	if (g.Layout != orb.XY || len(g.Extra) > 0) && !g.Valid() {
+		return ErrInvalidZM
+	}
+
+	return wkbcommon.Encode(e.w, e.order, g, e.srid, true)
+}
+
+// Decoder can decoder EWKB geometry off of the stream.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder will create a new EWKB decoder.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decode will decode the next geometry off of the stream and return
+// its srid, 0 if not present. Any Z and M values are dropped.
+func (d *Decoder) Decode() (orb.Geometry, int, error) {
+	g, srid, err := d.DecodeZM()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return g.Geometry, srid, nil
+}
+
+// DecodeZM will decode the next geometry off of the stream, keeping any
+// Z and M values, and return its srid, 0 if not present.
+func (d *Decoder) DecodeZM() (orb.GeometryZM, int, error) {
+	return wkbcommon.Decode(d.r)
+}
+
+// isNil returns true for nil geometries, these are not encoded.
+// Empty, but not nil, geometries will still write an empty version of that type.
+func isNil(geom orb.Geometry) bool {
+	switch g := geom.(type) {
+	case nil:
+		return true
+	case orb.MultiPoint:
+		return g == nil
+	case orb.LineString:
+		return g == nil
+	case orb.MultiLineString:
+		return g == nil
+	case orb.Ring:
+		return g == nil
+	case orb.Polygon:
+		return g == nil
+	case orb.MultiPolygon:
+		return g == nil
+	case orb.Collection:
+		return g == nil
+	}
+
+	return false
+}
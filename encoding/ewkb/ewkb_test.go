@@ -0,0 +1,258 @@
+package ewkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
+)
+
+func TestMarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		srid     int
+		order    binary.ByteOrder
+		expected string
+	}{
+		{
+			name:     "point with srid",
+			geom:     orb.Point{1, 2},
+			srid:     4326,
+			order:    binary.LittleEndian,
+			expected: "0101000020e6100000000000000000f03f0000000000000040",
+		},
+		{
+			name:     "big endian",
+			geom:     orb.Point{1, 2},
+			srid:     3857,
+			order:    binary.BigEndian,
+			expected: "002000000100000f113ff00000000000004000000000000000",
+		},
+		{
+			name:     "no srid is wkb",
+			geom:     orb.Point{1, 2},
+			order:    binary.LittleEndian,
+			expected: "0101000000000000000000f03f0000000000000040",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Marshal(tc.geom, tc.srid, tc.order)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if v := hex.EncodeToString(data); v != tc.expected {
+				t.Errorf("incorrect data")
+				t.Logf("%v", v)
+				t.Logf("%v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestMarshal_nil(t *testing.T) {
+	data, err := Marshal(orb.LineString(nil), 4326)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if data != nil {
+		t.Errorf("should be nil: %v", data)
+	}
+}
+
+func TestMarshalZM_invalid(t *testing.T) {
+	_, err := MarshalZM(orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYZ, Extra: []float64{1}}, 4326)
+	if err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	_, err = MarshalZM(orb.GeometryZM{Geometry: orb.Point{1, 2}, Extra: []float64{1}}, 4326)
+	if err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+type customGeometry struct {
+	orb.Point
+}
+
+func TestMustMarshal_unsupported(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrUnsupportedGeometry {
+			t.Errorf("incorrect panic: %v", r)
+		}
+	}()
+
+	MustMarshal(customGeometry{}, 4326)
+}
+
+func TestUnmarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected orb.GeometryZM
+		srid     int
+	}{
+		{
+			name:     "point with srid",
+			data:     "0101000020e6100000000000000000f03f0000000000000040",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}},
+			srid:     4326,
+		},
+		{
+			name: "line string z with srid",
+			data: "01020000a0e610000002000000000000000000f03f00000000000000400000000000000840000000000000104000000000000014400000000000001840",
+			expected: orb.GeometryZM{
+				Geometry: orb.LineString{{1, 2}, {4, 5}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{3, 6},
+			},
+			srid: 4326,
+		},
+		{
+			name:     "point m",
+			data:     "0101000040000000000000f03f00000000000000400000000000000840",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYM, Extra: []float64{3}},
+		},
+		{
+			name:     "big endian",
+			data:     "002000000100000f113ff00000000000004000000000000000",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}},
+			srid:     3857,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tc.data)
+
+			g, srid, err := UnmarshalZM(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !reflect.DeepEqual(g, tc.expected) {
+				t.Errorf("incorrect geometry: %v", g)
+			}
+
+			if srid != tc.srid {
+				t.Errorf("incorrect srid: %v != %v", srid, tc.srid)
+			}
+
+			g2, srid, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !orb.Equal(g2, tc.expected.Geometry) || srid != tc.srid {
+				t.Errorf("incorrect 2d geometry: %v %v", g2, srid)
+			}
+
+			// should round trip
+			out, err := MarshalZM(g, srid, binaryOrder(data))
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if v := hex.EncodeToString(out); v != tc.data {
+				t.Errorf("incorrect round trip: %v", v)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []string{
+		"",
+		"01",
+		"0101000020e6100000",
+		"0101000020e6100000000000000000f03f",
+		"0909000000",
+	}
+
+	for _, c := range cases {
+		data, _ := hex.DecodeString(c)
+		if _, _, err := Unmarshal(data); err == nil {
+			t.Errorf("expected error for %v", c)
+		}
+	}
+}
+
+func TestMarshal_allGeometries(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		data, err := Marshal(g, 4326)
+		if err != nil {
+			t.Fatalf("marshal error for %T: %v", g, err)
+		}
+
+		if data == nil {
+			continue
+		}
+
+		geom, srid, err := Unmarshal(data)
+		if err != nil {
+			t.Fatalf("unmarshal error for %T: %v", g, err)
+		}
+
+		if srid != 4326 {
+			t.Errorf("incorrect srid for %T: %v", g, srid)
+		}
+
+		// should be readable as wkb, the srid is dropped
+		wg, err := wkb.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("wkb unmarshal error for %T: %v", g, err)
+		}
+
+		if !orb.Equal(wg, geom) {
+			t.Errorf("wkb geometry not equal for %T: %v != %v", g, wg, geom)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	e := NewEncoder(buf)
+	e.SetSRID(4326)
+	e.SetByteOrder(binary.BigEndian)
+
+	geoms := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		orb.Collection{orb.Point{3, 4}, orb.LineString{{5, 6}, {7, 8}}},
+	}
+	for _, g := range geoms {
+		if err := e.Encode(g); err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+	}
+
+	d := NewDecoder(buf)
+	for _, expected := range geoms {
+		g, srid, err := d.Decode()
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		if !orb.Equal(g, expected) || srid != 4326 {
+			t.Errorf("incorrect geometry: %v %v", g, srid)
+		}
+	}
+}
+
+func binaryOrder(data []byte) binary.ByteOrder {
+	if data[0] == 0 {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
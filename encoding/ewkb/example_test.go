@@ -0,0 +1,26 @@
+package ewkb_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+)
+
+func ExampleMarshal() {
+	data, _ := ewkb.Marshal(orb.Point{1, 2}, 4326)
+	fmt.Printf("%x\n", data)
+
+	// Output:
+	// 0101000020e6100000000000000000f03f0000000000000040
+}
+
+func ExampleUnmarshal() {
+	data := ewkb.MustMarshal(orb.Point{1, 2}, 4326)
+
+	g, srid, _ := ewkb.Unmarshal(data)
+	fmt.Println(g, srid)
+
+	// Output:
+	// [1 2] 4326
+}
@@ -0,0 +1,199 @@
+package ewkb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	_ sql.Scanner  = &GeometryScanner{}
+	_ driver.Value = value{}
+)
+
+var (
+	// ErrUnsupportedDataType is returned by Scan methods when asked to scan
+	// non []byte or string data from the database.
+	ErrUnsupportedDataType = errors.New("ewkb: scan value must be []byte or string")
+
+	// ErrIncorrectGeometry is returned when unmarshalling EWKB data into the wrong type.
+	// For example, unmarshaling linestring data into a point.
+	ErrIncorrectGeometry = errors.New("ewkb: incorrect geometry")
+)
+
+// GeometryScanner is a thing that can scan in sql query results.
+// It can be used as a scan destination:
+//
+//	var s ewkb.GeometryScanner
+//	err := db.QueryRow("SELECT geom FROM foo WHERE id=$1", id).Scan(&s)
+//	...
+//	if s.Valid {
+//	  // use s.Geometry and s.SRID
+//	} else {
+//	  // NULL value
+//	}
+type GeometryScanner struct {
+	g        interface{}
+	Geometry orb.Geometry
+	SRID     int
+	Valid    bool // Valid is true if the geometry is not NULL
+}
+
+// Scanner will return a GeometryScanner that can scan sql query results.
+// The geometryScanner.Geometry and SRID attributes will be set to the value.
+// If g is non-nil, it MUST be a pointer to an orb.Geometry
+// type like a Point or LineString. In that case the value will be written to
+// g and the Geometry attribute.
+//
+//	var p orb.Point
+//	s := ewkb.Scanner(&p)
+//	err := db.QueryRow("SELECT geom FROM foo WHERE id=$1", id).Scan(s)
+//	...
+//	// use p and s.SRID
+//
+// PostGIS returns geometry columns as hex encoded EWKB when using the text
+// protocol, this is detected and decoded. Any Z and M values are dropped.
+func Scanner(g interface{}) *GeometryScanner {
+	return &GeometryScanner{g: g}
+}
+
+// Scan will scan the input []byte or string data into a geometry.
+// This could be into the orb geometry type pointer or, if nil,
+// the scanner.Geometry attribute.
+func (s *GeometryScanner) Scan(d interface{}) error {
+	s.Geometry = nil
+	s.SRID = 0
+	s.Valid = false
+
+	var data []byte
+	switch v := d.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return ErrUnsupportedDataType
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	data, err := decodeHex(data)
+	if err != nil {
+		return err
+	}
+
+	geom, srid, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	switch g := s.g.(type) {
+	case nil:
+	case *orb.Point:
+		p, ok := geom.(orb.Point)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = p
+	case *orb.MultiPoint:
+		mp, ok := geom.(orb.MultiPoint)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = mp
+	case *orb.LineString:
+		ls, ok := geom.(orb.LineString)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = ls
+	case *orb.MultiLineString:
+		mls, ok := geom.(orb.MultiLineString)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = mls
+	case *orb.Ring:
+		p, ok := geom.(orb.Polygon)
+		if !ok || len(p) != 1 {
+			return ErrIncorrectGeometry
+		}
+		*g = p[0]
+		geom = p[0]
+	case *orb.Polygon:
+		p, ok := geom.(orb.Polygon)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = p
+	case *orb.MultiPolygon:
+		mp, ok := geom.(orb.MultiPolygon)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = mp
+	case *orb.Collection:
+		c, ok := geom.(orb.Collection)
+		if !ok {
+			return ErrIncorrectGeometry
+		}
+		*g = c
+	case *orb.Bound:
+		b := geom.Bound()
+		*g = b
+		geom = b
+	default:
+		return ErrIncorrectGeometry
+	}
+
+	s.Geometry = geom
+	s.SRID = srid
+	s.Valid = true
+	return nil
+}
+
+// decodeHex will convert hex encoded data to binary. go-pg returns data
+// as `\xhexencoded` and the PostGIS text protocol as plain hex. Binary
+// data always starts with a 0 or 1 byte, not the '0' character.
+func decodeHex(data []byte) ([]byte, error) {
+	if len(data) > 2 && data[0] == '\\' && data[1] == 'x' {
+		data = data[2:]
+	} else if len(data) == 0 || data[0] != '0' {
+		return data, nil
+	}
+
+	result := make([]byte, hex.DecodedLen(len(data)))
+	n, err := hex.Decode(result, data)
+	if err != nil {
+		return nil, fmt.Errorf("thought the data was hex, but it is not: %v", err)
+	}
+
+	return result[:n], nil
+}
+
+type value struct {
+	v    orb.Geometry
+	srid int
+}
+
+// Value will create a driver.Valuer that will EWKB the geometry
+// with the srid into the database query.
+func Value(g orb.Geometry, srid int) driver.Valuer {
+	return value{v: g, srid: srid}
+}
+
+func (v value) Value() (driver.Value, error) {
+	val, err := Marshal(v.v, v.srid)
+	if val == nil {
+		return nil, err
+	}
+	return val, err
+}
@@ -0,0 +1,123 @@
+package ewkb
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestScanner(t *testing.T) {
+	expected := orb.Point{1, 2}
+	cases := []struct {
+		name string
+		data interface{}
+	}{
+		{
+			name: "binary",
+			data: MustMarshal(expected, 4326),
+		},
+		{
+			name: "hex string",
+			data: "0101000020e6100000000000000000f03f0000000000000040",
+		},
+		{
+			name: "hex bytes",
+			data: []byte("0101000020E6100000000000000000F03F0000000000000040"),
+		},
+		{
+			name: "go-pg hex",
+			data: []byte(`\x0101000020e6100000000000000000f03f0000000000000040`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var p orb.Point
+			s := Scanner(&p)
+
+			err := s.Scan(tc.data)
+			if err != nil {
+				t.Fatalf("scan error: %v", err)
+			}
+
+			if !p.Equal(expected) {
+				t.Errorf("incorrect point: %v", p)
+			}
+
+			if !s.Valid || s.SRID != 4326 || !orb.Equal(s.Geometry, expected) {
+				t.Errorf("incorrect scanner: %v", s)
+			}
+		})
+	}
+}
+
+func TestScanner_nil(t *testing.T) {
+	s := Scanner(nil)
+	if err := s.Scan(nil); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if s.Valid {
+		t.Errorf("should not be valid")
+	}
+
+	err := s.Scan(MustMarshal(orb.LineString{{1, 2}, {3, 4}}, 3857))
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if _, ok := s.Geometry.(orb.LineString); !ok || s.SRID != 3857 {
+		t.Errorf("incorrect geometry: %v %v", s.Geometry, s.SRID)
+	}
+}
+
+func TestScanner_errors(t *testing.T) {
+	var p orb.Point
+	s := Scanner(&p)
+
+	if err := s.Scan(MustMarshal(orb.LineString{{1, 2}}, 4326)); err != ErrIncorrectGeometry {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if err := s.Scan(123); err != ErrUnsupportedDataType {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if err := s.Scan("0zz"); err == nil {
+		t.Errorf("expected hex error")
+	}
+}
+
+func TestScanner_ring(t *testing.T) {
+	var r orb.Ring
+	err := Scanner(&r).Scan(MustMarshal(orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}}, 4326))
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if len(r) != 4 {
+		t.Errorf("incorrect ring: %v", r)
+	}
+}
+
+func TestValue(t *testing.T) {
+	val, err := Value(orb.Point{1, 2}, 4326).Value()
+	if err != nil {
+		t.Fatalf("value error: %v", err)
+	}
+
+	var p orb.Point
+	s := Scanner(&p)
+	if err := s.Scan(val); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if !p.Equal(orb.Point{1, 2}) || s.SRID != 4326 {
+		t.Errorf("incorrect value: %v %v", p, s.SRID)
+	}
+
+	val, err = Value(orb.LineString(nil), 4326).Value()
+	if err != nil || val != nil {
+		t.Errorf("nil geometry should be nil value: %v %v", val, err)
+	}
+}
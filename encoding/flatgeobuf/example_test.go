@@ -0,0 +1,48 @@
+package flatgeobuf_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/flatgeobuf"
+	"github.com/paulmach/orb/geojson"
+)
+
+func ExampleReader_Search() {
+	fc := geojson.NewFeatureCollection()
+	for i := 0; i < 10; i++ {
+		f := geojson.NewFeature(orb.Point{float64(i), float64(i)})
+		f.Properties["id"] = i
+		fc.Append(f)
+	}
+
+	data, err := flatgeobuf.Marshal(fc)
+	if err != nil {
+		log.Fatalf("marshal error: %v", err)
+	}
+
+	r, err := flatgeobuf.NewReader(bytes.NewReader(data))
+	if err != nil {
+		log.Fatalf("reader error: %v", err)
+	}
+
+	// only the features in the bound are decoded,
+	// they are in file order which is the order of the index.
+	features, err := r.Search(orb.Bound{Min: orb.Point{2.5, 2.5}, Max: orb.Point{5, 5}})
+	if err != nil {
+		log.Fatalf("search error: %v", err)
+	}
+
+	fmt.Println(r.Header().FeaturesCount)
+	for _, f := range features {
+		fmt.Println(f.Properties["id"], f.Geometry)
+	}
+
+	// Output:
+	// 10
+	// 5 [5 5]
+	// 4 [4 4]
+	// 3 [3 3]
+}
@@ -0,0 +1,376 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The FlatGeobuf header and features are flatbuffers. Only the small subset
+// of the format needed for those schemas is implemented here.
+//
+// The buffers are written front to back, a table's vtable is written before
+// the table and the children after, so all the unsigned offsets point forward
+// as required. Alignment is relative to the start of the buffer, including the
+// size prefix, matching the official builders.
+
+// fbField is a field of a table, either an inline scalar or a child
+// object, like a string, vector or other table, stored by offset.
+type fbField struct {
+	id     int
+	scalar []byte
+	child  func(w *fbWriter) int
+}
+
+func scalarField(id int, size int, v uint64) fbField {
+	data := make([]byte, size)
+	switch size {
+	case 1:
+		data[0] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(data, uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(data, uint32(v))
+	case 8:
+		binary.LittleEndian.PutUint64(data, v)
+	}
+
+	return fbField{id: id, scalar: data}
+}
+
+func childField(id int, child func(w *fbWriter) int) fbField {
+	return fbField{id: id, child: child}
+}
+
+type fbWriter struct {
+	buf []byte
+}
+
+// newSizePrefixed starts a buffer with space for the size prefix
+// and the offset to the root table.
+func newSizePrefixed() *fbWriter {
+	return &fbWriter{buf: make([]byte, 8, 1024)}
+}
+
+// finish writes the root table and returns the size prefixed buffer.
+func (w *fbWriter) finish(root []fbField) []byte {
+	pos := w.table(root)
+	binary.LittleEndian.PutUint32(w.buf[4:], uint32(pos-4))
+	binary.LittleEndian.PutUint32(w.buf[0:], uint32(len(w.buf)-4))
+
+	return w.buf
+}
+
+// pad adds zeros so the next n bytes end at a multiple of align.
+func (w *fbWriter) pad(n, align int) {
+	for (len(w.buf)+n)%align != 0 {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *fbWriter) uint16(v uint16) {
+	w.buf = append(w.buf, 0, 0)
+	binary.LittleEndian.PutUint16(w.buf[len(w.buf)-2:], v)
+}
+
+func (w *fbWriter) uint32(v uint32) {
+	w.buf = append(w.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(w.buf[len(w.buf)-4:], v)
+}
+
+func (w *fbWriter) uint64(v uint64) {
+	w.buf = append(w.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(w.buf[len(w.buf)-8:], v)
+}
+
+// table writes the vtable, the table and then the children,
+// returning the position of the table.
+func (w *fbWriter) table(fields []fbField) int {
+	numFields := 0
+	for _, f := range fields {
+		if f.id+1 > numFields {
+			numFields = f.id + 1
+		}
+	}
+
+	// the table starts with the offset to the vtable and is aligned
+	// to 8 bytes so the scalar fields can be aligned relative to it.
+	offsets := make([]uint16, numFields)
+	size := 4
+	for i, f := range fields {
+		n := 4
+		if f.child == nil {
+			n = len(f.scalar)
+		}
+
+		for size%n != 0 {
+			size++
+		}
+
+		offsets[fields[i].id] = uint16(size)
+		size += n
+	}
+
+	w.pad(0, 2)
+	vtable := len(w.buf)
+	w.uint16(uint16(4 + 2*numFields))
+	w.uint16(uint16(size))
+	for _, o := range offsets {
+		w.uint16(o)
+	}
+
+	w.pad(0, 8)
+	pos := len(w.buf)
+	w.buf = append(w.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(w.buf[pos:], uint32(int32(pos-vtable)))
+
+	for _, f := range fields {
+		if f.child == nil {
+			copy(w.buf[pos+int(offsets[f.id]):], f.scalar)
+		}
+	}
+
+	for _, f := range fields {
+		if f.child == nil {
+			continue
+		}
+
+		at := pos + int(offsets[f.id])
+		child := f.child(w)
+		binary.LittleEndian.PutUint32(w.buf[at:], uint32(child-at))
+	}
+
+	return pos
+}
+
+func (w *fbWriter) string(s string) int {
+	w.pad(0, 4)
+	pos := len(w.buf)
+	w.uint32(uint32(len(s)))
+	w.buf = append(w.buf, s...)
+	w.buf = append(w.buf, 0)
+
+	return pos
+}
+
+func (w *fbWriter) bytes(data []byte) int {
+	w.pad(0, 4)
+	pos := len(w.buf)
+	w.uint32(uint32(len(data)))
+	w.buf = append(w.buf, data...)
+
+	return pos
+}
+
+func (w *fbWriter) uint32s(vals []uint32) int {
+	w.pad(0, 4)
+	pos := len(w.buf)
+	w.uint32(uint32(len(vals)))
+	for _, v := range vals {
+		w.uint32(v)
+	}
+
+	return pos
+}
+
+func (w *fbWriter) float64s(vals []float64) int {
+	// the values, after the length, must be 8 byte aligned
+	w.pad(4, 8)
+	pos := len(w.buf)
+	w.uint32(uint32(len(vals)))
+	for _, v := range vals {
+		w.uint64(math.Float64bits(v))
+	}
+
+	return pos
+}
+
+func (w *fbWriter) tables(tables [][]fbField) int {
+	w.pad(0, 4)
+	pos := len(w.buf)
+	w.uint32(uint32(len(tables)))
+	w.buf = append(w.buf, make([]byte, 4*len(tables))...)
+
+	for i, t := range tables {
+		at := pos + 4 + 4*i
+		child := w.table(t)
+		binary.LittleEndian.PutUint32(w.buf[at:], uint32(child-at))
+	}
+
+	return pos
+}
+
+// fbReader reads tables from a flatbuffer. Reads outside of the buffer
+// set the error and return zero values so the error can be checked once
+// at the end.
+type fbReader struct {
+	buf []byte
+	err error
+}
+
+func (r *fbReader) check(pos, n int) bool {
+	if r.err != nil {
+		return false
+	}
+
+	if pos < 0 || n < 0 || pos+n > len(r.buf) || pos+n < pos {
+		r.err = ErrInvalidData
+		return false
+	}
+
+	return true
+}
+
+func (r *fbReader) uint8(pos int) uint8 {
+	if !r.check(pos, 1) {
+		return 0
+	}
+	return r.buf[pos]
+}
+
+func (r *fbReader) uint16(pos int) uint16 {
+	if !r.check(pos, 2) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(r.buf[pos:])
+}
+
+func (r *fbReader) uint32(pos int) uint32 {
+	if !r.check(pos, 4) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.buf[pos:])
+}
+
+func (r *fbReader) uint64(pos int) uint64 {
+	if !r.check(pos, 8) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(r.buf[pos:])
+}
+
+// root returns the position of the root table.
+func (r *fbReader) root() int {
+	return int(r.uint32(0))
+}
+
+// field returns the position of the field of the table, or 0 if not present.
+func (r *fbReader) field(table, id int) int {
+	vtable := table - int(int32(r.uint32(table)))
+	size := int(r.uint16(vtable))
+
+	o := 4 + 2*id
+	if r.err != nil || o+2 > size {
+		return 0
+	}
+
+	off := int(r.uint16(vtable + o))
+	if off == 0 {
+		return 0
+	}
+
+	return table + off
+}
+
+// offset follows the offset stored at the field, 0 if the field is not present.
+func (r *fbReader) offset(table, id int) int {
+	pos := r.field(table, id)
+	if pos == 0 {
+		return 0
+	}
+
+	return pos + int(r.uint32(pos))
+}
+
+func (r *fbReader) uint8Field(table, id int, def uint8) uint8 {
+	if pos := r.field(table, id); pos != 0 {
+		return r.uint8(pos)
+	}
+	return def
+}
+
+func (r *fbReader) uint16Field(table, id int, def uint16) uint16 {
+	if pos := r.field(table, id); pos != 0 {
+		return r.uint16(pos)
+	}
+	return def
+}
+
+func (r *fbReader) uint64Field(table, id int, def uint64) uint64 {
+	if pos := r.field(table, id); pos != 0 {
+		return r.uint64(pos)
+	}
+	return def
+}
+
+// vector returns the position of the first element and the
+// length of the vector at the field.
+func (r *fbReader) vector(table, id, elemSize int) (int, int) {
+	pos := r.offset(table, id)
+	if pos == 0 {
+		return 0, 0
+	}
+
+	n := int(r.uint32(pos))
+	if !r.check(pos+4, n*elemSize) {
+		return 0, 0
+	}
+
+	return pos + 4, n
+}
+
+func (r *fbReader) stringField(table, id int) string {
+	return string(r.bytesField(table, id))
+}
+
+func (r *fbReader) bytesField(table, id int) []byte {
+	pos, n := r.vector(table, id, 1)
+	if n == 0 {
+		return nil
+	}
+
+	return r.buf[pos : pos+n]
+}
+
+func (r *fbReader) uint32sField(table, id int) []uint32 {
+	pos, n := r.vector(table, id, 4)
+	if n == 0 {
+		return nil
+	}
+
+	result := make([]uint32, n)
+	for i := range result {
+		result[i] = binary.LittleEndian.Uint32(r.buf[pos+4*i:])
+	}
+
+	return result
+}
+
+func (r *fbReader) float64sField(table, id int) []float64 {
+	pos, n := r.vector(table, id, 8)
+	if n == 0 {
+		return nil
+	}
+
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = math.Float64frombits(binary.LittleEndian.Uint64(r.buf[pos+8*i:]))
+	}
+
+	return result
+}
+
+// tablesField returns the positions of the tables in the vector.
+func (r *fbReader) tablesField(table, id int) []int {
+	pos, n := r.vector(table, id, 4)
+	if n == 0 {
+		return nil
+	}
+
+	result := make([]int, n)
+	for i := range result {
+		at := pos + 4*i
+		result[i] = at + int(binary.LittleEndian.Uint32(r.buf[at:]))
+	}
+
+	return result
+}
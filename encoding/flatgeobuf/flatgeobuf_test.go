@@ -0,0 +1,569 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestMarshal_roundTrip(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+
+	geoms := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}, {9, 10}}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}},
+		orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		orb.MultiPolygon{
+			{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			{{{2, 2}, {3, 2}, {3, 3}, {2, 2}}, {{2.1, 2.1}, {2.2, 2.1}, {2.2, 2.2}, {2.1, 2.1}}},
+		},
+		orb.Collection{orb.Point{1, 2}, orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}},
+	}
+
+	for i, g := range geoms {
+		f := geojson.NewFeature(g)
+		f.Properties["index"] = float64(i)
+		fc.Append(f)
+	}
+
+	f := geojson.NewFeature(nil)
+	f.Properties["name"] = "no geometry"
+	fc.Append(f)
+
+	data, err := Marshal(fc, Name("test"))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(result.Features) != len(fc.Features) {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	// features are written in index order, match them up by their properties
+	for _, r := range result.Features {
+		i, ok := r.Properties["index"].(float64)
+		if !ok {
+			if !reflect.DeepEqual(r.Properties, f.Properties) || r.Geometry != nil {
+				t.Errorf("incorrect feature without geometry: %v %v", r.Geometry, r.Properties)
+			}
+			continue
+		}
+
+		if !orb.Equal(r.Geometry, geoms[int(i)]) {
+			t.Errorf("%v: incorrect geometry: %v", i, r.Geometry)
+		}
+	}
+}
+
+func TestMarshal_header(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+	fc.Append(geojson.NewFeature(orb.Point{-3, 4}))
+	fc.Features[0].Properties["name"] = "a"
+	fc.Features[0].Properties["count"] = 1.0
+	fc.Features[1].Properties["count"] = 1.5
+	fc.Features[1].Properties["mixed"] = "b"
+	fc.Features[0].Properties["mixed"] = 2.0
+
+	data, err := Marshal(fc, Name("points"))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !bytes.Equal(data[:8], magic) {
+		t.Errorf("incorrect magic bytes: %v", data[:8])
+	}
+
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	expected := &Header{
+		Name:          "points",
+		Bound:         orb.Bound{Min: orb.Point{-3, 2}, Max: orb.Point{1, 4}},
+		GeometryType:  GeometryPoint,
+		FeaturesCount: 2,
+		IndexNodeSize: 16,
+		Columns: []Column{
+			{Name: "count", Type: ColumnDouble},
+			{Name: "mixed", Type: ColumnJSON},
+			{Name: "name", Type: ColumnString},
+		},
+	}
+
+	if !reflect.DeepEqual(r.Header(), expected) {
+		t.Errorf("incorrect header: %+v", r.Header())
+	}
+}
+
+func TestMarshal_properties(t *testing.T) {
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Properties["string"] = "value"
+	f.Properties["float"] = 1.5
+	f.Properties["int"] = 7
+	f.Properties["bool"] = true
+	f.Properties["json"] = map[string]interface{}{"a": []interface{}{1.0, "b"}}
+	f.Properties["binary"] = []byte{1, 2, 3}
+	f.Properties["null"] = nil
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(f)
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := geojson.Properties{
+		"string": "value",
+		"float":  1.5,
+		"int":    7.0,
+		"bool":   true,
+		"json":   map[string]interface{}{"a": []interface{}{1.0, "b"}},
+		"binary": []byte{1, 2, 3},
+	}
+
+	if !reflect.DeepEqual(result.Features[0].Properties, expected) {
+		t.Errorf("incorrect properties: %v", result.Features[0].Properties)
+	}
+}
+
+func TestWriter_columns(t *testing.T) {
+	columns := []Column{
+		{Name: "byte", Type: ColumnByte},
+		{Name: "ushort", Type: ColumnUShort},
+		{Name: "int", Type: ColumnInt},
+		{Name: "float", Type: ColumnFloat},
+		{Name: "date", Type: ColumnDateTime},
+	}
+
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Properties["byte"] = -3.0
+	f.Properties["ushort"] = 60000
+	f.Properties["int"] = -100000.0
+	f.Properties["float"] = 0.5
+	f.Properties["date"] = "2020-01-02T03:04:05Z"
+	f.Properties["other"] = "not written"
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, Columns(columns...))
+	if err := w.Write(f); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	result, err := Unmarshal(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := geojson.Properties{
+		"byte":   -3.0,
+		"ushort": 60000.0,
+		"int":    -100000.0,
+		"float":  0.5,
+		"date":   "2020-01-02T03:04:05Z",
+	}
+
+	if !reflect.DeepEqual(result.Features[0].Properties, expected) {
+		t.Errorf("incorrect properties: %v", result.Features[0].Properties)
+	}
+
+	// values must match the column type
+	f.Properties["int"] = "seven"
+	err = NewWriter(&bytes.Buffer{}, Columns(columns...)).Write(f)
+	if err != ErrInvalidProperty {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestWriter_noIndex(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, IndexNodeSize(0))
+
+	for i := 0; i < 3; i++ {
+		f := geojson.NewFeature(orb.Point{float64(i), float64(i)})
+		f.Properties["i"] = float64(i)
+		if i == 2 {
+			f.Properties["later"] = "not in the header"
+		}
+
+		if err := w.Write(f); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		// features are written as they are received
+		if buf.Len() == 0 {
+			t.Errorf("should write the feature")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	h := r.Header()
+	if h.IndexNodeSize != 0 || h.FeaturesCount != 0 || h.GeometryType != GeometryUnknown {
+		t.Errorf("incorrect header: %+v", h)
+	}
+
+	fs, err := r.Search(orb.Bound{Min: orb.Point{0.5, 0.5}, Max: orb.Point{3, 3}})
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+
+	if len(fs) != 2 {
+		t.Fatalf("incorrect number of features: %d", len(fs))
+	}
+
+	for i, f := range fs {
+		expected := geojson.Properties{"i": float64(i + 1)}
+		if !reflect.DeepEqual(f.Properties, expected) {
+			t.Errorf("incorrect properties: %v", f.Properties)
+		}
+
+		if !orb.Equal(f.Geometry, orb.Point{float64(i + 1), float64(i + 1)}) {
+			t.Errorf("incorrect geometry: %v", f.Geometry)
+		}
+	}
+}
+
+// onlyReader hides the io.Seeker of the reader.
+type onlyReader struct {
+	io.Reader
+}
+
+func TestReader_Search(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			f := geojson.NewFeature(orb.Point{float64(x), float64(y)})
+			f.Properties["x"] = float64(x)
+			f.Properties["y"] = float64(y)
+			fc.Append(f)
+		}
+	}
+
+	// a line that crosses the query bound
+	line := geojson.NewFeature(orb.LineString{{-10, 5.5}, {40, 5.5}})
+	line.Properties["line"] = true
+	fc.Append(line)
+
+	data, err := Marshal(fc, IndexNodeSize(4))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	bound := orb.Bound{Min: orb.Point{4.5, 5}, Max: orb.Point{7, 6}}
+	expected := []string{"5 5", "5 6", "6 5", "6 6", "7 5", "7 6", "line"}
+
+	readers := map[string]func() io.Reader{
+		"seeker": func() io.Reader { return bytes.NewReader(data) },
+		"reader": func() io.Reader { return onlyReader{bytes.NewReader(data)} },
+	}
+
+	for name, reader := range readers {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewReader(reader())
+			if err != nil {
+				t.Fatalf("reader error: %v", err)
+			}
+
+			fs, err := r.Search(bound)
+			if err != nil {
+				t.Fatalf("search error: %v", err)
+			}
+
+			var result []string
+			for _, f := range fs {
+				if f.Properties["line"] == true {
+					result = append(result, "line")
+				} else {
+					result = append(result, fmt.Sprintf("%v %v", f.Properties["x"], f.Properties["y"]))
+				}
+			}
+			sort.Strings(result)
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("incorrect features: %v", result)
+			}
+
+			// nothing there
+			r, _ = NewReader(reader())
+			fs, err = r.Search(orb.Bound{Min: orb.Point{100, 100}, Max: orb.Point{101, 101}})
+			if err != nil || len(fs) != 0 {
+				t.Errorf("should find nothing: %v %v", fs, err)
+			}
+		})
+	}
+}
+
+func TestReader_afterNext(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Point{1, 2}))
+	fc.Append(geojson.NewFeature(orb.Point{3, 4}))
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("next error: %v", err)
+	}
+
+	_, err = r.Search(orb.Bound{Max: orb.Point{10, 10}})
+	if err != ErrInvalidData {
+		t.Errorf("search after next should error: %v", err)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{1, 2}, {3, 4}}))
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if _, err := Unmarshal([]byte("not flatgeobuf")); err != ErrNotFlatGeobuf {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if _, err := Unmarshal(data[:len(data)-10]); err != io.ErrUnexpectedEOF {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// break the feature table offset
+	broken := append([]byte(nil), data...)
+	for i := len(data) - 60; i < len(data); i++ {
+		broken[i] = 0xff
+	}
+
+	if _, err := Unmarshal(broken); err == nil {
+		t.Errorf("should error on invalid feature")
+	}
+}
+
+func TestLevelBounds(t *testing.T) {
+	cases := []struct {
+		items    uint64
+		nodeSize uint16
+		expected [][2]uint64
+	}{
+		{items: 1, nodeSize: 16, expected: [][2]uint64{{1, 2}, {0, 1}}},
+		{items: 16, nodeSize: 16, expected: [][2]uint64{{1, 17}, {0, 1}}},
+		{items: 100, nodeSize: 16, expected: [][2]uint64{{8, 108}, {1, 8}, {0, 1}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%d", tc.items), func(t *testing.T) {
+			v := levelBounds(tc.items, tc.nodeSize)
+			if !reflect.DeepEqual(v, tc.expected) {
+				t.Errorf("incorrect bounds: %v", v)
+			}
+		})
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	if v := hilbert(0, 0); v != 0 {
+		t.Errorf("origin should be 0: %v", v)
+	}
+
+	// the curve starts at the origin so the 4x4 corner is the first 16
+	// values, and consecutive values are neighboring cells.
+	cells := make(map[uint32][2]int)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			cells[hilbert(uint32(x), uint32(y))] = [2]int{x, y}
+		}
+	}
+
+	for i := uint32(0); i < 16; i++ {
+		c, ok := cells[i]
+		if !ok {
+			t.Fatalf("missing value %d: %v", i, cells)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		p := cells[i-1]
+		if d := abs(c[0]-p[0]) + abs(c[1]-p[1]); d != 1 {
+			t.Errorf("%d and %d are not neighbors: %v %v", i-1, i, p, c)
+		}
+	}
+}
+
+func TestMarshal_allGeometries(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		t.Run(fmt.Sprintf("%T", g), func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			fc.Append(geojson.NewFeature(g))
+
+			data, err := Marshal(fc)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if _, err := Unmarshal(data); err != nil {
+				t.Errorf("unmarshal error: %v", err)
+			}
+		})
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func TestUnmarshal_fixtures(t *testing.T) {
+	for _, name := range []string{"polygons.fgb", "polygons_noindex.fgb"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile("testdata/" + name)
+			if err != nil {
+				t.Fatalf("unable to read file: %v", err)
+			}
+
+			r, err := NewReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("reader error: %v", err)
+			}
+
+			h := r.Header()
+			expected := &Header{
+				Name:          "polygons",
+				Bound:         orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{48, 38}},
+				GeometryType:  GeometryPolygon,
+				Columns:       []Column{{"name", ColumnString}, {"id", ColumnLong}, {"area", ColumnDouble}, {"even", ColumnBool}},
+				FeaturesCount: 20,
+				IndexNodeSize: 16,
+			}
+			if name == "polygons_noindex.fgb" {
+				expected.IndexNodeSize = 0
+			}
+
+			if !reflect.DeepEqual(h, expected) {
+				t.Errorf("incorrect header: %+v", h)
+			}
+
+			fc, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if len(fc.Features) != 20 {
+				t.Fatalf("incorrect number of features: %d", len(fc.Features))
+			}
+
+			for _, f := range fc.Features {
+				i := int(f.Properties.MustFloat64("id") - 1000)
+				if !orb.Equal(f.Geometry, fixturePolygon(i)) {
+					t.Errorf("%d: incorrect geometry: %v", i, f.Geometry)
+				}
+
+				expected := geojson.Properties{
+					"id":   float64(1000 + i),
+					"area": 64.0,
+					"even": i%2 == 0,
+				}
+				if i%3 == 0 {
+					expected["area"] = 48.0
+				}
+				if i != 19 {
+					expected["name"] = "feature " + string(rune('a'+i))
+				}
+
+				if !reflect.DeepEqual(f.Properties, expected) {
+					t.Errorf("%d: incorrect properties: %v", i, f.Properties)
+				}
+			}
+		})
+	}
+}
+
+func TestReader_Search_fixture(t *testing.T) {
+	for _, name := range []string{"polygons.fgb", "polygons_noindex.fgb"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open("testdata/" + name)
+			if err != nil {
+				t.Fatalf("unable to open file: %v", err)
+			}
+			defer f.Close()
+
+			r, err := NewReader(f)
+			if err != nil {
+				t.Fatalf("reader error: %v", err)
+			}
+
+			features, err := r.Search(orb.Bound{Min: orb.Point{9, 7}, Max: orb.Point{21, 11}})
+			if err != nil {
+				t.Fatalf("search error: %v", err)
+			}
+
+			var ids []int
+			for _, f := range features {
+				ids = append(ids, int(f.Properties.MustFloat64("id")))
+			}
+			sort.Ints(ids)
+
+			if !reflect.DeepEqual(ids, []int{1001, 1002, 1006, 1007}) {
+				t.Errorf("incorrect features: %v", ids)
+			}
+		})
+	}
+}
+
+// fixturePolygon returns the geometry of the features in testdata,
+// see testdata/generate.
+func fixturePolygon(i int) orb.Polygon {
+	x, y := float64(10*(i%5)), float64(10*(i/5))
+
+	p := orb.Polygon{{{x, y}, {x + 8, y}, {x + 8, y + 8}, {x, y + 8}, {x, y}}}
+	if i%3 == 0 {
+		p = append(p, orb.Ring{{x + 2, y + 2}, {x + 2, y + 6}, {x + 6, y + 6}, {x + 6, y + 2}, {x + 2, y + 2}})
+	}
+
+	return p
+}
@@ -0,0 +1,209 @@
+package flatgeobuf
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+// geometry table field ids
+const (
+	geometryEnds  = 0
+	geometryXY    = 1
+	geometryType  = 6
+	geometryParts = 7
+)
+
+func geometryTypeOf(g orb.Geometry) GeometryType {
+	switch g.(type) {
+	case orb.Point:
+		return GeometryPoint
+	case orb.MultiPoint:
+		return GeometryMultiPoint
+	case orb.LineString:
+		return GeometryLineString
+	case orb.MultiLineString:
+		return GeometryMultiLineString
+	case orb.Ring, orb.Polygon, orb.Bound:
+		return GeometryPolygon
+	case orb.MultiPolygon:
+		return GeometryMultiPolygon
+	case orb.Collection:
+		return GeometryCollection
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// geometryFields returns the fields of the geometry table. The xy values
+// are flat, the ends are the point index of the end of each line or ring,
+// only needed if there is more than one. Multi polygons and collections
+// have a part for each polygon or geometry.
+func geometryFields(g orb.Geometry) []fbField {
+	var (
+		xy    []float64
+		ends  []uint32
+		parts []orb.Geometry
+	)
+
+	appendPoints := func(ps []orb.Point) {
+		for _, p := range ps {
+			xy = append(xy, p[0], p[1])
+		}
+		ends = append(ends, uint32(len(xy)/2))
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		appendPoints([]orb.Point{g})
+	case orb.MultiPoint:
+		appendPoints(g)
+	case orb.LineString:
+		appendPoints(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			appendPoints(ls)
+		}
+	case orb.Ring:
+		appendPoints(g)
+	case orb.Polygon:
+		for _, r := range g {
+			appendPoints(r)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			parts = append(parts, p)
+		}
+	case orb.Collection:
+		parts = g
+	case orb.Bound:
+		return geometryFields(g.ToPolygon())
+	}
+
+	fields := []fbField{}
+	if len(ends) > 1 {
+		fields = append(fields, childField(geometryEnds, func(w *fbWriter) int {
+			return w.uint32s(ends)
+		}))
+	}
+
+	if len(xy) > 0 {
+		fields = append(fields, childField(geometryXY, func(w *fbWriter) int {
+			return w.float64s(xy)
+		}))
+	}
+
+	fields = append(fields, scalarField(geometryType, 1, uint64(geometryTypeOf(g))))
+
+	if parts != nil {
+		fields = append(fields, childField(geometryParts, func(w *fbWriter) int {
+			tables := make([][]fbField, 0, len(parts))
+			for _, p := range parts {
+				tables = append(tables, geometryFields(p))
+			}
+			return w.tables(tables)
+		}))
+	}
+
+	return fields
+}
+
+// readGeometry reads the geometry table. The type is from the
+// header unless it is unknown.
+func readGeometry(r *fbReader, table int, typ GeometryType) (orb.Geometry, error) {
+	if typ == GeometryUnknown {
+		typ = GeometryType(r.uint8Field(table, geometryType, 0))
+	}
+
+	switch typ {
+	case GeometryMultiPolygon:
+		parts := r.tablesField(table, geometryParts)
+		mp := make(orb.MultiPolygon, 0, len(parts))
+		for _, part := range parts {
+			g, err := readGeometry(r, part, GeometryPolygon)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, g.(orb.Polygon))
+		}
+
+		return mp, r.err
+	case GeometryCollection:
+		parts := r.tablesField(table, geometryParts)
+		c := make(orb.Collection, 0, len(parts))
+		for _, part := range parts {
+			g, err := readGeometry(r, part, GeometryUnknown)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, g)
+		}
+
+		return c, r.err
+	}
+
+	xy := r.float64sField(table, geometryXY)
+	ends := r.uint32sField(table, geometryEnds)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	points := make([]orb.Point, len(xy)/2)
+	for i := range points {
+		points[i] = orb.Point{xy[2*i], xy[2*i+1]}
+	}
+
+	lines, err := split(points, ends)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case GeometryPoint:
+		if len(points) == 0 {
+			return orb.Point{}, nil
+		}
+		return points[0], nil
+	case GeometryMultiPoint:
+		return orb.MultiPoint(points), nil
+	case GeometryLineString:
+		return orb.LineString(points), nil
+	case GeometryMultiLineString:
+		mls := make(orb.MultiLineString, 0, len(lines))
+		for _, ls := range lines {
+			mls = append(mls, orb.LineString(ls))
+		}
+		return mls, nil
+	case GeometryPolygon:
+		p := make(orb.Polygon, 0, len(lines))
+		for _, r := range lines {
+			p = append(p, orb.Ring(r))
+		}
+		return p, nil
+	}
+
+	return nil, ErrUnsupportedGeometry
+}
+
+// split splits the points using the ends. No ends means one part.
+func split(points []orb.Point, ends []uint32) ([][]orb.Point, error) {
+	if len(ends) == 0 {
+		if len(points) == 0 {
+			return nil, nil
+		}
+		return [][]orb.Point{points}, nil
+	}
+
+	result := make([][]orb.Point, 0, len(ends))
+	start := uint32(0)
+	for _, end := range ends {
+		if end < start || int(end) > len(points) {
+			return nil, ErrInvalidData
+		}
+
+		result = append(result, points[start:end])
+		start = end
+	}
+
+	return result, nil
+}
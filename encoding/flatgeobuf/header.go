@@ -0,0 +1,182 @@
+package flatgeobuf
+
+import (
+	"errors"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	// ErrNotFlatGeobuf is returned when the data does not start with the
+	// FlatGeobuf magic bytes or is a different major version.
+	ErrNotFlatGeobuf = errors.New("flatgeobuf: not flatgeobuf data")
+
+	// ErrInvalidData is returned when the header or a feature can not be read.
+	ErrInvalidData = errors.New("flatgeobuf: invalid data")
+
+	// ErrUnsupportedGeometry is returned when reading a geometry type,
+	// like a curve, that is not supported by this package.
+	ErrUnsupportedGeometry = errors.New("flatgeobuf: unsupported geometry")
+
+	// ErrInvalidProperty is returned when a property value can not be
+	// encoded as the type of its column.
+	ErrInvalidProperty = errors.New("flatgeobuf: property does not match column type")
+)
+
+// magic is the start of every file, "fgb", the major version 3, "fgb", and the patch version.
+var magic = []byte{0x66, 0x67, 0x62, 0x03, 0x66, 0x67, 0x62, 0x00}
+
+// GeometryType is the type of the geometries in the file.
+type GeometryType uint8
+
+// The supported geometry types. Unknown means the file has mixed types.
+const (
+	GeometryUnknown GeometryType = iota
+	GeometryPoint
+	GeometryLineString
+	GeometryPolygon
+	GeometryMultiPoint
+	GeometryMultiLineString
+	GeometryMultiPolygon
+	GeometryCollection
+)
+
+// ColumnType is the type of the values of a property column.
+type ColumnType uint8
+
+// The column types as defined by the spec.
+const (
+	ColumnByte ColumnType = iota
+	ColumnUByte
+	ColumnBool
+	ColumnShort
+	ColumnUShort
+	ColumnInt
+	ColumnUInt
+	ColumnLong
+	ColumnULong
+	ColumnFloat
+	ColumnDouble
+	ColumnString
+	ColumnJSON
+	ColumnDateTime
+	ColumnBinary
+)
+
+// A Column describes a feature property.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// A Header describes the contents of the file.
+type Header struct {
+	Name string
+
+	// Bound is the envelope of all the features.
+	Bound orb.Bound
+
+	// GeometryType is unknown if the features have different geometry types.
+	GeometryType GeometryType
+
+	Columns []Column
+
+	// FeaturesCount is 0 if unknown.
+	FeaturesCount uint64
+
+	// IndexNodeSize is the node size of the packed Hilbert R-tree,
+	// 0 if there is no index.
+	IndexNodeSize uint16
+}
+
+// header table field ids
+const (
+	headerName          = 0
+	headerEnvelope      = 1
+	headerGeometryType  = 2
+	headerColumns       = 7
+	headerFeaturesCount = 8
+	headerIndexNodeSize = 9
+)
+
+// column table field ids
+const (
+	columnName = 0
+	columnType = 1
+)
+
+func (h *Header) marshal() []byte {
+	w := newSizePrefixed()
+
+	var fields []fbField
+	if h.Name != "" {
+		fields = append(fields, childField(headerName, func(w *fbWriter) int {
+			return w.string(h.Name)
+		}))
+	}
+
+	if h.FeaturesCount > 0 {
+		b := h.Bound
+		fields = append(fields, childField(headerEnvelope, func(w *fbWriter) int {
+			return w.float64s([]float64{b.Min[0], b.Min[1], b.Max[0], b.Max[1]})
+		}))
+	}
+
+	fields = append(fields, scalarField(headerGeometryType, 1, uint64(h.GeometryType)))
+
+	if len(h.Columns) > 0 {
+		fields = append(fields, childField(headerColumns, func(w *fbWriter) int {
+			columns := make([][]fbField, 0, len(h.Columns))
+			for _, c := range h.Columns {
+				name := c.Name
+				columns = append(columns, []fbField{
+					childField(columnName, func(w *fbWriter) int {
+						return w.string(name)
+					}),
+					scalarField(columnType, 1, uint64(c.Type)),
+				})
+			}
+
+			return w.tables(columns)
+		}))
+	}
+
+	fields = append(fields,
+		scalarField(headerFeaturesCount, 8, h.FeaturesCount),
+		scalarField(headerIndexNodeSize, 2, uint64(h.IndexNodeSize)),
+	)
+
+	return w.finish(fields)
+}
+
+func unmarshalHeader(data []byte) (*Header, error) {
+	r := &fbReader{buf: data}
+	root := r.root()
+
+	h := &Header{
+		Name:          r.stringField(root, headerName),
+		GeometryType:  GeometryType(r.uint8Field(root, headerGeometryType, 0)),
+		FeaturesCount: r.uint64Field(root, headerFeaturesCount, 0),
+		IndexNodeSize: r.uint16Field(root, headerIndexNodeSize, 16),
+	}
+
+	if env := r.float64sField(root, headerEnvelope); len(env) >= 4 {
+		h.Bound = orb.Bound{
+			Min: orb.Point{env[0], env[1]},
+			Max: orb.Point{env[2], env[3]},
+		}
+	}
+
+	for _, c := range r.tablesField(root, headerColumns) {
+		h.Columns = append(h.Columns, Column{
+			Name: r.stringField(c, columnName),
+			Type: ColumnType(r.uint8Field(c, columnType, 0)),
+		})
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return h, nil
+}
@@ -0,0 +1,254 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// DefaultIndexNodeSize is the default number of children of each index node.
+const DefaultIndexNodeSize = 16
+
+// nodeItemSize is the encoded size of an index node,
+// the bound as 4 float64s and the offset as a uint64.
+const nodeItemSize = 40
+
+const hilbertMax = (1 << 16) - 1
+
+// nodeItem is a node of the packed R-tree. For leaf nodes the offset is the
+// byte offset of the feature from the start of the features, for the other
+// nodes it is the index of the first child node.
+type nodeItem struct {
+	bound  orb.Bound
+	offset uint64
+}
+
+// emptyBound is used for features without a geometry,
+// it does not intersect anything.
+var emptyBound = orb.Bound{
+	Min: orb.Point{math.Inf(1), math.Inf(1)},
+	Max: orb.Point{math.Inf(-1), math.Inf(-1)},
+}
+
+func extend(a, b orb.Bound) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{math.Min(a.Min[0], b.Min[0]), math.Min(a.Min[1], b.Min[1])},
+		Max: orb.Point{math.Max(a.Max[0], b.Max[0]), math.Max(a.Max[1], b.Max[1])},
+	}
+}
+
+func intersects(a, b orb.Bound) bool {
+	return a.Max[0] >= b.Min[0] && a.Min[0] <= b.Max[0] &&
+		a.Max[1] >= b.Min[1] && a.Min[1] <= b.Max[1]
+}
+
+// levelBounds returns the start and end node index of each level of the
+// tree, leaves first. The root is node 0 and the leaves are at the end.
+func levelBounds(numItems uint64, nodeSize uint16) [][2]uint64 {
+	n := numItems
+	numNodes := n
+	levelNumNodes := []uint64{n}
+	for {
+		n = (n + uint64(nodeSize) - 1) / uint64(nodeSize)
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+		if n == 1 {
+			break
+		}
+	}
+
+	bounds := make([][2]uint64, 0, len(levelNumNodes))
+	n = numNodes
+	for _, size := range levelNumNodes {
+		bounds = append(bounds, [2]uint64{n - size, n})
+		n -= size
+	}
+
+	return bounds
+}
+
+// indexSize returns the number of bytes of the index.
+func indexSize(numItems uint64, nodeSize uint16) uint64 {
+	if numItems == 0 || nodeSize < 2 {
+		return 0
+	}
+
+	bounds := levelBounds(numItems, nodeSize)
+	return bounds[0][1] * nodeItemSize
+}
+
+// hilbertSort sorts the items by the hilbert value of the center
+// of their bound within the extent.
+func hilbertSort(items []*pending, extent orb.Bound) {
+	width := extent.Max[0] - extent.Min[0]
+	height := extent.Max[1] - extent.Min[1]
+
+	values := make(map[*pending]uint32, len(items))
+	for _, item := range items {
+		if item.bound == emptyBound {
+			continue
+		}
+
+		var x, y uint32
+		if width > 0 {
+			x = uint32(math.Floor(hilbertMax * ((item.bound.Min[0]+item.bound.Max[0])/2 - extent.Min[0]) / width))
+		}
+		if height > 0 {
+			y = uint32(math.Floor(hilbertMax * ((item.bound.Min[1]+item.bound.Max[1])/2 - extent.Min[1]) / height))
+		}
+
+		values[item] = hilbert(x, y)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return values[items[i]] > values[items[j]]
+	})
+}
+
+// hilbert returns the index of the point along the hilbert curve.
+// Based on the public domain code at https://github.com/rawrunprotected/hilbert_curves
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
+
+// buildIndex returns the encoded packed R-tree for the leaf nodes,
+// which must already be in hilbert order.
+func buildIndex(leaves []nodeItem, nodeSize uint16) []byte {
+	bounds := levelBounds(uint64(len(leaves)), nodeSize)
+	nodes := make([]nodeItem, bounds[0][1])
+	copy(nodes[bounds[0][0]:], leaves)
+
+	for i := 0; i < len(bounds)-1; i++ {
+		pos, end := bounds[i][0], bounds[i][1]
+		parent := bounds[i+1][0]
+		for pos < end {
+			node := nodeItem{bound: emptyBound, offset: pos}
+			for j := 0; j < int(nodeSize) && pos < end; j++ {
+				node.bound = extend(node.bound, nodes[pos].bound)
+				pos++
+			}
+
+			nodes[parent] = node
+			parent++
+		}
+	}
+
+	data := make([]byte, len(nodes)*nodeItemSize)
+	for i, n := range nodes {
+		b := data[i*nodeItemSize:]
+		binary.LittleEndian.PutUint64(b[0:], math.Float64bits(n.bound.Min[0]))
+		binary.LittleEndian.PutUint64(b[8:], math.Float64bits(n.bound.Min[1]))
+		binary.LittleEndian.PutUint64(b[16:], math.Float64bits(n.bound.Max[0]))
+		binary.LittleEndian.PutUint64(b[24:], math.Float64bits(n.bound.Max[1]))
+		binary.LittleEndian.PutUint64(b[32:], n.offset)
+	}
+
+	return data
+}
+
+func readNode(index []byte, i uint64) nodeItem {
+	b := index[i*nodeItemSize:]
+	return nodeItem{
+		bound: orb.Bound{
+			Min: orb.Point{
+				math.Float64frombits(binary.LittleEndian.Uint64(b[0:])),
+				math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+			},
+			Max: orb.Point{
+				math.Float64frombits(binary.LittleEndian.Uint64(b[16:])),
+				math.Float64frombits(binary.LittleEndian.Uint64(b[24:])),
+			},
+		},
+		offset: binary.LittleEndian.Uint64(b[32:]),
+	}
+}
+
+// searchIndex returns the byte offsets, in increasing order, of the
+// features whose bound intersects the query bound.
+func searchIndex(index []byte, numItems uint64, nodeSize uint16, b orb.Bound) ([]uint64, error) {
+	bounds := levelBounds(numItems, nodeSize)
+	numNodes := bounds[0][1]
+	leavesStart := numNodes - numItems
+
+	type entry struct {
+		node  uint64
+		level int
+	}
+
+	var result []uint64
+	queue := []entry{{node: 0, level: len(bounds) - 1}}
+	for len(queue) > 0 {
+		e := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		isLeaf := e.node >= leavesStart
+		end := e.node + uint64(nodeSize)
+		if levelEnd := bounds[e.level][1]; end > levelEnd {
+			end = levelEnd
+		}
+
+		for pos := e.node; pos < end; pos++ {
+			n := readNode(index, pos)
+			if !intersects(b, n.bound) {
+				continue
+			}
+
+			if isLeaf {
+				result = append(result, n.offset)
+				continue
+			}
+
+			if e.level == 0 || n.offset >= numNodes {
+				return nil, ErrInvalidData
+			}
+			queue = append(queue, entry{node: n.offset, level: e.level - 1})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package flatgeobuf
+
+type options struct {
+	name      string
+	nodeSize  uint16
+	columns   []Column
+	hasSchema bool
+}
+
+// An Option is a possible parameter when writing FlatGeobuf.
+type Option func(*options)
+
+// Name sets the name of the dataset in the header.
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// IndexNodeSize sets the number of children of each node of the spatial
+// index. Default is 16, 0 disables the index so features can be written as
+// they are received instead of being held until the writer is closed.
+func IndexNodeSize(n uint16) Option {
+	return func(o *options) {
+		if n == 1 {
+			n = 2
+		}
+		o.nodeSize = n
+	}
+}
+
+// Columns sets the property columns. Only these properties are written
+// and the values must match the column type. By default the columns are
+// inferred from the properties, see Writer.
+func Columns(columns ...Column) Option {
+	return func(o *options) {
+		o.columns = columns
+		o.hasSchema = true
+	}
+}
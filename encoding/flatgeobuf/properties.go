@@ -0,0 +1,310 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/paulmach/orb/geojson"
+)
+
+// inferType returns the column type for the value, false for nil.
+func inferType(v interface{}) (ColumnType, bool) {
+	switch v.(type) {
+	case nil:
+		return 0, false
+	case bool:
+		return ColumnBool, true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ColumnLong, true
+	case float32, float64:
+		return ColumnDouble, true
+	case string:
+		return ColumnString, true
+	case time.Time:
+		return ColumnDateTime, true
+	case []byte:
+		return ColumnBinary, true
+	}
+
+	return ColumnJSON, true
+}
+
+// mergeType returns the column type that can hold values of both types.
+func mergeType(a, b ColumnType) ColumnType {
+	if a == b {
+		return a
+	}
+
+	if (a == ColumnLong || a == ColumnDouble) && (b == ColumnLong || b == ColumnDouble) {
+		return ColumnDouble
+	}
+
+	return ColumnJSON
+}
+
+// encodeProperties encodes the values as the column index, uint16,
+// followed by the value. Nil values and properties without a column are skipped.
+func encodeProperties(props geojson.Properties, columns []Column) ([]byte, error) {
+	var buf []byte
+	for i, c := range columns {
+		v, ok := props[c.Name]
+		if !ok || v == nil {
+			continue
+		}
+
+		buf = appendUint(buf, 2, uint64(i))
+
+		var err error
+		buf, err = appendValue(buf, c.Type, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func appendUint(buf []byte, size int, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:size]...)
+}
+
+func appendBytes(buf []byte, data []byte) []byte {
+	buf = appendUint(buf, 4, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func appendValue(buf []byte, t ColumnType, v interface{}) ([]byte, error) {
+	switch t {
+	case ColumnBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case ColumnByte, ColumnUByte, ColumnShort, ColumnUShort, ColumnInt, ColumnUInt, ColumnLong, ColumnULong:
+		i, ok := toInt(v)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		return appendUint(buf, columnSize(t), uint64(i)), nil
+	case ColumnFloat:
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		return appendUint(buf, 4, uint64(math.Float32bits(float32(f)))), nil
+	case ColumnDouble:
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		return appendUint(buf, 8, math.Float64bits(f)), nil
+	case ColumnString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		return appendBytes(buf, []byte(s)), nil
+	case ColumnDateTime:
+		switch v := v.(type) {
+		case string:
+			return appendBytes(buf, []byte(v)), nil
+		case time.Time:
+			return appendBytes(buf, []byte(v.Format(time.RFC3339Nano))), nil
+		}
+
+		return nil, ErrInvalidProperty
+	case ColumnJSON:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return appendBytes(buf, data), nil
+	case ColumnBinary:
+		data, ok := v.([]byte)
+		if !ok {
+			return nil, ErrInvalidProperty
+		}
+
+		return appendBytes(buf, data), nil
+	}
+
+	return nil, ErrInvalidProperty
+}
+
+// columnSize returns the size of fixed size values, 0 for the
+// variable length types that are prefixed by a uint32 length.
+func columnSize(t ColumnType) int {
+	switch t {
+	case ColumnByte, ColumnUByte, ColumnBool:
+		return 1
+	case ColumnShort, ColumnUShort:
+		return 2
+	case ColumnInt, ColumnUInt, ColumnFloat:
+		return 4
+	case ColumnLong, ColumnULong, ColumnDouble:
+		return 8
+	}
+
+	return 0
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case float32:
+		if float32(int64(v)) == v {
+			return int64(v), true
+		}
+	case float64:
+		// values from json decoding are all float64
+		if float64(int64(v)) == v {
+			return int64(v), true
+		}
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+
+	i, ok := toInt(v)
+	return float64(i), ok
+}
+
+// decodeProperties decodes the values into properties. Numbers are
+// returned as float64, like values decoded from GeoJSON.
+func decodeProperties(data []byte, columns []Column) (geojson.Properties, error) {
+	props := make(geojson.Properties)
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, ErrInvalidData
+		}
+
+		i := int(binary.LittleEndian.Uint16(data))
+		data = data[2:]
+		if i >= len(columns) {
+			return nil, ErrInvalidData
+		}
+
+		c := columns[i]
+		size := columnSize(c.Type)
+		if size == 0 {
+			if len(data) < 4 {
+				return nil, ErrInvalidData
+			}
+
+			n := binary.LittleEndian.Uint32(data)
+			data = data[4:]
+			if uint64(n) > uint64(len(data)) {
+				return nil, ErrInvalidData
+			}
+
+			v, err := decodeVariable(c.Type, data[:n])
+			if err != nil {
+				return nil, err
+			}
+
+			props[c.Name] = v
+			data = data[n:]
+			continue
+		}
+
+		if len(data) < size {
+			return nil, ErrInvalidData
+		}
+
+		props[c.Name] = decodeFixed(c.Type, data[:size])
+		data = data[size:]
+	}
+
+	return props, nil
+}
+
+func decodeFixed(t ColumnType, data []byte) interface{} {
+	switch t {
+	case ColumnByte:
+		return float64(int8(data[0]))
+	case ColumnUByte:
+		return float64(data[0])
+	case ColumnBool:
+		return data[0] != 0
+	case ColumnShort:
+		return float64(int16(binary.LittleEndian.Uint16(data)))
+	case ColumnUShort:
+		return float64(binary.LittleEndian.Uint16(data))
+	case ColumnInt:
+		return float64(int32(binary.LittleEndian.Uint32(data)))
+	case ColumnUInt:
+		return float64(binary.LittleEndian.Uint32(data))
+	case ColumnLong:
+		return float64(int64(binary.LittleEndian.Uint64(data)))
+	case ColumnULong:
+		return float64(binary.LittleEndian.Uint64(data))
+	case ColumnFloat:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(data))
+}
+
+func decodeVariable(t ColumnType, data []byte) (interface{}, error) {
+	switch t {
+	case ColumnJSON:
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case ColumnBinary:
+		return append([]byte(nil), data...), nil
+	case ColumnString, ColumnDateTime:
+		return string(data), nil
+	}
+
+	return nil, ErrInvalidData
+}
@@ -0,0 +1,252 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// maxHeaderSize is the limit on the header size to protect against
+// allocating huge buffers when reading invalid data.
+const maxHeaderSize = 10 * 1024 * 1024
+
+// Unmarshal decodes all the features.
+func Unmarshal(data []byte) (*geojson.FeatureCollection, error) {
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			return fc, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Append(f)
+	}
+}
+
+// A Reader reads features from FlatGeobuf data.
+type Reader struct {
+	r      io.Reader
+	header *Header
+
+	// pos is the number of bytes read, or skipped, from the start.
+	pos           int64
+	featuresStart int64
+}
+
+// NewReader reads the header and returns a Reader positioned at the start
+// of the index, or features. If r is an io.Seeker, Search will seek to the
+// matching features instead of reading past the others.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: r}
+
+	var m [8]byte
+	if err := rd.read(m[:]); err != nil {
+		return nil, ErrNotFlatGeobuf
+	}
+
+	if !bytes.Equal(m[:3], magic[:3]) || m[3] != magic[3] || !bytes.Equal(m[4:7], magic[4:7]) {
+		return nil, ErrNotFlatGeobuf
+	}
+
+	data, err := rd.readSizePrefixed(maxHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rd.header, err = unmarshalHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rd.featuresStart = rd.pos + int64(indexSize(rd.header.FeaturesCount, rd.header.IndexNodeSize))
+	return rd, nil
+}
+
+// Header returns the header of the data.
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Next returns the next feature. The spatial index, if any, is skipped.
+// It returns io.EOF when there are no more features.
+func (r *Reader) Next() (*geojson.Feature, error) {
+	if err := r.skip(r.featuresStart - r.pos); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	data, err := r.readSizePrefixed(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.feature(data)
+}
+
+// Search returns the features whose bound intersects b using the spatial
+// index. It must be called before Next. If there is no index all the features
+// are read and the ones whose geometry bound intersects b are returned.
+func (r *Reader) Search(b orb.Bound) ([]*geojson.Feature, error) {
+	if r.pos > r.featuresStart || (r.featuresStart > r.pos && r.header.IndexNodeSize == 0) {
+		return nil, ErrInvalidData
+	}
+
+	if r.featuresStart == r.pos {
+		return r.searchAll(b)
+	}
+
+	index, err := r.readBytes(r.featuresStart - r.pos)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets, err := searchIndex(index, r.header.FeaturesCount, r.header.IndexNodeSize, b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*geojson.Feature, 0, len(offsets))
+	for _, o := range offsets {
+		if err := r.skip(r.featuresStart + int64(o) - r.pos); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		data, err := r.readSizePrefixed(-1)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		f, err := r.feature(data)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+func (r *Reader) searchAll(b orb.Bound) ([]*geojson.Feature, error) {
+	var result []*geojson.Feature
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Geometry != nil && intersects(b, f.Geometry.Bound()) {
+			result = append(result, f)
+		}
+	}
+}
+
+func (r *Reader) feature(data []byte) (*geojson.Feature, error) {
+	fr := &fbReader{buf: data}
+	root := fr.root()
+
+	f := geojson.NewFeature(nil)
+	if g := fr.offset(root, featureGeometry); g != 0 {
+		geom, err := readGeometry(fr, g, r.header.GeometryType)
+		if err != nil {
+			return nil, err
+		}
+
+		f.Geometry = geom
+	}
+
+	if props := fr.bytesField(root, featureProperties); len(props) > 0 {
+		p, err := decodeProperties(props, r.header.Columns)
+		if err != nil {
+			return nil, err
+		}
+
+		f.Properties = p
+	}
+
+	if fr.err != nil {
+		return nil, fr.err
+	}
+
+	return f, nil
+}
+
+// readSizePrefixed reads a uint32 size followed by that many bytes.
+// It returns io.EOF if there is no more data.
+func (r *Reader) readSizePrefixed(max int64) ([]byte, error) {
+	var size [4]byte
+	if err := r.read(size[:]); err != nil {
+		return nil, err
+	}
+
+	n := int64(binary.LittleEndian.Uint32(size[:]))
+	if max > 0 && n > max {
+		return nil, ErrInvalidData
+	}
+
+	return r.readBytes(n)
+}
+
+// readBytes copies the bytes instead of allocating the full size
+// up front in case the size is invalid.
+func (r *Reader) readBytes(n int64) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	c, err := io.CopyN(buf, r.r, n)
+	r.pos += c
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (r *Reader) read(data []byte) error {
+	n, err := io.ReadFull(r.r, data)
+	r.pos += int64(n)
+
+	return err
+}
+
+// skip moves forward n bytes, seeking if possible.
+func (r *Reader) skip(n int64) error {
+	if n <= 0 {
+		return nil
+	}
+
+	if s, ok := r.r.(io.Seeker); ok {
+		if _, err := s.Seek(n, io.SeekCurrent); err != nil {
+			return err
+		}
+
+		r.pos += n
+		return nil
+	}
+
+	c, err := io.CopyN(ioutil.Discard, r.r, n)
+	r.pos += c
+	return err
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,5 @@
+module github.com/paulmach/orb/encoding/flatgeobuf/testdata/generate
+
+go 1.15
+
+require github.com/google/flatbuffers v25.2.10+incompatible
@@ -0,0 +1,375 @@
+// Command generate writes the FlatGeobuf test fixtures. It does not use the
+// flatgeobuf package, the tables are built with the FlatBuffers Go runtime
+// following the schema and the packed Hilbert R-tree from the spec at
+// https://github.com/flatgeobuf/flatgeobuf so the files are independent of
+// the package's encoder.
+//
+//	cd testdata/generate && go run .
+package main
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+const (
+	geometryTypePolygon = 3
+
+	columnTypeBool   = 2
+	columnTypeLong   = 7
+	columnTypeDouble = 10
+	columnTypeString = 11
+
+	nodeSize   = 16
+	hilbertMax = (1 << 16) - 1
+)
+
+var magic = []byte{0x66, 0x67, 0x62, 0x03, 0x66, 0x67, 0x62, 0x00}
+
+type column struct {
+	name string
+	typ  byte
+}
+
+var columns = []column{
+	{"name", columnTypeString},
+	{"id", columnTypeLong},
+	{"area", columnTypeDouble},
+	{"even", columnTypeBool},
+}
+
+type feature struct {
+	rings [][][2]float64
+	props []byte
+	bound [4]float64
+	data  []byte
+}
+
+func main() {
+	features := make([]*feature, 20)
+	for i := range features {
+		features[i] = newFeature(i)
+	}
+
+	if err := ioutil.WriteFile("../polygons_noindex.fgb", encode(features, 0), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("../polygons.fgb", encode(features, nodeSize), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newFeature returns an 8x8 square in a 5x4 grid, every third square has a hole.
+func newFeature(i int) *feature {
+	x, y := float64(10*(i%5)), float64(10*(i/5))
+
+	f := &feature{
+		rings: [][][2]float64{{{x, y}, {x + 8, y}, {x + 8, y + 8}, {x, y + 8}, {x, y}}},
+		bound: [4]float64{x, y, x + 8, y + 8},
+	}
+
+	area := 64.0
+	if i%3 == 0 {
+		f.rings = append(f.rings, [][2]float64{{x + 2, y + 2}, {x + 2, y + 6}, {x + 6, y + 6}, {x + 6, y + 2}, {x + 2, y + 2}})
+		area -= 16
+	}
+
+	// the last feature has no name, the property is left out.
+	var props []byte
+	if i != 19 {
+		name := "feature " + string(rune('a'+i))
+		props = appendUint16(props, 0)
+		props = appendUint32(props, uint32(len(name)))
+		props = append(props, name...)
+	}
+
+	props = appendUint16(props, 1)
+	props = appendUint64(props, uint64(int64(1000+i)))
+	props = appendUint16(props, 2)
+	props = appendUint64(props, math.Float64bits(area))
+	props = appendUint16(props, 3)
+	if i%2 == 0 {
+		props = append(props, 1)
+	} else {
+		props = append(props, 0)
+	}
+	f.props = props
+
+	f.data = encodeFeature(f)
+	return f
+}
+
+func encode(features []*feature, nodeSize uint16) []byte {
+	extent := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, f := range features {
+		extent = extend(extent, f.bound)
+	}
+
+	if nodeSize > 0 {
+		sorted := make([]*feature, len(features))
+		copy(sorted, features)
+		hilbertSort(sorted, extent)
+		features = sorted
+	}
+
+	data := append([]byte(nil), magic...)
+	data = append(data, encodeHeader(extent, uint64(len(features)), nodeSize)...)
+
+	if nodeSize > 0 {
+		data = append(data, encodeIndex(features, nodeSize)...)
+	}
+
+	for _, f := range features {
+		data = append(data, f.data...)
+	}
+
+	return data
+}
+
+// encodeHeader writes the header table with a crs like most writers.
+func encodeHeader(extent [4]float64, count uint64, nodeSize uint16) []byte {
+	b := flatbuffers.NewBuilder(0)
+
+	name := b.CreateString("polygons")
+
+	b.StartVector(8, 4, 8)
+	for i := 3; i >= 0; i-- {
+		b.PrependFloat64(extent[i])
+	}
+	envelope := b.EndVector(4)
+
+	offsets := make([]flatbuffers.UOffsetT, len(columns))
+	for i, c := range columns {
+		n := b.CreateString(c.name)
+		b.StartObject(11)
+		b.PrependUOffsetTSlot(0, n, 0)
+		b.PrependByteSlot(1, c.typ, 0)
+		offsets[i] = b.EndObject()
+	}
+
+	b.StartVector(4, len(offsets), 4)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	cols := b.EndVector(len(offsets))
+
+	org := b.CreateString("EPSG")
+	b.StartObject(6)
+	b.PrependUOffsetTSlot(0, org, 0)
+	b.PrependInt32Slot(1, 3857, 0)
+	crs := b.EndObject()
+
+	b.StartObject(14)
+	b.PrependUOffsetTSlot(0, name, 0)
+	b.PrependUOffsetTSlot(1, envelope, 0)
+	b.PrependByteSlot(2, geometryTypePolygon, 0)
+	b.PrependUOffsetTSlot(7, cols, 0)
+	b.PrependUint64Slot(8, count, 0)
+	b.PrependUint16Slot(9, nodeSize, 16)
+	b.PrependUOffsetTSlot(10, crs, 0)
+	b.FinishSizePrefixed(b.EndObject())
+
+	return b.FinishedBytes()
+}
+
+// encodeFeature writes the feature table. The layer has a geometry type
+// so the type of the geometry is left out.
+func encodeFeature(f *feature) []byte {
+	b := flatbuffers.NewBuilder(0)
+
+	var (
+		ends []uint32
+		xy   []float64
+	)
+	for _, r := range f.rings {
+		for _, p := range r {
+			xy = append(xy, p[0], p[1])
+		}
+		ends = append(ends, uint32(len(xy)/2))
+	}
+
+	b.StartVector(4, len(ends), 4)
+	for i := len(ends) - 1; i >= 0; i-- {
+		b.PrependUint32(ends[i])
+	}
+	endsOffset := b.EndVector(len(ends))
+
+	b.StartVector(8, len(xy), 8)
+	for i := len(xy) - 1; i >= 0; i-- {
+		b.PrependFloat64(xy[i])
+	}
+	xyOffset := b.EndVector(len(xy))
+
+	b.StartObject(8)
+	if len(f.rings) > 1 {
+		b.PrependUOffsetTSlot(0, endsOffset, 0)
+	}
+	b.PrependUOffsetTSlot(1, xyOffset, 0)
+	geometry := b.EndObject()
+
+	props := b.CreateByteVector(f.props)
+
+	b.StartObject(3)
+	b.PrependUOffsetTSlot(0, geometry, 0)
+	b.PrependUOffsetTSlot(1, props, 0)
+	b.FinishSizePrefixed(b.EndObject())
+
+	return b.FinishedBytes()
+}
+
+// encodeIndex writes the packed Hilbert R-tree, root first.
+func encodeIndex(features []*feature, nodeSize uint16) []byte {
+	type node struct {
+		bound  [4]float64
+		offset uint64
+	}
+
+	bounds := levelBounds(uint64(len(features)), nodeSize)
+	nodes := make([]node, bounds[0][1])
+
+	var offset uint64
+	for i, f := range features {
+		nodes[bounds[0][0]+uint64(i)] = node{bound: f.bound, offset: offset}
+		offset += uint64(len(f.data))
+	}
+
+	for level := 0; level < len(bounds)-1; level++ {
+		parent := bounds[level+1][0]
+		for start := bounds[level][0]; start < bounds[level][1]; start += uint64(nodeSize) {
+			n := node{
+				bound:  [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)},
+				offset: start,
+			}
+			for i := start; i < start+uint64(nodeSize) && i < bounds[level][1]; i++ {
+				n.bound = extend(n.bound, nodes[i].bound)
+			}
+			nodes[parent] = n
+			parent++
+		}
+	}
+
+	var data []byte
+	for _, n := range nodes {
+		for _, v := range n.bound {
+			data = appendUint64(data, math.Float64bits(v))
+		}
+		data = appendUint64(data, n.offset)
+	}
+
+	return data
+}
+
+// levelBounds returns the node range of each level, leaves first.
+func levelBounds(numItems uint64, nodeSize uint16) [][2]uint64 {
+	n := numItems
+	numNodes := n
+	levelNumNodes := []uint64{n}
+	for n != 1 {
+		n = (n + uint64(nodeSize) - 1) / uint64(nodeSize)
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+	}
+
+	var bounds [][2]uint64
+	end := numNodes
+	for _, size := range levelNumNodes {
+		bounds = append(bounds, [2]uint64{end - size, end})
+		end -= size
+	}
+
+	return bounds
+}
+
+func hilbertSort(features []*feature, extent [4]float64) {
+	width := extent[2] - extent[0]
+	height := extent[3] - extent[1]
+
+	value := func(f *feature) uint32 {
+		x := uint32(math.Floor(hilbertMax * ((f.bound[0]+f.bound[2])/2 - extent[0]) / width))
+		y := uint32(math.Floor(hilbertMax * ((f.bound[1]+f.bound[3])/2 - extent[1]) / height))
+		return hilbert(x, y)
+	}
+
+	sort.SliceStable(features, func(i, j int) bool {
+		return value(features[i]) > value(features[j])
+	})
+}
+
+// hilbert is from https://github.com/rawrunprotected/hilbert_curves,
+// the same as the reference implementations.
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
+
+func extend(a, b [4]float64) [4]float64 {
+	return [4]float64{
+		math.Min(a[0], b[0]), math.Min(a[1], b[1]),
+		math.Max(a[2], b[2]), math.Max(a[3], b[3]),
+	}
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
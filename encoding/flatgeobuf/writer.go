@@ -0,0 +1,264 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// feature table field ids
+const (
+	featureGeometry   = 0
+	featureProperties = 1
+)
+
+// Marshal encodes the feature collection with a spatial index. The columns
+// are inferred from the properties of all the features, properties with
+// mixed number types are doubles and other mixed types are json.
+func Marshal(fc *geojson.FeatureCollection, opts ...Option) ([]byte, error) {
+	columns := make([]Column, 0)
+	index := make(map[string]int)
+	for _, f := range fc.Features {
+		for _, key := range sortedKeys(f.Properties) {
+			t, ok := inferType(f.Properties[key])
+			if !ok {
+				continue
+			}
+
+			if i, ok := index[key]; ok {
+				columns[i].Type = mergeType(columns[i].Type, t)
+				continue
+			}
+
+			index[key] = len(columns)
+			columns = append(columns, Column{Name: key, Type: t})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, append([]Option{Columns(columns...)}, opts...)...)
+	for _, f := range fc.Features {
+		if err := w.Write(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pending is an encoded feature waiting to be written in index order.
+type pending struct {
+	data  []byte
+	bound orb.Bound
+}
+
+// A Writer writes features to FlatGeobuf. With the spatial index, the
+// default, the features are encoded and held in memory until Close since
+// the index, and so the order of the features, is written first.
+// Without an index they are written as they are received.
+//
+// If the Columns option is not set the columns are added as properties
+// are seen, with the type inferred from the first non-nil value. Without
+// an index the header is written with the first feature, so only the
+// properties of that feature are written.
+type Writer struct {
+	w    io.Writer
+	opts *options
+
+	header  *Header
+	index   map[string]int
+	started bool
+	types   map[GeometryType]bool
+
+	features []*pending
+	err      error
+}
+
+// NewWriter creates a new Writer that writes to w.
+func NewWriter(w io.Writer, opts ...Option) *Writer {
+	o := &options{nodeSize: DefaultIndexNodeSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	wr := &Writer{
+		w:    w,
+		opts: o,
+		header: &Header{
+			Name:          o.name,
+			Bound:         emptyBound,
+			IndexNodeSize: o.nodeSize,
+			Columns:       append([]Column(nil), o.columns...),
+		},
+		index: make(map[string]int),
+		types: make(map[GeometryType]bool),
+	}
+
+	for i, c := range wr.header.Columns {
+		wr.index[c.Name] = i
+	}
+
+	return wr
+}
+
+// Write encodes the feature.
+func (w *Writer) Write(f *geojson.Feature) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if !w.opts.hasSchema && (w.opts.nodeSize > 0 || !w.started) {
+		w.addColumns(f.Properties)
+	}
+
+	props, err := encodeProperties(f.Properties, w.header.Columns)
+	if err != nil {
+		return err
+	}
+
+	var fields []fbField
+	p := &pending{bound: emptyBound}
+	if f.Geometry != nil {
+		fields = append(fields, childField(featureGeometry, func(fw *fbWriter) int {
+			return fw.table(geometryFields(f.Geometry))
+		}))
+
+		p.bound = f.Geometry.Bound()
+		w.header.Bound = extend(w.header.Bound, p.bound)
+		w.types[geometryTypeOf(f.Geometry)] = true
+	}
+
+	if len(props) > 0 {
+		fields = append(fields, childField(featureProperties, func(fw *fbWriter) int {
+			return fw.bytes(props)
+		}))
+	}
+
+	p.data = newSizePrefixed().finish(fields)
+	w.header.FeaturesCount++
+
+	if w.opts.nodeSize > 0 {
+		w.features = append(w.features, p)
+		return nil
+	}
+
+	// no index, the header can not include the feature count,
+	// bound or geometry type since they are not known yet.
+	if !w.started {
+		w.started = true
+
+		h := *w.header
+		h.Bound = emptyBound
+		h.FeaturesCount = 0
+		if err := w.writeHeader(&h); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.w.Write(p.data)
+	w.err = err
+	return err
+}
+
+// Close writes the header, index and features if using an index.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.opts.nodeSize == 0 {
+		if w.started {
+			return nil
+		}
+
+		return w.writeHeader(w.header)
+	}
+
+	if len(w.types) == 1 {
+		for t := range w.types {
+			w.header.GeometryType = t
+		}
+	}
+
+	if w.header.FeaturesCount == 0 {
+		w.header.IndexNodeSize = 0
+		return w.writeHeader(w.header)
+	}
+
+	if err := w.writeHeader(w.header); err != nil {
+		return err
+	}
+
+	hilbertSort(w.features, w.header.Bound)
+
+	leaves := make([]nodeItem, 0, len(w.features))
+	offset := uint64(0)
+	for _, p := range w.features {
+		leaves = append(leaves, nodeItem{bound: p.bound, offset: offset})
+		offset += uint64(len(p.data))
+	}
+
+	if _, err := w.w.Write(buildIndex(leaves, w.header.IndexNodeSize)); err != nil {
+		w.err = err
+		return err
+	}
+
+	for _, p := range w.features {
+		if _, err := w.w.Write(p.data); err != nil {
+			w.err = err
+			return err
+		}
+	}
+
+	w.features = nil
+	return nil
+}
+
+func (w *Writer) writeHeader(h *Header) error {
+	if h.Bound == emptyBound {
+		h.Bound = orb.Bound{}
+	}
+
+	if _, err := w.w.Write(magic); err != nil {
+		w.err = err
+		return err
+	}
+
+	_, err := w.w.Write(h.marshal())
+	w.err = err
+	return err
+}
+
+func (w *Writer) addColumns(props geojson.Properties) {
+	for _, key := range sortedKeys(props) {
+		if _, ok := w.index[key]; ok {
+			continue
+		}
+
+		t, ok := inferType(props[key])
+		if !ok {
+			continue
+		}
+
+		w.index[key] = len(w.header.Columns)
+		w.header.Columns = append(w.header.Columns, Column{Name: key, Type: t})
+	}
+}
+
+func sortedKeys(props geojson.Properties) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,167 @@
+package gpx
+
+import (
+	"encoding/xml"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// Unmarshal decodes the GPX data into a feature collection. Waypoints are
+// returned first as points, then routes as line strings and tracks as
+// multi line strings, with a line string for each track segment.
+//
+// The name, cmt, desc, src, sym, type and number values become properties
+// of the same name, element extensions become the "extensions" property
+// as raw xml. For waypoints the time and elevation are the "time" and "ele"
+// properties. For routes and tracks the values of each point are in the
+// "times", "elevations" and "pointExtensions" properties, arrays matching
+// the coordinates with nil for missing values. They are only set if
+// at least one point has the value.
+func Unmarshal(data []byte) (*geojson.FeatureCollection, error) {
+	doc := &gpx{}
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for _, w := range doc.Waypoints {
+		f := geojson.NewFeature(orb.Point{w.Lon, w.Lat})
+		setString(f.Properties, "name", w.Name)
+		setString(f.Properties, "cmt", w.Cmt)
+		setString(f.Properties, "desc", w.Desc)
+		setString(f.Properties, "src", w.Src)
+		setString(f.Properties, "sym", w.Sym)
+		setString(f.Properties, "type", w.Type)
+		setString(f.Properties, "time", w.Time)
+		setString(f.Properties, "extensions", w.Extensions.String())
+		if w.Ele != nil {
+			f.Properties["ele"] = *w.Ele
+		}
+
+		fc.Append(f)
+	}
+
+	for _, r := range doc.Routes {
+		v := &pointValues{}
+		f := geojson.NewFeature(v.lineString(r.Points))
+		setString(f.Properties, "name", r.Name)
+		setString(f.Properties, "cmt", r.Cmt)
+		setString(f.Properties, "desc", r.Desc)
+		setString(f.Properties, "src", r.Src)
+		setString(f.Properties, "type", r.Type)
+		setString(f.Properties, "extensions", r.Extensions.String())
+		if r.Number != nil {
+			f.Properties["number"] = float64(*r.Number)
+		}
+
+		v.set(f.Properties, 0)
+		fc.Append(f)
+	}
+
+	for _, t := range doc.Tracks {
+		v := &pointValues{}
+		mls := make(orb.MultiLineString, 0, len(t.Segments))
+		for _, s := range t.Segments {
+			mls = append(mls, v.lineString(s.Points))
+		}
+
+		f := geojson.NewFeature(mls)
+		setString(f.Properties, "name", t.Name)
+		setString(f.Properties, "cmt", t.Cmt)
+		setString(f.Properties, "desc", t.Desc)
+		setString(f.Properties, "src", t.Src)
+		setString(f.Properties, "type", t.Type)
+		setString(f.Properties, "extensions", t.Extensions.String())
+		if t.Number != nil {
+			f.Properties["number"] = float64(*t.Number)
+		}
+
+		v.set(f.Properties, len(t.Segments))
+		fc.Append(f)
+	}
+
+	return fc, nil
+}
+
+func setString(props geojson.Properties, key, value string) {
+	if value != "" {
+		props[key] = value
+	}
+}
+
+// pointValues collects the time, elevation and extensions of
+// each point of the line strings.
+type pointValues struct {
+	times      [][]interface{}
+	elevations [][]interface{}
+	extensions [][]interface{}
+
+	hasTime, hasEle, hasExt bool
+}
+
+func (v *pointValues) lineString(points []point) orb.LineString {
+	ls := make(orb.LineString, 0, len(points))
+	times := make([]interface{}, 0, len(points))
+	eles := make([]interface{}, 0, len(points))
+	exts := make([]interface{}, 0, len(points))
+
+	for _, p := range points {
+		ls = append(ls, orb.Point{p.Lon, p.Lat})
+
+		if p.Time != "" {
+			times = append(times, p.Time)
+			v.hasTime = true
+		} else {
+			times = append(times, nil)
+		}
+
+		if p.Ele != nil {
+			eles = append(eles, *p.Ele)
+			v.hasEle = true
+		} else {
+			eles = append(eles, nil)
+		}
+
+		if s := p.Extensions.String(); s != "" {
+			exts = append(exts, s)
+			v.hasExt = true
+		} else {
+			exts = append(exts, nil)
+		}
+	}
+
+	v.times = append(v.times, times)
+	v.elevations = append(v.elevations, eles)
+	v.extensions = append(v.extensions, exts)
+
+	return ls
+}
+
+// set adds the values to the properties, nested by segment if there
+// are segments. Routes have no segments so the values are a flat array.
+func (v *pointValues) set(props geojson.Properties, segments int) {
+	value := func(vals [][]interface{}) interface{} {
+		if segments == 0 {
+			return vals[0]
+		}
+
+		result := make([]interface{}, 0, len(vals))
+		for _, v := range vals {
+			result = append(result, v)
+		}
+		return result
+	}
+
+	if v.hasTime {
+		props["times"] = value(v.times)
+	}
+
+	if v.hasEle {
+		props["elevations"] = value(v.elevations)
+	}
+
+	if v.hasExt {
+		props["pointExtensions"] = value(v.extensions)
+	}
+}
@@ -0,0 +1,215 @@
+package gpx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// ErrUnsupportedGeometry is returned when encoding a feature with a geometry
+// that is not a point, multi point, line string or multi line string.
+var ErrUnsupportedGeometry = errors.New("gpx: unsupported geometry")
+
+// Creator is the value of the creator attribute of encoded documents.
+var Creator = "github.com/paulmach/orb"
+
+// Marshal encodes the feature collection as a GPX 1.1 document. Points
+// become waypoints, each point of a multi point a waypoint, line strings
+// become routes and multi line strings become tracks with a segment for
+// each line string. The properties are read as described in Unmarshal.
+// Per point values are only written if the arrays match the coordinates,
+// so they are dropped if the geometry was simplified or resampled.
+func Marshal(fc *geojson.FeatureCollection) ([]byte, error) {
+	doc := &gpx{
+		Version: "1.1",
+		Creator: Creator,
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: Namespace},
+			{Name: xml.Name{Local: "xmlns:gpxx"}, Value: GarminExtensionsNamespace},
+			{Name: xml.Name{Local: "xmlns:gpxtpx"}, Value: GarminTrackPointExtensionNamespace},
+		},
+	}
+
+	for _, f := range fc.Features {
+		switch g := f.Geometry.(type) {
+		case orb.Point:
+			doc.Waypoints = append(doc.Waypoints, waypoint(g, f.Properties))
+		case orb.MultiPoint:
+			for _, p := range g {
+				doc.Waypoints = append(doc.Waypoints, waypoint(p, f.Properties))
+			}
+		case orb.LineString:
+			r := route{
+				Name:       str(f.Properties["name"]),
+				Cmt:        str(f.Properties["cmt"]),
+				Desc:       str(f.Properties["desc"]),
+				Src:        str(f.Properties["src"]),
+				Number:     number(f.Properties["number"]),
+				Type:       str(f.Properties["type"]),
+				Extensions: newExtensions(str(f.Properties["extensions"])),
+			}
+
+			r.Points = points(g,
+				values(f.Properties["times"], len(g)),
+				values(f.Properties["elevations"], len(g)),
+				values(f.Properties["pointExtensions"], len(g)),
+			)
+
+			doc.Routes = append(doc.Routes, r)
+		case orb.MultiLineString:
+			t := track{
+				Name:       str(f.Properties["name"]),
+				Cmt:        str(f.Properties["cmt"]),
+				Desc:       str(f.Properties["desc"]),
+				Src:        str(f.Properties["src"]),
+				Number:     number(f.Properties["number"]),
+				Type:       str(f.Properties["type"]),
+				Extensions: newExtensions(str(f.Properties["extensions"])),
+			}
+
+			times := values(f.Properties["times"], len(g))
+			eles := values(f.Properties["elevations"], len(g))
+			exts := values(f.Properties["pointExtensions"], len(g))
+			for i, ls := range g {
+				t.Segments = append(t.Segments, segment{
+					Points: points(ls,
+						values(index(times, i), len(ls)),
+						values(index(eles, i), len(ls)),
+						values(index(exts, i), len(ls)),
+					),
+				})
+			}
+
+			doc.Tracks = append(doc.Tracks, t)
+		default:
+			return nil, ErrUnsupportedGeometry
+		}
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+func waypoint(p orb.Point, props geojson.Properties) point {
+	return point{
+		Lat:        p.Lat(),
+		Lon:        p.Lon(),
+		Ele:        float(props["ele"]),
+		Time:       timeString(props["time"]),
+		Name:       str(props["name"]),
+		Cmt:        str(props["cmt"]),
+		Desc:       str(props["desc"]),
+		Src:        str(props["src"]),
+		Sym:        str(props["sym"]),
+		Type:       str(props["type"]),
+		Extensions: newExtensions(str(props["extensions"])),
+	}
+}
+
+func points(ls orb.LineString, times, eles, exts []interface{}) []point {
+	result := make([]point, 0, len(ls))
+	for i, p := range ls {
+		result = append(result, point{
+			Lat:        p.Lat(),
+			Lon:        p.Lon(),
+			Ele:        float(index(eles, i)),
+			Time:       timeString(index(times, i)),
+			Extensions: newExtensions(str(index(exts, i))),
+		})
+	}
+
+	return result
+}
+
+// values returns the property value as an array if it has n values.
+func values(v interface{}, n int) []interface{} {
+	var result []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		result = v
+	case []string:
+		for _, s := range v {
+			result = append(result, s)
+		}
+	case []float64:
+		for _, f := range v {
+			result = append(result, f)
+		}
+	case []time.Time:
+		for _, t := range v {
+			result = append(result, t)
+		}
+	}
+
+	if len(result) != n {
+		return nil
+	}
+
+	return result
+}
+
+func index(vals []interface{}, i int) interface{} {
+	if vals == nil {
+		return nil
+	}
+
+	return vals[i]
+}
+
+func str(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	return fmt.Sprint(v)
+}
+
+func timeString(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return str(v)
+}
+
+func float(v interface{}) *float64 {
+	var f float64
+	switch v := v.(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	case json.Number:
+		var err error
+		f, err = v.Float64()
+		if err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return &f
+}
+
+func number(v interface{}) *int {
+	f := float(v)
+	if f == nil {
+		return nil
+	}
+
+	n := int(*f)
+	return &n
+}
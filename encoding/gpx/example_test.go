@@ -0,0 +1,36 @@
+package gpx_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/gpx"
+	"github.com/paulmach/orb/simplify"
+)
+
+func ExampleUnmarshal() {
+	data := []byte(`<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+		<trk><name>Ride</name><trkseg>
+			<trkpt lat="37.42" lon="-122.08"><ele>10</ele></trkpt>
+			<trkpt lat="37.43" lon="-122.09"><ele>12</ele></trkpt>
+		</trkseg></trk>
+	</gpx>`)
+
+	fc, err := gpx.Unmarshal(data)
+	if err != nil {
+		log.Fatalf("unmarshal error: %v", err)
+	}
+
+	track := fc.Features[0]
+	track.Geometry = simplify.DouglasPeucker(0.001).Simplify(track.Geometry)
+
+	fmt.Println(track.Properties["name"])
+	fmt.Println(track.Geometry.(orb.MultiLineString))
+	fmt.Println(track.Properties["elevations"])
+
+	// Output:
+	// Ride
+	// [[[-122.08 37.42] [-122.09 37.43]]]
+	// [[10 12]]
+}
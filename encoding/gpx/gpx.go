@@ -0,0 +1,88 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Namespace is the GPX 1.1 namespace.
+const Namespace = "http://www.topografix.com/GPX/1/1"
+
+// The namespaces of the common Garmin extensions. They are declared on the
+// root element when encoding so extensions using these prefixes stay valid.
+const (
+	GarminExtensionsNamespace          = "http://www.garmin.com/xmlschemas/GpxExtensions/v3"
+	GarminTrackPointExtensionNamespace = "http://www.garmin.com/xmlschemas/TrackPointExtension/v1"
+)
+
+type gpx struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Version   string     `xml:"version,attr,omitempty"`
+	Creator   string     `xml:"creator,attr,omitempty"`
+	Attrs     []xml.Attr `xml:",any,attr"`
+	Waypoints []point    `xml:"wpt"`
+	Routes    []route    `xml:"rte"`
+	Tracks    []track    `xml:"trk"`
+}
+
+type point struct {
+	Lat        float64     `xml:"lat,attr"`
+	Lon        float64     `xml:"lon,attr"`
+	Ele        *float64    `xml:"ele"`
+	Time       string      `xml:"time,omitempty"`
+	Name       string      `xml:"name,omitempty"`
+	Cmt        string      `xml:"cmt,omitempty"`
+	Desc       string      `xml:"desc,omitempty"`
+	Src        string      `xml:"src,omitempty"`
+	Sym        string      `xml:"sym,omitempty"`
+	Type       string      `xml:"type,omitempty"`
+	Extensions *extensions `xml:"extensions"`
+}
+
+type route struct {
+	Name       string      `xml:"name,omitempty"`
+	Cmt        string      `xml:"cmt,omitempty"`
+	Desc       string      `xml:"desc,omitempty"`
+	Src        string      `xml:"src,omitempty"`
+	Number     *int        `xml:"number"`
+	Type       string      `xml:"type,omitempty"`
+	Extensions *extensions `xml:"extensions"`
+	Points     []point     `xml:"rtept"`
+}
+
+type track struct {
+	Name       string      `xml:"name,omitempty"`
+	Cmt        string      `xml:"cmt,omitempty"`
+	Desc       string      `xml:"desc,omitempty"`
+	Src        string      `xml:"src,omitempty"`
+	Number     *int        `xml:"number"`
+	Type       string      `xml:"type,omitempty"`
+	Extensions *extensions `xml:"extensions"`
+	Segments   []segment   `xml:"trkseg"`
+}
+
+type segment struct {
+	Points     []point     `xml:"trkpt"`
+	Extensions *extensions `xml:"extensions"`
+}
+
+// extensions are kept as the raw xml.
+type extensions struct {
+	XML string `xml:",innerxml"`
+}
+
+func (e *extensions) String() string {
+	if e == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(e.XML)
+}
+
+func newExtensions(s string) *extensions {
+	if s == "" {
+		return nil
+	}
+
+	return &extensions{XML: s}
+}
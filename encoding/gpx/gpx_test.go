@@ -0,0 +1,248 @@
+package gpx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="Garmin Connect"
+  xmlns="http://www.topografix.com/GPX/1/1"
+  xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <metadata><time>2020-05-01T10:00:00Z</time></metadata>
+  <wpt lat="37.42" lon="-122.08">
+    <ele>12.5</ele>
+    <time>2020-05-01T10:00:00Z</time>
+    <name>Start</name>
+    <sym>Flag</sym>
+  </wpt>
+  <rte>
+    <name>Plan</name>
+    <number>3</number>
+    <rtept lat="1" lon="2"></rtept>
+    <rtept lat="3" lon="4"><ele>5</ele></rtept>
+  </rte>
+  <trk>
+    <name>Morning Ride</name>
+    <type>cycling</type>
+    <trkseg>
+      <trkpt lat="1" lon="2">
+        <ele>10</ele>
+        <time>2020-05-01T10:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="3" lon="4">
+        <ele>11</ele>
+        <time>2020-05-01T10:00:05Z</time>
+      </trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="5" lon="6"><ele>12</ele></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestUnmarshal(t *testing.T) {
+	fc, err := Unmarshal([]byte(testGPX))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(fc.Features) != 3 {
+		t.Fatalf("incorrect number of features: %d", len(fc.Features))
+	}
+
+	// waypoint
+	f := fc.Features[0]
+	if !orb.Equal(f.Geometry, orb.Point{-122.08, 37.42}) {
+		t.Errorf("incorrect waypoint: %v", f.Geometry)
+	}
+
+	expected := geojson.Properties{
+		"name": "Start",
+		"sym":  "Flag",
+		"time": "2020-05-01T10:00:00Z",
+		"ele":  12.5,
+	}
+	if !reflect.DeepEqual(f.Properties, expected) {
+		t.Errorf("incorrect waypoint properties: %v", f.Properties)
+	}
+
+	// route
+	f = fc.Features[1]
+	if !orb.Equal(f.Geometry, orb.LineString{{2, 1}, {4, 3}}) {
+		t.Errorf("incorrect route: %v", f.Geometry)
+	}
+
+	expected = geojson.Properties{
+		"name":       "Plan",
+		"number":     3.0,
+		"elevations": []interface{}{nil, 5.0},
+	}
+	if !reflect.DeepEqual(f.Properties, expected) {
+		t.Errorf("incorrect route properties: %v", f.Properties)
+	}
+
+	// track
+	f = fc.Features[2]
+	if !orb.Equal(f.Geometry, orb.MultiLineString{{{2, 1}, {4, 3}}, {{6, 5}}}) {
+		t.Errorf("incorrect track: %v", f.Geometry)
+	}
+
+	expected = geojson.Properties{
+		"name": "Morning Ride",
+		"type": "cycling",
+		"times": []interface{}{
+			[]interface{}{"2020-05-01T10:00:00Z", "2020-05-01T10:00:05Z"},
+			[]interface{}{nil},
+		},
+		"elevations": []interface{}{
+			[]interface{}{10.0, 11.0},
+			[]interface{}{12.0},
+		},
+		"pointExtensions": []interface{}{
+			[]interface{}{"<gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension>", nil},
+			[]interface{}{nil},
+		},
+	}
+	if !reflect.DeepEqual(f.Properties, expected) {
+		t.Errorf("incorrect track properties: %v", f.Properties)
+	}
+}
+
+func TestUnmarshal_error(t *testing.T) {
+	_, err := Unmarshal([]byte(`<gpx><wpt lat="a" lon="1"></wpt></gpx>`))
+	if err == nil {
+		t.Errorf("should return error for invalid lat")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Properties["name"] = "A & B"
+	f.Properties["ele"] = 3.0
+	f.Properties["time"] = time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	fc.Append(f)
+
+	f = geojson.NewFeature(orb.LineString{{1, 2}, {3, 4}})
+	f.Properties["number"] = 2
+	f.Properties["elevations"] = []float64{1, 2}
+	fc.Append(f)
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="github.com/paulmach/orb" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxx="http://www.garmin.com/xmlschemas/GpxExtensions/v3" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <wpt lat="2" lon="1">
+    <ele>3</ele>
+    <time>2020-05-01T10:00:00Z</time>
+    <name>A &amp; B</name>
+  </wpt>
+  <rte>
+    <number>2</number>
+    <rtept lat="2" lon="1">
+      <ele>1</ele>
+    </rtept>
+    <rtept lat="4" lon="3">
+      <ele>2</ele>
+    </rtept>
+  </rte>
+</gpx>`
+
+	if string(data) != expected {
+		t.Errorf("incorrect gpx")
+		t.Logf("%s", data)
+	}
+}
+
+func TestMarshal_roundTrip(t *testing.T) {
+	fc, err := Unmarshal([]byte(testGPX))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	// through json to make sure the property types survive
+	data, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("json marshal error: %v", err)
+	}
+
+	fc, err = geojson.UnmarshalFeatureCollection(data)
+	if err != nil {
+		t.Fatalf("json unmarshal error: %v", err)
+	}
+
+	data, err = Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(result.Features) != len(fc.Features) {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	for i := range fc.Features {
+		if !orb.Equal(result.Features[i].Geometry, fc.Features[i].Geometry) {
+			t.Errorf("%d: incorrect geometry: %v", i, result.Features[i].Geometry)
+		}
+
+		if !reflect.DeepEqual(result.Features[i].Properties, fc.Features[i].Properties) {
+			t.Errorf("%d: incorrect properties", i)
+			t.Logf("%v", result.Features[i].Properties)
+			t.Logf("%v", fc.Features[i].Properties)
+		}
+	}
+}
+
+func TestMarshal_resampled(t *testing.T) {
+	fc, err := Unmarshal([]byte(testGPX))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	track := fc.Features[2]
+	track.Geometry = orb.MultiLineString{
+		{{2, 1}, {3, 2}, {4, 3}},
+		{{6, 5}},
+	}
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// point values of the first segment no longer match, the second still do.
+	if strings.Contains(string(data), "<ele>10</ele>") {
+		t.Errorf("should drop values that do not match: %s", data)
+	}
+
+	if !strings.Contains(string(data), "<ele>12</ele>") {
+		t.Errorf("should keep values that match: %s", data)
+	}
+}
+
+func TestMarshal_unsupportedGeometry(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Polygon{}))
+
+	_, err := Marshal(fc)
+	if err != ErrUnsupportedGeometry {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
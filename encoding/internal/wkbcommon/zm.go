@@ -0,0 +1,565 @@
+// Package wkbcommon contains the WKB decoding and encoding with Z, M and SRID
+// values shared by the wkb and ewkb packages.
+package wkbcommon
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// ByteOrder represents little or big endian encoding.
+type ByteOrder int
+
+// The possible byte orders, the first byte of the WKB data.
+const (
+	BigEndian    ByteOrder = 0
+	LittleEndian ByteOrder = 1
+)
+
+const (
+	pointType              uint32 = 1
+	lineStringType         uint32 = 2
+	polygonType            uint32 = 3
+	multiPointType         uint32 = 4
+	multiLineStringType    uint32 = 5
+	multiPolygonType       uint32 = 6
+	geometryCollectionType uint32 = 7
+)
+
+// Geometries with Z and/or M values are indicated in the type either by
+// adding 1000, 2000 or 3000 as defined by ISO SQL/MM or by setting the high
+// bits as done by PostGIS EWKB. EWKB can also include an SRID after the type.
+const (
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+const (
+	// limits so that bad data can't come in and preallocate tons of memory.
+	maxPointsAlloc = 10000
+	maxMultiAlloc  = 100
+)
+
+var (
+	// ErrNotWKB is returned when the data is not valid.
+	ErrNotWKB = errors.New("wkb: invalid data")
+
+	// ErrUnsupportedGeometry is returned when geometry type is not supported.
+	ErrUnsupportedGeometry = errors.New("wkb: unsupported geometry")
+)
+
+// IsZM returns true if the type has Z, M or SRID values.
+func IsZM(typ uint32) bool {
+	_, layout, srid := SplitType(typ)
+	return layout != orb.XY || srid
+}
+
+// SplitType returns the base geometry type, the layout of
+// the points and if the type is followed by an SRID.
+func SplitType(typ uint32) (uint32, orb.Layout, bool) {
+	srid := typ&ewkbSRID != 0
+	z := typ&ewkbZ != 0
+	m := typ&ewkbM != 0
+
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1:
+		z = true
+	case 2:
+		m = true
+	case 3:
+		z, m = true, true
+	}
+	typ %= 1000
+
+	layout := orb.XY
+	switch {
+	case z && m:
+		layout = orb.XYZM
+	case z:
+		layout = orb.XYZ
+	case m:
+		layout = orb.XYM
+	}
+
+	return typ, layout, srid
+}
+
+// Decode reads a geometry of any layout, returning the SRID if present.
+func Decode(r io.Reader) (orb.GeometryZM, int, error) {
+	d := &zmDecoder{r: r}
+	g, layout, srid, err := d.geometry(false, orb.XY)
+	if err != nil {
+		return orb.GeometryZM{}, 0, err
+	}
+
+	return orb.GeometryZM{Geometry: g, Layout: layout, Extra: d.extra}, srid, nil
+}
+
+// DecodeBody reads the rest of a geometry after the byte order and type
+// have already been read off the reader.
+func DecodeBody(r io.Reader, order ByteOrder, typ uint32) (orb.GeometryZM, int, error) {
+	d := &zmDecoder{r: r}
+	g, layout, srid, err := d.body(order, typ, false, orb.XY)
+	if err != nil {
+		return orb.GeometryZM{}, 0, err
+	}
+
+	return orb.GeometryZM{Geometry: g, Layout: layout, Extra: d.extra}, srid, nil
+}
+
+type zmDecoder struct {
+	r     io.Reader
+	buf   [8]byte
+	order ByteOrder
+	extra []float64
+}
+
+// geometry reads the next geometry. If it is part of a multi geometry or
+// collection it must have the same layout as the parent.
+func (d *zmDecoder) geometry(child bool, parent orb.Layout) (orb.Geometry, orb.Layout, int, error) {
+	order, typ, err := readByteOrderType(d.r, d.buf[:])
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return d.body(order, typ, child, parent)
+}
+
+// body reads the rest of the geometry after the byte order and type.
+func (d *zmDecoder) body(order ByteOrder, typ uint32, child bool, parent orb.Layout) (orb.Geometry, orb.Layout, int, error) {
+	d.order = order
+
+	var err error
+	typ, layout, hasSRID := SplitType(typ)
+	if child && parent != layout {
+		return nil, 0, 0, ErrNotWKB
+	}
+
+	srid := 0
+	if hasSRID {
+		s, err := d.uint32()
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		srid = int(int32(s))
+	}
+
+	var g orb.Geometry
+	switch typ {
+	case pointType:
+		g, err = d.point(layout)
+	case lineStringType:
+		var ls []orb.Point
+		ls, err = d.points(layout)
+		g = orb.LineString(ls)
+	case polygonType:
+		g, err = d.polygon(layout)
+	case multiPointType:
+		var n uint32
+		if n, err = d.uint32(); err != nil {
+			break
+		}
+
+		mp := make(orb.MultiPoint, 0, alloc(n, maxPointsAlloc))
+		for i := 0; i < int(n) && err == nil; i++ {
+			var p orb.Geometry
+			if p, _, _, err = d.geometry(true, layout); err == nil {
+				if pt, ok := p.(orb.Point); ok {
+					mp = append(mp, pt)
+				} else {
+					err = ErrNotWKB
+				}
+			}
+		}
+		g = mp
+	case multiLineStringType:
+		var n uint32
+		if n, err = d.uint32(); err != nil {
+			break
+		}
+
+		mls := make(orb.MultiLineString, 0, alloc(n, maxMultiAlloc))
+		for i := 0; i < int(n) && err == nil; i++ {
+			var ls orb.Geometry
+			if ls, _, _, err = d.geometry(true, layout); err == nil {
+				if l, ok := ls.(orb.LineString); ok {
+					mls = append(mls, l)
+				} else {
+					err = ErrNotWKB
+				}
+			}
+		}
+		g = mls
+	case multiPolygonType:
+		var n uint32
+		if n, err = d.uint32(); err != nil {
+			break
+		}
+
+		mp := make(orb.MultiPolygon, 0, alloc(n, maxMultiAlloc))
+		for i := 0; i < int(n) && err == nil; i++ {
+			var p orb.Geometry
+			if p, _, _, err = d.geometry(true, layout); err == nil {
+				if poly, ok := p.(orb.Polygon); ok {
+					mp = append(mp, poly)
+				} else {
+					err = ErrNotWKB
+				}
+			}
+		}
+		g = mp
+	case geometryCollectionType:
+		var n uint32
+		if n, err = d.uint32(); err != nil {
+			break
+		}
+
+		c := make(orb.Collection, 0, alloc(n, maxMultiAlloc))
+		for i := 0; i < int(n) && err == nil; i++ {
+			var cg orb.Geometry
+			if cg, _, _, err = d.geometry(true, layout); err == nil {
+				c = append(c, cg)
+			}
+		}
+		g = c
+	default:
+		return nil, 0, 0, ErrUnsupportedGeometry
+	}
+
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return g, layout, srid, nil
+}
+
+func (d *zmDecoder) polygon(layout orb.Layout) (orb.Polygon, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	p := make(orb.Polygon, 0, alloc(n, maxMultiAlloc))
+	for i := 0; i < int(n); i++ {
+		r, err := d.points(layout)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, orb.Ring(r))
+	}
+
+	return p, nil
+}
+
+func (d *zmDecoder) points(layout orb.Layout) ([]orb.Point, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]orb.Point, 0, alloc(n, maxPointsAlloc))
+	for i := 0; i < int(n); i++ {
+		p, err := d.point(layout)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+func (d *zmDecoder) point(layout orb.Layout) (orb.Point, error) {
+	var p orb.Point
+	for i := 0; i < layout.Stride(); i++ {
+		v, err := d.float64()
+		if err != nil {
+			return orb.Point{}, err
+		}
+
+		if i < 2 {
+			p[i] = v
+		} else {
+			d.extra = append(d.extra, v)
+		}
+	}
+
+	return p, nil
+}
+
+func (d *zmDecoder) uint32() (uint32, error) {
+	return d.readUint32()
+}
+
+func (d *zmDecoder) readUint32() (uint32, error) {
+	if _, err := io.ReadFull(d.r, d.buf[:4]); err != nil {
+		return 0, err
+	}
+
+	if d.order == LittleEndian {
+		return binary.LittleEndian.Uint32(d.buf[:4]), nil
+	}
+
+	return binary.BigEndian.Uint32(d.buf[:4]), nil
+}
+
+func (d *zmDecoder) float64() (float64, error) {
+	if _, err := io.ReadFull(d.r, d.buf[:]); err != nil {
+		return 0, err
+	}
+
+	if d.order == LittleEndian {
+		return math.Float64frombits(binary.LittleEndian.Uint64(d.buf[:])), nil
+	}
+
+	return math.Float64frombits(binary.BigEndian.Uint64(d.buf[:])), nil
+}
+
+func alloc(n uint32, max int) int {
+	// invalid data can come in here and allocate tons of memory.
+	if int(n) > max || int(n) < 0 {
+		return max
+	}
+
+	return int(n)
+}
+
+// Encode writes the geometry with its z and m values. If ewkb is true the
+// PostGIS flags, and the srid if non-zero, are used instead of the ISO type codes.
+func Encode(w io.Writer, order binary.ByteOrder, g orb.GeometryZM, srid int, ewkb bool) error {
+	e := &zmEncoder{w: w, order: order, layout: g.Layout, extra: g.Extra, ewkb: ewkb}
+	return e.geometry(g.Geometry, srid)
+}
+
+type zmEncoder struct {
+	w     io.Writer
+	buf   [8]byte
+	order binary.ByteOrder
+
+	layout orb.Layout
+	extra  []float64
+	index  int
+
+	// ewkb will use the PostGIS flags instead of ISO type codes.
+	ewkb bool
+}
+
+// geometry writes the geometry, the srid is written if non-zero
+// and using ewkb.
+func (e *zmEncoder) geometry(g orb.Geometry, srid int) error {
+	switch t := g.(type) {
+	case orb.Ring:
+		g = orb.Polygon{t}
+	case orb.Bound:
+		// the polygon visits the bound's min and max points in this order.
+		n := e.layout.Stride() - 2
+		min := e.extra[e.index : e.index+n]
+		max := e.extra[e.index+n : e.index+2*n]
+
+		extra := make([]float64, 0, 5*n)
+		for _, v := range [][]float64{min, min, max, max, min} {
+			extra = append(extra, v...)
+		}
+
+		sub := &zmEncoder{w: e.w, order: e.order, layout: e.layout, extra: extra, ewkb: e.ewkb}
+		e.index += 2 * n
+		return sub.geometry(t.ToPolygon(), srid)
+	}
+
+	var typ uint32
+	switch g.(type) {
+	case orb.Point:
+		typ = pointType
+	case orb.MultiPoint:
+		typ = multiPointType
+	case orb.LineString:
+		typ = lineStringType
+	case orb.MultiLineString:
+		typ = multiLineStringType
+	case orb.Polygon:
+		typ = polygonType
+	case orb.MultiPolygon:
+		typ = multiPolygonType
+	case orb.Collection:
+		typ = geometryCollectionType
+	default:
+		return ErrUnsupportedGeometry
+	}
+
+	if err := e.header(typ, srid); err != nil {
+		return err
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return e.point(g)
+	case orb.MultiPoint:
+		if err := e.uint32(uint32(len(g))); err != nil {
+			return err
+		}
+		for _, p := range g {
+			if err := e.geometry(p, 0); err != nil {
+				return err
+			}
+		}
+	case orb.LineString:
+		return e.points(g)
+	case orb.MultiLineString:
+		if err := e.uint32(uint32(len(g))); err != nil {
+			return err
+		}
+		for _, ls := range g {
+			if err := e.geometry(ls, 0); err != nil {
+				return err
+			}
+		}
+	case orb.Polygon:
+		if err := e.uint32(uint32(len(g))); err != nil {
+			return err
+		}
+		for _, r := range g {
+			if err := e.points(r); err != nil {
+				return err
+			}
+		}
+	case orb.MultiPolygon:
+		if err := e.uint32(uint32(len(g))); err != nil {
+			return err
+		}
+		for _, p := range g {
+			if err := e.geometry(p, 0); err != nil {
+				return err
+			}
+		}
+	case orb.Collection:
+		if err := e.uint32(uint32(len(g))); err != nil {
+			return err
+		}
+		for _, c := range g {
+			if err := e.geometry(c, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *zmEncoder) header(typ uint32, srid int) error {
+	if e.order == binary.LittleEndian {
+		e.buf[0] = 1
+	} else {
+		e.buf[0] = 0
+	}
+
+	if _, err := e.w.Write(e.buf[:1]); err != nil {
+		return err
+	}
+
+	if e.ewkb {
+		if e.layout.HasZ() {
+			typ |= ewkbZ
+		}
+		if e.layout.HasM() {
+			typ |= ewkbM
+		}
+		if srid != 0 {
+			typ |= ewkbSRID
+		}
+	} else {
+		switch e.layout {
+		case orb.XYZ:
+			typ += 1000
+		case orb.XYM:
+			typ += 2000
+		case orb.XYZM:
+			typ += 3000
+		}
+	}
+
+	if err := e.uint32(typ); err != nil {
+		return err
+	}
+
+	if e.ewkb && srid != 0 {
+		return e.uint32(uint32(int32(srid)))
+	}
+
+	return nil
+}
+
+func (e *zmEncoder) points(ps []orb.Point) error {
+	if err := e.uint32(uint32(len(ps))); err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		if err := e.point(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *zmEncoder) point(p orb.Point) error {
+	if err := e.float64(p[0]); err != nil {
+		return err
+	}
+
+	if err := e.float64(p[1]); err != nil {
+		return err
+	}
+
+	for i := 2; i < e.layout.Stride(); i++ {
+		if err := e.float64(e.extra[e.index]); err != nil {
+			return err
+		}
+		e.index++
+	}
+
+	return nil
+}
+
+func (e *zmEncoder) uint32(v uint32) error {
+	e.order.PutUint32(e.buf[:4], v)
+	_, err := e.w.Write(e.buf[:4])
+	return err
+}
+
+func (e *zmEncoder) float64(v float64) error {
+	e.order.PutUint64(e.buf[:], math.Float64bits(v))
+	_, err := e.w.Write(e.buf[:])
+	return err
+}
+
+func readByteOrderType(r io.Reader, buf []byte) (ByteOrder, uint32, error) {
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return 0, 0, err
+	}
+
+	var order ByteOrder
+	if buf[0] == 0 {
+		order = BigEndian
+	} else if buf[0] == 1 {
+		order = LittleEndian
+	} else {
+		return 0, 0, ErrNotWKB
+	}
+
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return 0, 0, err
+	}
+
+	if order == LittleEndian {
+		return order, binary.LittleEndian.Uint32(buf[:4]), nil
+	}
+
+	return order, binary.BigEndian.Uint32(buf[:4]), nil
+}
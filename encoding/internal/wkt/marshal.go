@@ -7,69 +7,114 @@ import (
 )
 
 func Marshal(buf *bytes.Buffer, geom orb.Geometry) {
+	e := &encoder{buf: buf}
+	e.geometry(geom)
+}
+
+// MarshalZM writes the geometry with its z and/or m values,
+// e.g. POINT Z(1 2 3).
+func MarshalZM(buf *bytes.Buffer, g orb.GeometryZM) {
+	e := &encoder{
+		buf:    buf,
+		layout: g.Layout,
+		extra:  g.Extra,
+	}
+
+	switch g.Layout {
+	case orb.XYZ:
+		e.tag = " Z"
+	case orb.XYM:
+		e.tag = " M"
+	case orb.XYZM:
+		e.tag = " ZM"
+	}
+
+	e.geometry(g.Geometry)
+}
+
+type encoder struct {
+	buf *bytes.Buffer
+
+	tag    string
+	layout orb.Layout
+	extra  []float64
+	index  int
+}
+
+func (e *encoder) geometry(geom orb.Geometry) {
+	buf := e.buf
 	switch g := geom.(type) {
 	case orb.Point:
-		fmt.Fprintf(buf, "POINT(%g %g)", g[0], g[1])
+		e.keyword("POINT")
+		buf.WriteByte('(')
+		e.point(g)
+		buf.WriteByte(')')
 	case orb.MultiPoint:
 		if len(g) == 0 {
-			buf.Write([]byte(`MULTIPOINT EMPTY`))
+			e.empty("MULTIPOINT")
 			return
 		}
 
-		buf.Write([]byte(`MULTIPOINT(`))
+		e.keyword("MULTIPOINT")
+		buf.WriteByte('(')
 		for i, p := range g {
 			if i != 0 {
 				buf.WriteByte(',')
 			}
 
-			fmt.Fprintf(buf, "(%g %g)", p[0], p[1])
+			buf.WriteByte('(')
+			e.point(p)
+			buf.WriteByte(')')
 		}
 		buf.WriteByte(')')
 	case orb.LineString:
 		if len(g) == 0 {
-			buf.Write([]byte(`LINESTRING EMPTY`))
+			e.empty("LINESTRING")
 			return
 		}
 
-		buf.Write([]byte(`LINESTRING`))
-		writeLineString(buf, g)
+		e.keyword("LINESTRING")
+		e.lineString(g)
 	case orb.MultiLineString:
 		if len(g) == 0 {
-			buf.Write([]byte(`MULTILINESTRING EMPTY`))
+			e.empty("MULTILINESTRING")
 			return
 		}
 
-		buf.Write([]byte(`MULTILINESTRING(`))
+		e.keyword("MULTILINESTRING")
+		buf.WriteByte('(')
 		for i, ls := range g {
 			if i != 0 {
 				buf.WriteByte(',')
 			}
-			writeLineString(buf, ls)
+			e.lineString(ls)
 		}
 		buf.WriteByte(')')
 	case orb.Ring:
-		Marshal(buf, orb.Polygon{g})
+		e.geometry(orb.Polygon{g})
 	case orb.Polygon:
 		if len(g) == 0 {
-			buf.Write([]byte(`POLYGON EMPTY`))
+			e.empty("POLYGON")
 			return
 		}
 
-		buf.Write([]byte(`POLYGON(`))
+		e.keyword("POLYGON")
+		buf.WriteByte('(')
 		for i, r := range g {
 			if i != 0 {
 				buf.WriteByte(',')
 			}
-			writeLineString(buf, orb.LineString(r))
+			e.lineString(orb.LineString(r))
 		}
 		buf.WriteByte(')')
 	case orb.MultiPolygon:
 		if len(g) == 0 {
-			buf.Write([]byte(`MULTIPOLYGON EMPTY`))
+			e.empty("MULTIPOLYGON")
 			return
 		}
 
-		buf.Write([]byte(`MULTIPOLYGON(`))
+		e.keyword("MULTIPOLYGON")
+		buf.WriteByte('(')
 		for i, p := range g {
 			if i != 0 {
 				buf.WriteByte(',')
@@ -79,39 +124,82 @@ func Marshal(buf *bytes.Buffer, geom orb.Geometry) {
 				if j != 0 {
 					buf.WriteByte(',')
 				}
-				writeLineString(buf, orb.LineString(r))
+				e.lineString(orb.LineString(r))
 			}
 			buf.WriteByte(')')
 		}
 		buf.WriteByte(')')
 	case orb.Collection:
 		if len(g) == 0 {
-			buf.Write([]byte(`GEOMETRYCOLLECTION EMPTY`))
+			e.empty("GEOMETRYCOLLECTION")
 			return
 		}
-		buf.Write([]byte(`GEOMETRYCOLLECTION(`))
+
+		e.keyword("GEOMETRYCOLLECTION")
+		buf.WriteByte('(')
 		for i, c := range g {
 			if i != 0 {
 				buf.WriteByte(',')
 			}
-			Marshal(buf, c)
+			e.geometry(c)
 		}
 		buf.WriteByte(')')
 	case orb.Bound:
-		Marshal(buf, g.ToPolygon())
+		if e.layout == orb.XY {
+			e.geometry(g.ToPolygon())
+			return
+		}
+
+		// the bound's extra values are for the min and max points,
+		// repeat them for each corner of the polygon.
+		stride := e.layout.Stride() - 2
+		min := e.extra[e.index*stride : (e.index+1)*stride]
+		max := e.extra[(e.index+1)*stride : (e.index+2)*stride]
+
+		corners := make([]float64, 0, 5*stride)
+		for _, v := range [][]float64{min, min, max, max, min} {
+			corners = append(corners, v...)
+		}
+
+		be := &encoder{buf: buf, tag: e.tag, layout: e.layout, extra: corners}
+		be.geometry(g.ToPolygon())
+		e.index += 2
 	default:
 		panic("unsupported type")
 	}
 }
 
-func writeLineString(buf *bytes.Buffer, ls orb.LineString) {
-	buf.WriteByte('(')
+func (e *encoder) keyword(name string) {
+	e.buf.WriteString(name)
+	e.buf.WriteString(e.tag)
+}
+
+func (e *encoder) empty(name string) {
+	e.keyword(name)
+	e.buf.WriteString(" EMPTY")
+}
+
+func (e *encoder) lineString(ls orb.LineString) {
+	e.buf.WriteByte('(')
 	for i, p := range ls {
 		if i != 0 {
-			buf.WriteByte(',')
+			e.buf.WriteByte(',')
 		}
 
-		fmt.Fprintf(buf, "%g %g", p[0], p[1])
+		e.point(p)
+	}
+	e.buf.WriteByte(')')
+}
+
+func (e *encoder) point(p orb.Point) {
+	fmt.Fprintf(e.buf, "%g %g", p[0], p[1])
+	if e.layout == orb.XY {
+		return
+	}
+
+	stride := e.layout.Stride() - 2
+	for _, v := range e.extra[e.index*stride : (e.index+1)*stride] {
+		fmt.Fprintf(e.buf, " %g", v)
 	}
-	buf.WriteByte(')')
+	e.index++
 }
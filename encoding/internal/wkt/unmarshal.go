@@ -54,7 +54,20 @@ order:
 	POLYGON
 */
 func Unmarshal(s string) (geom orb.Geometry, err error) {
-	s = strings.ToUpper(strings.Trim(s, " "))
+	if hasZM(s) {
+		g, err := UnmarshalZM(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return g.Geometry, nil
+	}
+
+	return unmarshal(strings.ToUpper(strings.Trim(s, " ")))
+}
+
+func unmarshal(s string) (geom orb.Geometry, err error) {
+	s = strings.Trim(s, " ")
 	switch {
 	case strings.Contains(s, "GEOMETRYCOLLECTION"):
 		if s == "GEOMETRYCOLLECTION " {
@@ -70,7 +83,7 @@ func Unmarshal(s string) (geom orb.Geometry, err error) {
 			if len(v) == 0 {
 				continue
 			}
-			g, err := Unmarshal(v)
+			g, err := unmarshal(v)
 			if err != nil {
 				return nil, ErrWrap(ErrUnMarshaGeometryCollection, err)
 			}
@@ -1,6 +1,9 @@
 package wkt
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestTrimSpaceBrackets(t *testing.T) {
 	cases := []struct {
@@ -37,3 +40,49 @@ func TestTrimSpaceBrackets(t *testing.T) {
 		}
 	}
 }
+
+func TestHasZM(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected bool
+	}{
+		{s: "POINT(1 2)", expected: false},
+		{s: "POLYGON((0 0,1 0,1 1,0 0))", expected: false},
+		{s: "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING EMPTY)", expected: false},
+		{s: "POINT(NaN 2)", expected: false},
+		{s: "POINT Z(1 2 3)", expected: true},
+		{s: "point m(1 2 3)", expected: true},
+		{s: "POINTZM(1 2 3 4)", expected: true},
+		{s: "LINESTRING Z EMPTY", expected: true},
+		{s: "LINESTRING(1 2 3,4 5 6)", expected: true},
+		{s: "POINT(1 2 NaN)", expected: true},
+		{s: "LINESTRING(1 2 3,4 5)", expected: false},
+		{s: "POINT(1 2 x)", expected: false},
+		{s: "GEOMETRYCOLLECTION(POINT(1 2),MULTIPOINT M((1 2 3)))", expected: true},
+		{s: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)))", expected: false},
+	}
+
+	for _, tc := range cases {
+		if v := hasZM(tc.s); v != tc.expected {
+			t.Errorf("%s: incorrect result: %v", tc.s, v)
+		}
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	// data without z or m values should have the errors of the 2d parser.
+	cases := []string{
+		"LINESTRING(1 2 3,4 5)",
+		"LINESTRING(1 2,4 5 6)",
+		"POINT(1 x)",
+		"POINT(1 2 x)",
+	}
+
+	for _, s := range cases {
+		_, err := Unmarshal(s)
+		_, expected := unmarshal(strings.ToUpper(s))
+		if err == nil || expected == nil || err.Error() != expected.Error() {
+			t.Errorf("%s: incorrect error: %v != %v", s, err, expected)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package wkt
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ErrInvalidZM is returned when the z and m values do not match
+// the tag or are not the same for every point.
+var ErrInvalidZM = errors.New("wkt: invalid z or m values")
+
+var tagRegexp = regexp.MustCompile(`\b(POINT|LINESTRING|POLYGON|MULTIPOINT|MULTILINESTRING|MULTIPOLYGON|GEOMETRYCOLLECTION)\s*(ZM|Z|M)\b`)
+
+// UnmarshalZM parses the wkt string keeping any z and m values.
+// The layout comes from the Z, M or ZM tag, e.g. POINT Z(1 2 3), or if
+// missing, from the number of values for each point, 3 is XYZ and 4 is XYZM.
+func UnmarshalZM(s string) (orb.GeometryZM, error) {
+	s = strings.ToUpper(strings.Trim(s, " "))
+
+	layout := orb.XY
+	tagged := false
+	for _, m := range tagRegexp.FindAllStringSubmatch(s, -1) {
+		var l orb.Layout
+		switch m[2] {
+		case "Z":
+			l = orb.XYZ
+		case "M":
+			l = orb.XYM
+		case "ZM":
+			l = orb.XYZM
+		}
+
+		if tagged && l != layout {
+			return orb.GeometryZM{}, ErrInvalidZM
+		}
+		layout, tagged = l, true
+	}
+	s = tagRegexp.ReplaceAllString(s, "$1")
+
+	s, extra, n, err := stripExtra(s)
+	if err != nil {
+		return orb.GeometryZM{}, err
+	}
+
+	if !tagged {
+		switch n {
+		case 0, -1:
+			layout = orb.XY
+		case 1:
+			layout = orb.XYZ
+		case 2:
+			layout = orb.XYZM
+		default:
+			return orb.GeometryZM{}, ErrInvalidZM
+		}
+	} else if n != -1 && n != layout.Stride()-2 {
+		return orb.GeometryZM{}, ErrInvalidZM
+	}
+
+	g, err := unmarshal(s)
+	if err != nil {
+		return orb.GeometryZM{}, err
+	}
+
+	if layout == orb.XY {
+		extra = nil
+	}
+
+	return orb.GeometryZM{Geometry: g, Layout: layout, Extra: extra}, nil
+}
+
+// hasZM returns true if the wkt has a Z, M or ZM tag, or every point has
+// more than two numbers. Plain 2d data, and data with a mix of point sizes,
+// is parsed without the extra work to remove the z and m values.
+func hasZM(s string) bool {
+	count, min, max := 0, -1, 0
+	for i := 0; i <= len(s); i++ {
+		var c byte
+		if i < len(s) {
+			c = s[i]
+		}
+
+		switch {
+		case c == 0 || c == '(' || c == ')' || c == ',':
+			if count == 2 {
+				// a 2d point, the rest can only have a tag.
+				return hasTag(s[i:])
+			}
+
+			if count > 0 {
+				if min == -1 || count < min {
+					min = count
+				}
+				if count > max {
+					max = count
+				}
+			}
+			count = 0
+			continue
+		case isDelimiter(c):
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isDelimiter(s[i]) {
+			i++
+		}
+		token := s[start:i]
+		i--
+
+		if isNumber(token) {
+			count++
+			if count > 2 {
+				if _, err := strconv.ParseFloat(token, 64); err != nil {
+					return false
+				}
+			}
+		} else if isTag(token) {
+			return true
+		}
+	}
+
+	return min > 2 && min == max
+}
+
+func isDelimiter(c byte) bool {
+	return c == '(' || c == ')' || c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// hasTag returns true if there is a Z, M or ZM tag in the wkt.
+func hasTag(s string) bool {
+	for {
+		i := strings.IndexAny(s, "ZzMm")
+		if i == -1 {
+			return false
+		}
+
+		start, end := i, i
+		for start > 0 && !isDelimiter(s[start-1]) {
+			start--
+		}
+		for end < len(s) && !isDelimiter(s[end]) {
+			end++
+		}
+
+		if isTag(s[start:end]) {
+			return true
+		}
+		s = s[end:]
+	}
+}
+
+// isNumber returns true if the token looks like a number, it is not
+// fully parsed. NaN and infinity are numbers.
+func isNumber(s string) bool {
+	switch c := s[0]; {
+	case c >= '0' && c <= '9', c == '-', c == '+', c == '.':
+		return true
+	}
+
+	return strings.EqualFold(s, "nan") || strings.EqualFold(s, "inf") || strings.EqualFold(s, "infinity")
+}
+
+// isTag returns true for a Z, M or ZM tag, with or without the
+// geometry type before it, e.g. POINTZ.
+func isTag(s string) bool {
+	s = strings.ToUpper(s)
+	for _, t := range []string{"POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION"} {
+		if strings.HasPrefix(s, t) {
+			s = s[len(t):]
+			break
+		}
+	}
+
+	return s == "Z" || s == "M" || s == "ZM"
+}
+
+// stripExtra removes any values after x and y from each point. It returns
+// the values in point order and how many there were per point, -1 if
+// there were no points.
+func stripExtra(s string) (string, []float64, int, error) {
+	var (
+		b     strings.Builder
+		extra []float64
+	)
+
+	n := -1
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] != '(' && s[i] != ')' && s[i] != ',' {
+			continue
+		}
+
+		segment := s[start:i]
+		fields := strings.Fields(segment)
+		if len(fields) > 0 && !isKeyword(fields[0]) {
+			if n == -1 {
+				n = len(fields) - 2
+			}
+
+			if n < 0 || len(fields)-2 != n {
+				return "", nil, 0, ErrInvalidZM
+			}
+
+			for _, f := range fields[2:] {
+				v, err := strconv.ParseFloat(f, 64)
+				if err != nil {
+					return "", nil, 0, err
+				}
+				extra = append(extra, v)
+			}
+
+			segment = fields[0] + " " + fields[1]
+		}
+
+		b.WriteString(segment)
+		if i < len(s) {
+			b.WriteByte(s[i])
+		}
+		start = i + 1
+	}
+
+	return b.String(), extra, n, nil
+}
+
+// isKeyword returns true if the field is a geometry type or EMPTY
+// and not a number like NAN.
+func isKeyword(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err != nil
+}
@@ -0,0 +1,418 @@
+package kml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+var (
+	// ErrInvalidCoordinates is returned when the coordinates of a geometry
+	// can not be parsed.
+	ErrInvalidCoordinates = errors.New("kml: invalid coordinates")
+
+	// ErrInvalidZM is returned when encoding a feature and the number of
+	// extra values does not match the points and layout.
+	ErrInvalidZM = errors.New("kml: extra values do not match the geometry and layout")
+)
+
+// Unmarshal decodes all the placemarks in the kml data into a feature collection.
+func Unmarshal(data []byte) (*geojson.FeatureCollection, error) {
+	fc := geojson.NewFeatureCollection()
+
+	d := NewDecoder(bytes.NewReader(data))
+	for {
+		f, err := d.Decode()
+		if err == io.EOF {
+			return fc, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fc.Append(f)
+	}
+}
+
+// A Decoder reads placemarks off of a kml stream, one at a time,
+// so the whole file does not need to be in memory.
+type Decoder struct {
+	d *xml.Decoder
+}
+
+// NewDecoder creates a new Decoder for the given reader.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: xml.NewDecoder(r)}
+}
+
+// Decode returns the next placemark, in any Document or Folder, as a feature.
+// The name, description and ExtendedData values are mapped to properties
+// and the id attribute to the feature ID. Altitudes are kept as the Z
+// values of the feature, see geojson.Feature.GeometryZM.
+// It will return io.EOF when there are no more placemarks.
+func (d *Decoder) Decode() (*geojson.Feature, error) {
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "Placemark" {
+			return d.placemark(se)
+		}
+	}
+}
+
+type extendedData struct {
+	Data []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	} `xml:"Data"`
+	SchemaData []struct {
+		SimpleData []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"SimpleData"`
+	} `xml:"SchemaData"`
+}
+
+func (d *Decoder) placemark(start xml.StartElement) (*geojson.Feature, error) {
+	f := geojson.NewFeature(nil)
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "id" {
+			f.ID = attr.Value
+		}
+	}
+
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "name", "description":
+				var s string
+				if err := d.d.DecodeElement(&s, &t); err != nil {
+					return nil, err
+				}
+				f.Properties[t.Name.Local] = strings.TrimSpace(s)
+			case "ExtendedData":
+				var ed extendedData
+				if err := d.d.DecodeElement(&ed, &t); err != nil {
+					return nil, err
+				}
+
+				for _, data := range ed.Data {
+					f.Properties[data.Name] = data.Value
+				}
+				for _, sd := range ed.SchemaData {
+					for _, data := range sd.SimpleData {
+						f.Properties[data.Name] = data.Value
+					}
+				}
+			case "Point", "LineString", "LinearRing", "Polygon", "MultiGeometry":
+				g, z, err := d.geometry(t)
+				if err != nil {
+					return nil, err
+				}
+
+				f.Geometry = g
+				if hasValue(z) {
+					f.Layout = orb.XYZ
+					f.Extra = z
+				}
+			default:
+				if err := d.d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			return f, nil
+		}
+	}
+}
+
+// geometry reads the geometry element returning the altitude of each point,
+// NaN if not present.
+func (d *Decoder) geometry(start xml.StartElement) (orb.Geometry, []float64, error) {
+	switch start.Name.Local {
+	case "Point":
+		ps, z, err := d.coordinates()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(ps) != 1 {
+			return nil, nil, ErrInvalidCoordinates
+		}
+		return ps[0], z, nil
+	case "LineString":
+		ps, z, err := d.coordinates()
+		return orb.LineString(ps), z, err
+	case "LinearRing":
+		ps, z, err := d.coordinates()
+		return orb.Ring(ps), z, err
+	case "Polygon":
+		return d.polygon()
+	case "MultiGeometry":
+		return d.multiGeometry()
+	}
+
+	return nil, nil, d.d.Skip()
+}
+
+func (d *Decoder) polygon() (orb.Geometry, []float64, error) {
+	var (
+		outer    orb.Ring
+		outerZ   []float64
+		inner    []orb.Ring
+		innerZ   [][]float64
+		hasOuter bool
+	)
+
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "outerBoundaryIs" && t.Name.Local != "innerBoundaryIs" {
+				if err := d.d.Skip(); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+
+			rings, zs, err := d.boundary()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if t.Name.Local == "outerBoundaryIs" && len(rings) > 0 {
+				outer, outerZ, hasOuter = rings[0], zs[0], true
+			} else {
+				inner = append(inner, rings...)
+				innerZ = append(innerZ, zs...)
+			}
+		case xml.EndElement:
+			if !hasOuter {
+				return orb.Polygon{}, nil, nil
+			}
+
+			p := append(orb.Polygon{outer}, inner...)
+			z := outerZ
+			for _, iz := range innerZ {
+				z = append(z, iz...)
+			}
+
+			return p, z, nil
+		}
+	}
+}
+
+// boundary reads the LinearRings of an outer or inner boundary.
+func (d *Decoder) boundary() ([]orb.Ring, [][]float64, error) {
+	var (
+		rings []orb.Ring
+		zs    [][]float64
+	)
+
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "LinearRing" {
+				if err := d.d.Skip(); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+
+			ps, z, err := d.coordinates()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			rings = append(rings, orb.Ring(ps))
+			zs = append(zs, z)
+		case xml.EndElement:
+			return rings, zs, nil
+		}
+	}
+}
+
+// multiGeometry reads the child geometries. If they are all points, lines or
+// polygons the result is a multi geometry of that type, otherwise a collection.
+func (d *Decoder) multiGeometry() (orb.Geometry, []float64, error) {
+	var (
+		c orb.Collection
+		z []float64
+	)
+
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			g, gz, err := d.geometry(t)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if g != nil {
+				c = append(c, g)
+				z = append(z, gz...)
+			}
+		case xml.EndElement:
+			return collapse(c), z, nil
+		}
+	}
+}
+
+func collapse(c orb.Collection) orb.Geometry {
+	if len(c) == 0 {
+		return orb.Collection{}
+	}
+
+	switch c[0].(type) {
+	case orb.Point:
+		mp := make(orb.MultiPoint, 0, len(c))
+		for _, g := range c {
+			p, ok := g.(orb.Point)
+			if !ok {
+				return c
+			}
+			mp = append(mp, p)
+		}
+		return mp
+	case orb.LineString:
+		mls := make(orb.MultiLineString, 0, len(c))
+		for _, g := range c {
+			ls, ok := g.(orb.LineString)
+			if !ok {
+				return c
+			}
+			mls = append(mls, ls)
+		}
+		return mls
+	case orb.Polygon:
+		mp := make(orb.MultiPolygon, 0, len(c))
+		for _, g := range c {
+			p, ok := g.(orb.Polygon)
+			if !ok {
+				return c
+			}
+			mp = append(mp, p)
+		}
+		return mp
+	}
+
+	return c
+}
+
+// coordinates reads the coordinates element of a Point, LineString or LinearRing.
+func (d *Decoder) coordinates() ([]orb.Point, []float64, error) {
+	var (
+		ps []orb.Point
+		z  []float64
+	)
+
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "coordinates" {
+				if err := d.d.Skip(); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+
+			var s string
+			if err := d.d.DecodeElement(&s, &t); err != nil {
+				return nil, nil, err
+			}
+
+			ps, z, err = parseCoordinates(s)
+			if err != nil {
+				return nil, nil, err
+			}
+		case xml.EndElement:
+			return ps, z, nil
+		}
+	}
+}
+
+var commaSpace = regexp.MustCompile(`\s*,\s*`)
+
+// parseCoordinates parses the lon,lat[,alt] tuples separated by whitespace.
+func parseCoordinates(s string) ([]orb.Point, []float64, error) {
+	tuples := strings.Fields(commaSpace.ReplaceAllString(s, ","))
+
+	ps := make([]orb.Point, 0, len(tuples))
+	z := make([]float64, 0, len(tuples))
+	for _, tuple := range tuples {
+		values := strings.Split(tuple, ",")
+		if len(values) < 2 || len(values) > 3 {
+			return nil, nil, ErrInvalidCoordinates
+		}
+
+		var p orb.Point
+		for i := 0; i < 2; i++ {
+			v, err := strconv.ParseFloat(values[i], 64)
+			if err != nil {
+				return nil, nil, ErrInvalidCoordinates
+			}
+			p[i] = v
+		}
+
+		alt := math.NaN()
+		if len(values) == 3 {
+			v, err := strconv.ParseFloat(values[2], 64)
+			if err != nil {
+				return nil, nil, ErrInvalidCoordinates
+			}
+			alt = v
+		}
+
+		ps = append(ps, p)
+		z = append(z, alt)
+	}
+
+	return ps, z, nil
+}
+
+func hasValue(z []float64) bool {
+	for _, v := range z {
+		if !math.IsNaN(v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,276 @@
+package kml
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+const testDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+<Document>
+  <name>Field survey</name>
+  <Style id="red"><LineStyle><color>ff0000ff</color></LineStyle></Style>
+  <Folder>
+    <name>Sites</name>
+    <Placemark id="site-1">
+      <name>Site 1</name>
+      <description><![CDATA[<b>first</b> site]]></description>
+      <styleUrl>#red</styleUrl>
+      <ExtendedData>
+        <Data name="crew"><displayName>Crew</displayName><value>A</value></Data>
+        <SchemaData schemaUrl="#survey">
+          <SimpleData name="depth">12.5</SimpleData>
+        </SchemaData>
+      </ExtendedData>
+      <Point><coordinates>-122.08, 37.42, 10</coordinates></Point>
+    </Placemark>
+  </Folder>
+  <Placemark>
+    <LineString>
+      <tessellate>1</tessellate>
+      <coordinates>
+        1,2 3,4
+        5,6
+      </coordinates>
+    </LineString>
+  </Placemark>
+</Document>
+</kml>`
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder(strings.NewReader(testDoc))
+
+	f, err := d.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if f.ID != "site-1" {
+		t.Errorf("incorrect id: %v", f.ID)
+	}
+
+	if v := f.Properties["name"]; v != "Site 1" {
+		t.Errorf("incorrect name: %v", v)
+	}
+
+	if v := f.Properties["description"]; v != "<b>first</b> site" {
+		t.Errorf("incorrect description: %v", v)
+	}
+
+	if v := f.Properties["crew"]; v != "A" {
+		t.Errorf("incorrect data value: %v", v)
+	}
+
+	if v := f.Properties["depth"]; v != "12.5" {
+		t.Errorf("incorrect simple data value: %v", v)
+	}
+
+	if !orb.Equal(f.Geometry, orb.Point{-122.08, 37.42}) {
+		t.Errorf("incorrect geometry: %v", f.Geometry)
+	}
+
+	if f.Layout != orb.XYZ || len(f.Extra) != 1 || f.Extra[0] != 10 {
+		t.Errorf("incorrect altitude: %v %v", f.Layout, f.Extra)
+	}
+
+	f, err = d.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	expected := orb.LineString{{1, 2}, {3, 4}, {5, 6}}
+	if !orb.Equal(f.Geometry, expected) {
+		t.Errorf("incorrect geometry: %v", f.Geometry)
+	}
+
+	if f.Layout != orb.XY || f.Extra != nil {
+		t.Errorf("should not have altitude: %v %v", f.Layout, f.Extra)
+	}
+
+	if len(f.Properties) != 0 {
+		t.Errorf("should not have properties: %v", f.Properties)
+	}
+
+	_, err = d.Decode()
+	if err != io.EOF {
+		t.Errorf("expected eof, got: %v", err)
+	}
+}
+
+func TestUnmarshal_geometries(t *testing.T) {
+	cases := []struct {
+		name     string
+		kml      string
+		expected orb.Geometry
+	}{
+		{
+			name:     "point",
+			kml:      `<Point><coordinates>1,2</coordinates></Point>`,
+			expected: orb.Point{1, 2},
+		},
+		{
+			name:     "linear ring",
+			kml:      `<LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing>`,
+			expected: orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		},
+		{
+			name: "polygon",
+			kml: `<Polygon>
+				<extrude>1</extrude>
+				<outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,0</coordinates></LinearRing></outerBoundaryIs>
+				<innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>
+			</Polygon>`,
+			expected: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 0}},
+				{{1, 1}, {2, 1}, {2, 2}, {1, 1}},
+			},
+		},
+		{
+			name: "inner boundary first",
+			kml: `<Polygon>
+				<innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>
+				<outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,0</coordinates></LinearRing></outerBoundaryIs>
+			</Polygon>`,
+			expected: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 0}},
+				{{1, 1}, {2, 1}, {2, 2}, {1, 1}},
+			},
+		},
+		{
+			name: "multi point",
+			kml: `<MultiGeometry>
+				<Point><coordinates>1,2</coordinates></Point>
+				<Point><coordinates>3,4</coordinates></Point>
+			</MultiGeometry>`,
+			expected: orb.MultiPoint{{1, 2}, {3, 4}},
+		},
+		{
+			name: "multi line string",
+			kml: `<MultiGeometry>
+				<LineString><coordinates>1,2 3,4</coordinates></LineString>
+				<LineString><coordinates>5,6 7,8</coordinates></LineString>
+			</MultiGeometry>`,
+			expected: orb.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+		},
+		{
+			name: "multi polygon",
+			kml: `<MultiGeometry>
+				<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon>
+				<Polygon><outerBoundaryIs><LinearRing><coordinates>2,2 3,2 3,3 2,2</coordinates></LinearRing></outerBoundaryIs></Polygon>
+			</MultiGeometry>`,
+			expected: orb.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				{{{2, 2}, {3, 2}, {3, 3}, {2, 2}}},
+			},
+		},
+		{
+			name: "mixed",
+			kml: `<MultiGeometry>
+				<Point><coordinates>1,2</coordinates></Point>
+				<MultiGeometry>
+					<LineString><coordinates>1,2 3,4</coordinates></LineString>
+				</MultiGeometry>
+			</MultiGeometry>`,
+			expected: orb.Collection{
+				orb.Point{1, 2},
+				orb.MultiLineString{{{1, 2}, {3, 4}}},
+			},
+		},
+		{
+			name:     "empty multi geometry",
+			kml:      `<MultiGeometry></MultiGeometry>`,
+			expected: orb.Collection{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc, err := Unmarshal([]byte(`<kml><Placemark>` + tc.kml + `</Placemark></kml>`))
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if len(fc.Features) != 1 {
+				t.Fatalf("incorrect number of features: %d", len(fc.Features))
+			}
+
+			if !orb.Equal(fc.Features[0].Geometry, tc.expected) {
+				t.Errorf("incorrect geometry")
+				t.Logf("%v", fc.Features[0].Geometry)
+				t.Logf("%v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_altitude(t *testing.T) {
+	data := `<kml><Placemark><Polygon>
+		<innerBoundaryIs><LinearRing><coordinates>1,1,5 2,1,5 2,2,5 1,1,5</coordinates></LinearRing></innerBoundaryIs>
+		<outerBoundaryIs><LinearRing><coordinates>0,0,1 4,0,2 4,4 0,0,1</coordinates></LinearRing></outerBoundaryIs>
+	</Polygon></Placemark></kml>`
+
+	fc, err := Unmarshal([]byte(data))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	f := fc.Features[0]
+	if f.Layout != orb.XYZ {
+		t.Fatalf("incorrect layout: %v", f.Layout)
+	}
+
+	// outer ring first with NaN for the missing value
+	expected := []float64{1, 2, math.NaN(), 1, 5, 5, 5, 5}
+	if len(f.Extra) != len(expected) {
+		t.Fatalf("incorrect extra: %v", f.Extra)
+	}
+
+	for i := range expected {
+		if math.IsNaN(expected[i]) != math.IsNaN(f.Extra[i]) ||
+			(!math.IsNaN(expected[i]) && expected[i] != f.Extra[i]) {
+			t.Errorf("incorrect extra: %v", f.Extra)
+		}
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		kml  string
+		err  error
+	}{
+		{
+			name: "bad number",
+			kml:  `<kml><Placemark><Point><coordinates>1,a</coordinates></Point></Placemark></kml>`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "one value",
+			kml:  `<kml><Placemark><LineString><coordinates>1,2 3</coordinates></LineString></Placemark></kml>`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "multiple points",
+			kml:  `<kml><Placemark><Point><coordinates>1,2 3,4</coordinates></Point></Placemark></kml>`,
+			err:  ErrInvalidCoordinates,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tc.kml))
+			if err != tc.err {
+				t.Errorf("incorrect error: %v != %v", err, tc.err)
+			}
+		})
+	}
+
+	_, err := Unmarshal([]byte(`<kml><Placemark><Point><coordinates>1,2</coordinates>`))
+	if err == nil {
+		t.Errorf("truncated data should error")
+	}
+}
@@ -0,0 +1,337 @@
+package kml
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// Namespace is the KML 2.2 namespace written on the root element.
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+// Marshal encodes the feature collection as a KML document with a
+// placemark for each feature.
+func Marshal(fc *geojson.FeatureCollection) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	e := NewEncoder(buf)
+	for _, f := range fc.Features {
+		if err := e.Encode(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// An Encoder writes features as placemarks of a KML document.
+// Close must be called to finish the document.
+type Encoder struct {
+	w       io.Writer
+	e       *xml.Encoder
+	started bool
+}
+
+// NewEncoder creates a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+
+	return &Encoder{w: w, e: e}
+}
+
+// Encode writes the feature as a placemark. The "name" and "description"
+// properties become the matching elements, the rest are written as
+// ExtendedData. Z values of the feature are written as the altitude.
+func (e *Encoder) Encode(f *geojson.Feature) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: "Placemark"}}
+	if f.ID != nil {
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "id"}, Value: fmt.Sprint(f.ID)}}
+	}
+
+	if err := e.e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if err := e.properties(f.Properties); err != nil {
+		return err
+	}
+
+	if f.Geometry != nil {
+		g := f.GeometryZM()
+		if !g.Valid() {
+			return ErrInvalidZM
+		}
+
+		ge := &geometryEncoder{Encoder: e, g: g}
+		if err := ge.geometry(f.Geometry); err != nil {
+			return err
+		}
+	}
+
+	return e.e.EncodeToken(start.End())
+}
+
+// Close finishes the document and flushes it to the writer.
+func (e *Encoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	err := e.e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "Document"}})
+	if err != nil {
+		return err
+	}
+
+	err = e.e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "kml"}})
+	if err != nil {
+		return err
+	}
+
+	return e.e.Flush()
+}
+
+func (e *Encoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	// nothing has been written to the xml encoder yet
+	// so write the header directly.
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+
+	err := e.e.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "kml"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.e.EncodeToken(xml.StartElement{Name: xml.Name{Local: "Document"}})
+}
+
+func (e *Encoder) properties(props geojson.Properties) error {
+	for _, key := range []string{"name", "description"} {
+		if v, ok := props[key]; ok && v != nil {
+			if err := e.text(key, toString(v)); err != nil {
+				return err
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if k != "name" && k != "description" {
+			keys = append(keys, k)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	ed := xml.StartElement{Name: xml.Name{Local: "ExtendedData"}}
+	if err := e.e.EncodeToken(ed); err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		data := xml.StartElement{
+			Name: xml.Name{Local: "Data"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: k}},
+		}
+		if err := e.e.EncodeToken(data); err != nil {
+			return err
+		}
+
+		if err := e.text("value", toString(props[k])); err != nil {
+			return err
+		}
+
+		if err := e.e.EncodeToken(data.End()); err != nil {
+			return err
+		}
+	}
+
+	return e.e.EncodeToken(ed.End())
+}
+
+func (e *Encoder) text(name, value string) error {
+	return e.e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// toString converts a property value to its KML text. Maps and slices
+// are written as json.
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool, int, int64, json.Number:
+		return fmt.Sprint(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// geometryEncoder writes the geometry, taking the altitude of each point,
+// in order, from the z values of g.
+type geometryEncoder struct {
+	*Encoder
+	g     orb.GeometryZM
+	index int
+}
+
+func (e *geometryEncoder) geometry(g orb.Geometry) error {
+	switch g := g.(type) {
+	case orb.Point:
+		return e.element("Point", func() error {
+			return e.coordinates([]orb.Point{g})
+		})
+	case orb.MultiPoint:
+		return e.element("MultiGeometry", func() error {
+			for _, p := range g {
+				if err := e.geometry(p); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case orb.LineString:
+		return e.element("LineString", func() error {
+			return e.coordinates(g)
+		})
+	case orb.MultiLineString:
+		return e.element("MultiGeometry", func() error {
+			for _, ls := range g {
+				if err := e.geometry(ls); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case orb.Ring:
+		return e.ring(g)
+	case orb.Polygon:
+		return e.element("Polygon", func() error {
+			for i, r := range g {
+				name := "innerBoundaryIs"
+				if i == 0 {
+					name = "outerBoundaryIs"
+				}
+
+				err := e.element(name, func() error {
+					return e.ring(r)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case orb.MultiPolygon:
+		return e.element("MultiGeometry", func() error {
+			for _, p := range g {
+				if err := e.geometry(p); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case orb.Collection:
+		return e.element("MultiGeometry", func() error {
+			for _, c := range g {
+				if err := e.geometry(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case orb.Bound:
+		// the z values of a bound are for the min and max points,
+		// repeat them for the corners of the polygon.
+		be := &geometryEncoder{Encoder: e.Encoder, g: orb.GeometryZM{Geometry: g.ToPolygon()}}
+		if e.g.Layout.HasZ() {
+			min, max := e.g.Z(e.index), e.g.Z(e.index+1)
+			be.g.Layout = orb.XYZ
+			be.g.Extra = []float64{min, min, max, max, min}
+		}
+		e.index += 2
+
+		return be.geometry(be.g.Geometry)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func (e *geometryEncoder) ring(r orb.Ring) error {
+	return e.element("LinearRing", func() error {
+		return e.coordinates(r)
+	})
+}
+
+func (e *geometryEncoder) element(name string, inner func() error) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if err := inner(); err != nil {
+		return err
+	}
+
+	return e.e.EncodeToken(start.End())
+}
+
+func (e *geometryEncoder) coordinates(ps []orb.Point) error {
+	var sb strings.Builder
+	for i, p := range ps {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(strconv.FormatFloat(p[0], 'f', -1, 64))
+		sb.WriteByte(',')
+		sb.WriteString(strconv.FormatFloat(p[1], 'f', -1, 64))
+
+		if e.g.Layout.HasZ() {
+			if z := e.g.Z(e.index); !math.IsNaN(z) {
+				sb.WriteByte(',')
+				sb.WriteString(strconv.FormatFloat(z, 'f', -1, 64))
+			}
+		}
+		e.index++
+	}
+
+	return e.text("coordinates", sb.String())
+}
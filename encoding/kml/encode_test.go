@@ -0,0 +1,183 @@
+package kml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func TestMarshal(t *testing.T) {
+	f := geojson.NewFeature(orb.LineString{{1, 2}, {3.5, 4}})
+	f.ID = 7
+	f.Properties["name"] = "A & B"
+	f.Properties["lanes"] = 2.0
+	f.Properties["tags"] = []interface{}{"a", "b"}
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(f)
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Placemark id="7">
+      <name>A &amp; B</name>
+      <ExtendedData>
+        <Data name="lanes">
+          <value>2</value>
+        </Data>
+        <Data name="tags">
+          <value>[&#34;a&#34;,&#34;b&#34;]</value>
+        </Data>
+      </ExtendedData>
+      <LineString>
+        <coordinates>1,2 3.5,4</coordinates>
+      </LineString>
+    </Placemark>
+  </Document>
+</kml>`
+
+	if string(data) != expected {
+		t.Errorf("incorrect kml")
+		t.Logf("%s", data)
+	}
+}
+
+func TestMarshal_empty(t *testing.T) {
+	data, err := Marshal(geojson.NewFeatureCollection())
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	fc, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(fc.Features) != 0 {
+		t.Errorf("should have no features: %v", fc.Features)
+	}
+}
+
+func TestMarshal_roundTrip(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		t.Run(fmt.Sprintf("%T", g), func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			fc.Append(geojson.NewFeature(g))
+
+			data, err := Marshal(fc)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			result, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if len(result.Features) != 1 {
+				t.Fatalf("incorrect number of features: %d", len(result.Features))
+			}
+		})
+	}
+
+	geoms := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+		orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{2, 2}, {3, 2}, {3, 3}, {2, 2}}}},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}},
+	}
+
+	for _, g := range geoms {
+		t.Run(g.GeoJSONType(), func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			fc.Append(geojson.NewFeature(g))
+
+			data, err := Marshal(fc)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			result, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !orb.Equal(result.Features[0].Geometry, g) {
+				t.Errorf("incorrect geometry: %v", result.Features[0].Geometry)
+			}
+		})
+	}
+}
+
+func TestMarshal_altitude(t *testing.T) {
+	f := geojson.NewFeatureZM(orb.GeometryZM{
+		Geometry: orb.MultiPoint{{1, 2}, {3, 4}},
+		Layout:   orb.XYZM,
+		Extra:    []float64{10, 100, 20, 200},
+	})
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(f)
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<coordinates>1,2,10</coordinates>") ||
+		!strings.Contains(string(data), "<coordinates>3,4,20</coordinates>") {
+		t.Errorf("altitudes not written: %s", data)
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	r := result.Features[0]
+	if r.Layout != orb.XYZ || len(r.Extra) != 2 || r.Extra[0] != 10 || r.Extra[1] != 20 {
+		t.Errorf("incorrect altitudes: %v %v", r.Layout, r.Extra)
+	}
+}
+
+func TestMarshal_bound(t *testing.T) {
+	f := geojson.NewFeatureZM(orb.GeometryZM{
+		Geometry: orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+		Layout:   orb.XYZ,
+		Extra:    []float64{1, 2},
+	})
+
+	fc := geojson.NewFeatureCollection()
+	fc.Append(f)
+
+	data, err := Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<coordinates>0,0,1 1,0,1 1,1,2 0,1,2 0,0,1</coordinates>") {
+		t.Errorf("incorrect bound: %s", data)
+	}
+}
+
+func TestEncoder_invalidZM(t *testing.T) {
+	f := geojson.NewFeature(orb.Point{1, 2})
+	f.Layout = orb.XYZ
+
+	err := NewEncoder(&strings.Builder{}).Encode(f)
+	if err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
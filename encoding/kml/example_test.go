@@ -0,0 +1,66 @@
+package kml_test
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/kml"
+	"github.com/paulmach/orb/geojson"
+)
+
+func ExampleDecoder() {
+	r := strings.NewReader(`<kml xmlns="http://www.opengis.net/kml/2.2"><Document>
+		<Placemark>
+			<name>Camp</name>
+			<Point><coordinates>-122.08,37.42,120</coordinates></Point>
+		</Placemark>
+	</Document></kml>`)
+
+	d := kml.NewDecoder(r)
+	for {
+		f, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Fatalf("decode error: %v", err)
+		}
+
+		fmt.Println(f.Properties["name"], f.Geometry, f.GeometryZM().Z(0))
+	}
+
+	// Output:
+	// Camp [-122.08 37.42] 120
+}
+
+func ExampleEncoder() {
+	f := geojson.NewFeature(orb.Point{-122.08, 37.42})
+	f.Properties["name"] = "Camp"
+
+	e := kml.NewEncoder(os.Stdout)
+	if err := e.Encode(f); err != nil {
+		log.Fatalf("encode error: %v", err)
+	}
+
+	if err := e.Close(); err != nil {
+		log.Fatalf("close error: %v", err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <kml xmlns="http://www.opengis.net/kml/2.2">
+	//   <Document>
+	//     <Placemark>
+	//       <name>Camp</name>
+	//       <Point>
+	//         <coordinates>-122.08,37.42</coordinates>
+	//       </Point>
+	//     </Placemark>
+	//   </Document>
+	// </kml>
+}
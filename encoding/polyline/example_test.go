@@ -0,0 +1,38 @@
+package polyline_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/polyline"
+)
+
+func ExampleEncode() {
+	ls := orb.LineString{{-120.2, 38.5}, {-120.95, 40.7}, {-126.453, 43.252}}
+
+	fmt.Println(polyline.Encode(ls))
+	fmt.Println(polyline.Encode(ls, polyline.Precision(6)))
+
+	// Output:
+	// _p~iF~ps|U_ulLnnqC_mqNvxq`@
+	// _izlhA~rlgdF_{geC~ywl@_kwzCn`{nI
+}
+
+func ExampleDecoder() {
+	d := polyline.NewDecoder(strings.NewReader("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+
+	for {
+		p, err := d.Decode()
+		if err != nil {
+			break
+		}
+
+		fmt.Println(p)
+	}
+
+	// Output:
+	// [-120.2 38.5]
+	// [-120.95 40.7]
+	// [-126.453 43.252]
+}
@@ -0,0 +1,36 @@
+package polyline
+
+import "math"
+
+type options struct {
+	factor float64
+	lonLat bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{factor: 1e5}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// An Option is a possible parameter to the encode and decode functions.
+type Option func(*options)
+
+// Precision sets the number of decimal places kept. The default is 5,
+// as used by Google. OSRM and Valhalla can also use 6.
+func Precision(p int) Option {
+	return func(o *options) {
+		o.factor = math.Pow10(p)
+	}
+}
+
+// LonLat is an option to encode the values as lon/lat instead of the
+// default lat/lon order used by Google.
+func LonLat(yes bool) Option {
+	return func(o *options) {
+		o.lonLat = yes
+	}
+}
@@ -0,0 +1,204 @@
+// Package polyline is for encoding and decoding Google's encoded polyline
+// algorithm format as described at
+// https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+package polyline
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ErrInvalidPolyline is returned when decoding and the data is not valid.
+var ErrInvalidPolyline = errors.New("polyline: invalid data")
+
+// Encode returns the line string encoded as a polyline.
+func Encode(ls orb.LineString, opts ...Option) string {
+	var sb strings.Builder
+	sb.Grow(len(ls) * 10)
+
+	e := NewEncoder(&sb, opts...)
+	for _, p := range ls {
+		e.Encode(p)
+	}
+
+	return sb.String()
+}
+
+// EncodeMultiLineString returns each line string encoded as a polyline.
+func EncodeMultiLineString(mls orb.MultiLineString, opts ...Option) []string {
+	result := make([]string, 0, len(mls))
+	for _, ls := range mls {
+		result = append(result, Encode(ls, opts...))
+	}
+
+	return result
+}
+
+// Decode returns the line string encoded in the polyline.
+func Decode(s string, opts ...Option) (orb.LineString, error) {
+	ls := make(orb.LineString, 0, len(s)/4)
+
+	d := NewDecoder(strings.NewReader(s), opts...)
+	for {
+		p, err := d.Decode()
+		if err == io.EOF {
+			return ls, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ls = append(ls, p)
+	}
+}
+
+// DecodeMultiLineString returns the line strings encoded in the polylines.
+func DecodeMultiLineString(s []string, opts ...Option) (orb.MultiLineString, error) {
+	result := make(orb.MultiLineString, 0, len(s))
+	for _, v := range s {
+		ls, err := Decode(v, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, ls)
+	}
+
+	return result, nil
+}
+
+// An Encoder writes the points of a polyline to the writer given at
+// creation time, one point at a time.
+type Encoder struct {
+	w    io.Writer
+	opts *options
+	last [2]int64
+	buf  []byte
+}
+
+// NewEncoder creates a new Encoder for the given writer.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{
+		w:    w,
+		opts: newOptions(opts),
+		buf:  make([]byte, 0, 24),
+	}
+}
+
+// Encode writes the point as the delta from the previous point.
+func (e *Encoder) Encode(p orb.Point) error {
+	first, second := p[1], p[0]
+	if e.opts.lonLat {
+		first, second = p[0], p[1]
+	}
+
+	a := int64(math.Round(first * e.opts.factor))
+	b := int64(math.Round(second * e.opts.factor))
+
+	e.buf = appendValue(e.buf[:0], a-e.last[0])
+	e.buf = appendValue(e.buf, b-e.last[1])
+	e.last[0], e.last[1] = a, b
+
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+func appendValue(buf []byte, v int64) []byte {
+	u := uint64(v<<1) ^ uint64(v>>63)
+	for u >= 0x20 {
+		buf = append(buf, byte(0x20|u&0x1f)+63)
+		u >>= 5
+	}
+
+	return append(buf, byte(u)+63)
+}
+
+// A Decoder reads the points of a polyline off of the stream,
+// one point at a time. This allows for decoding very long traces
+// without holding all the data in memory.
+type Decoder struct {
+	r    io.ByteReader
+	opts *options
+	last [2]int64
+}
+
+// NewDecoder creates a new Decoder for the given reader.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &Decoder{
+		r:    br,
+		opts: newOptions(opts),
+	}
+}
+
+// Decode returns the next point of the polyline. It will return
+// io.EOF when there are no more points.
+func (d *Decoder) Decode() (orb.Point, error) {
+	a, err := d.value(true)
+	if err != nil {
+		return orb.Point{}, err
+	}
+
+	b, err := d.value(false)
+	if err != nil {
+		return orb.Point{}, err
+	}
+
+	d.last[0] += a
+	d.last[1] += b
+
+	first := float64(d.last[0]) / d.opts.factor
+	second := float64(d.last[1]) / d.opts.factor
+	if d.opts.lonLat {
+		return orb.Point{first, second}, nil
+	}
+
+	return orb.Point{second, first}, nil
+}
+
+// value reads the next varint encoded value. The end of the data
+// is only valid at the start of a point.
+func (d *Decoder) value(start bool) (int64, error) {
+	var (
+		u     uint64
+		shift uint
+	)
+
+	for i := 0; ; i++ {
+		c, err := d.r.ReadByte()
+		if err == io.EOF {
+			if start && i == 0 {
+				return 0, io.EOF
+			}
+			return 0, ErrInvalidPolyline
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if c < 63 || c > 127 || shift > 60 {
+			return 0, ErrInvalidPolyline
+		}
+
+		c -= 63
+		u |= uint64(c&0x1f) << shift
+		shift += 5
+
+		if c < 0x20 {
+			break
+		}
+	}
+
+	return int64(u>>1) ^ -int64(u&1), nil
+}
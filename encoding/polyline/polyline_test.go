@@ -0,0 +1,154 @@
+package polyline
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// example from https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+var (
+	googleLineString = orb.LineString{{-120.2, 38.5}, {-120.95, 40.7}, {-126.453, 43.252}}
+	googlePolyline   = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+)
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		name     string
+		ls       orb.LineString
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "google example",
+			ls:       googleLineString,
+			expected: googlePolyline,
+		},
+		{
+			name:     "lon lat",
+			ls:       orb.LineString{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+			opts:     []Option{LonLat(true)},
+			expected: googlePolyline,
+		},
+		{
+			name:     "precision 6",
+			ls:       orb.LineString{{-120.2, 38.5}, {-120.95, 40.7}},
+			opts:     []Option{Precision(6)},
+			expected: "_izlhA~rlgdF_{geC~ywl@",
+		},
+		{
+			name:     "empty",
+			ls:       orb.LineString{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := Encode(tc.ls, tc.opts...)
+			if v != tc.expected {
+				t.Errorf("incorrect polyline: %v != %v", v, tc.expected)
+			}
+
+			ls, err := Decode(v, tc.opts...)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+
+			if !ls.Equal(tc.ls) {
+				t.Errorf("incorrect line string: %v", ls)
+			}
+		})
+	}
+}
+
+func TestEncode_rounding(t *testing.T) {
+	ls := orb.LineString{{-122.4194155, 37.7749295}, {0.000004, -0.000006}}
+
+	v, err := Decode(Encode(ls))
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	expected := orb.LineString{{-122.41942, 37.77493}, {0, -0.00001}}
+	if !v.Equal(expected) {
+		t.Errorf("incorrect line string: %v", v)
+	}
+}
+
+func TestMultiLineString(t *testing.T) {
+	mls := orb.MultiLineString{googleLineString, {{1, 2}, {3, 4}}}
+
+	encoded := EncodeMultiLineString(mls)
+	if len(encoded) != 2 || encoded[0] != googlePolyline {
+		t.Errorf("incorrect polylines: %v", encoded)
+	}
+
+	v, err := DecodeMultiLineString(encoded)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if !v.Equal(mls) {
+		t.Errorf("incorrect multi line string: %v", v)
+	}
+
+	_, err = DecodeMultiLineString([]string{googlePolyline, "_p~iF"})
+	if err != ErrInvalidPolyline {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestDecode_errors(t *testing.T) {
+	cases := []string{
+		"_p~iF",           // only latitude
+		"_p~iF~ps|",       // truncated
+		"_p~iF~ps U",      // invalid character
+		"~~~~~~~~~~~~~~~", // overflow
+	}
+
+	for _, c := range cases {
+		if _, err := Decode(c); err != ErrInvalidPolyline {
+			t.Errorf("incorrect error for %q: %v", c, err)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	// not a byte reader
+	r := io.MultiReader(strings.NewReader(googlePolyline[:5]), strings.NewReader(googlePolyline[5:]))
+
+	d := NewDecoder(r)
+	for i, expected := range googleLineString {
+		p, err := d.Decode()
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		if !p.Equal(expected) {
+			t.Errorf("incorrect point %d: %v", i, p)
+		}
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected eof: %v", err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	e := NewEncoder(buf)
+	for _, p := range googleLineString {
+		if err := e.Encode(p); err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+	}
+
+	if v := buf.String(); v != googlePolyline {
+		t.Errorf("incorrect polyline: %v", v)
+	}
+}
@@ -0,0 +1,227 @@
+package topojson
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// ToFeatureCollections converts each object into a feature collection.
+// For GeometryCollection objects each geometry becomes a feature,
+// other objects become a collection with one feature.
+func (t *Topology) ToFeatureCollections() (map[string]*geojson.FeatureCollection, error) {
+	d := &decoder{topology: t}
+	if err := d.decodeArcs(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*geojson.FeatureCollection, len(t.Objects))
+	for name, obj := range t.Objects {
+		if obj == nil {
+			continue
+		}
+
+		fc, err := d.featureCollection(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = fc
+	}
+
+	return result, nil
+}
+
+// ToFeatureCollection converts the named object into a feature collection.
+// It returns nil if the object does not exist.
+func (t *Topology) ToFeatureCollection(name string) (*geojson.FeatureCollection, error) {
+	obj, ok := t.Objects[name]
+	if !ok || obj == nil {
+		return nil, nil
+	}
+
+	d := &decoder{topology: t}
+	if err := d.decodeArcs(); err != nil {
+		return nil, err
+	}
+
+	return d.featureCollection(obj)
+}
+
+type decoder struct {
+	topology *Topology
+	arcs     []orb.LineString
+}
+
+func (d *decoder) featureCollection(obj *Geometry) (*geojson.FeatureCollection, error) {
+	objects := []*Geometry{obj}
+	if obj.Type == "GeometryCollection" {
+		objects = obj.Geometries
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for _, o := range objects {
+		if o == nil {
+			return nil, ErrInvalidGeometry
+		}
+
+		g, err := d.geometry(o)
+		if err != nil {
+			return nil, err
+		}
+
+		f := geojson.NewFeature(g)
+		f.ID = o.ID
+		for k, v := range o.Properties {
+			f.Properties[k] = v
+		}
+
+		fc.Append(f)
+	}
+
+	return fc, nil
+}
+
+// decodeArcs converts the arcs to the original coordinates,
+// removing the delta encoding and applying the transform.
+func (d *decoder) decodeArcs() error {
+	tr := d.topology.Transform
+
+	d.arcs = make([]orb.LineString, 0, len(d.topology.Arcs))
+	for _, arc := range d.topology.Arcs {
+		if len(arc) == 0 {
+			return ErrInvalidTopology
+		}
+
+		ls := make(orb.LineString, len(arc))
+		var x, y float64
+		for i, p := range arc {
+			if tr == nil {
+				ls[i] = p
+				continue
+			}
+
+			x += p[0]
+			y += p[1]
+			ls[i] = tr.apply(orb.Point{x, y})
+		}
+
+		d.arcs = append(d.arcs, ls)
+	}
+
+	return nil
+}
+
+func (d *decoder) geometry(g *Geometry) (orb.Geometry, error) {
+	switch g.Type {
+	case "":
+		return nil, nil
+	case "Point":
+		return d.point(g.Point), nil
+	case "MultiPoint":
+		mp := make(orb.MultiPoint, 0, len(g.MultiPoint))
+		for _, p := range g.MultiPoint {
+			mp = append(mp, d.point(p))
+		}
+		return mp, nil
+	case "LineString":
+		return d.line(g.LineString)
+	case "MultiLineString":
+		mls := make(orb.MultiLineString, 0, len(g.MultiLineString))
+		for _, arcs := range g.MultiLineString {
+			ls, err := d.line(arcs)
+			if err != nil {
+				return nil, err
+			}
+			mls = append(mls, ls)
+		}
+		return mls, nil
+	case "Polygon":
+		return d.polygon(g.Polygon)
+	case "MultiPolygon":
+		mp := make(orb.MultiPolygon, 0, len(g.MultiPolygon))
+		for _, rings := range g.MultiPolygon {
+			p, err := d.polygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, p)
+		}
+		return mp, nil
+	case "GeometryCollection":
+		c := make(orb.Collection, 0, len(g.Geometries))
+		for _, child := range g.Geometries {
+			if child == nil {
+				return nil, ErrInvalidGeometry
+			}
+
+			cg, err := d.geometry(child)
+			if err != nil {
+				return nil, err
+			}
+
+			if cg != nil {
+				c = append(c, cg)
+			}
+		}
+		return c, nil
+	}
+
+	return nil, ErrInvalidGeometry
+}
+
+func (d *decoder) point(p orb.Point) orb.Point {
+	if d.topology.Transform == nil {
+		return p
+	}
+
+	return d.topology.Transform.apply(p)
+}
+
+func (d *decoder) polygon(rings [][]int) (orb.Polygon, error) {
+	p := make(orb.Polygon, 0, len(rings))
+	for _, arcs := range rings {
+		ls, err := d.line(arcs)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, orb.Ring(ls))
+	}
+
+	return p, nil
+}
+
+// line joins the arcs, the first point of each arc after the first
+// is the same as the last point of the previous arc.
+func (d *decoder) line(arcs []int) (orb.LineString, error) {
+	var ls orb.LineString
+	for _, i := range arcs {
+		reverse := i < 0
+		if reverse {
+			i = ^i
+		}
+
+		if i >= len(d.arcs) {
+			return nil, ErrInvalidTopology
+		}
+
+		arc := d.arcs[i]
+		start := 0
+		if len(ls) > 0 {
+			start = 1
+		}
+
+		for j := start; j < len(arc); j++ {
+			if reverse {
+				ls = append(ls, arc[len(arc)-1-j])
+			} else {
+				ls = append(ls, arc[j])
+			}
+		}
+	}
+
+	if ls == nil {
+		ls = orb.LineString{}
+	}
+
+	return ls, nil
+}
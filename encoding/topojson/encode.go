@@ -0,0 +1,431 @@
+package topojson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+// New creates a topology with an object, a GeometryCollection,
+// for each feature collection. The lines and rings are cut where they
+// meet other lines and the shared arcs are only stored once.
+// Rings are rotated to start at one of these junctions, so they may
+// start at a different point when converted back. The objects are built
+// in order of their names so the arcs are the same for the same input.
+func New(collections map[string]*geojson.FeatureCollection, opts ...Option) *Topology {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	b := &builder{
+		byStart: make(map[orb.Point][]int),
+	}
+
+	t := &Topology{
+		Type:    "Topology",
+		Objects: make(map[string]*Geometry, len(collections)),
+		Arcs:    []orb.LineString{},
+	}
+
+	bound, ok := collectionsBound(collections)
+	if ok {
+		t.BBox = []float64{bound.Min[0], bound.Min[1], bound.Max[0], bound.Max[1]}
+		if o.quantization > 0 {
+			t.Transform = newTransform(bound, o.quantization)
+			b.transform = t.Transform
+		}
+	}
+
+	// the arcs are numbered in the order they're found, so the
+	// collections are added in order to have the same result every time.
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fc := collections[name]
+		obj := &Geometry{
+			Type:       "GeometryCollection",
+			Geometries: make([]*Geometry, 0, len(fc.Features)),
+		}
+
+		for _, f := range fc.Features {
+			g := b.geometry(f.Geometry)
+			g.ID = f.ID
+			if len(f.Properties) > 0 {
+				g.Properties = f.Properties
+			}
+
+			obj.Geometries = append(obj.Geometries, g)
+		}
+
+		t.Objects[name] = obj
+	}
+
+	b.cut()
+	for _, obj := range t.Objects {
+		b.replaceLines(obj)
+	}
+
+	t.Arcs = b.arcs
+	if t.Transform != nil {
+		for _, arc := range t.Arcs {
+			deltaEncode(arc)
+		}
+	}
+
+	return t
+}
+
+func collectionsBound(collections map[string]*geojson.FeatureCollection) (orb.Bound, bool) {
+	var (
+		bound orb.Bound
+		found bool
+	)
+
+	for _, fc := range collections {
+		for _, f := range fc.Features {
+			if f.Geometry == nil || orb.PointCount(f.Geometry) == 0 {
+				continue
+			}
+
+			if !found {
+				bound = f.Geometry.Bound()
+				found = true
+			} else {
+				bound = bound.Union(f.Geometry.Bound())
+			}
+		}
+	}
+
+	return bound, found
+}
+
+func newTransform(bound orb.Bound, n int) *Transform {
+	t := &Transform{
+		Scale:     [2]float64{1, 1},
+		Translate: [2]float64{bound.Min[0], bound.Min[1]},
+	}
+
+	for i := 0; i < 2; i++ {
+		if d := bound.Max[i] - bound.Min[i]; d > 0 {
+			t.Scale[i] = d / float64(n-1)
+		}
+	}
+
+	return t
+}
+
+func (t *Transform) quantize(p orb.Point) orb.Point {
+	return orb.Point{
+		math.Round((p[0] - t.Translate[0]) / t.Scale[0]),
+		math.Round((p[1] - t.Translate[1]) / t.Scale[1]),
+	}
+}
+
+func (t *Transform) apply(p orb.Point) orb.Point {
+	return orb.Point{
+		p[0]*t.Scale[0] + t.Translate[0],
+		p[1]*t.Scale[1] + t.Translate[1],
+	}
+}
+
+func deltaEncode(ls orb.LineString) {
+	for i := len(ls) - 1; i > 0; i-- {
+		ls[i] = orb.Point{ls[i][0] - ls[i-1][0], ls[i][1] - ls[i-1][1]}
+	}
+}
+
+// A line is a line string or a closed ring of the input geometry.
+type line struct {
+	points orb.LineString
+	ring   bool
+	arcs   []int
+}
+
+type neighbors struct {
+	prev, next orb.Point
+}
+
+// builder collects the lines of the geometries and cuts them into arcs.
+// The geometry objects first reference the line index, these are replaced
+// with the arc indexes once all the lines are known.
+type builder struct {
+	transform *Transform
+	lines     []*line
+
+	arcs    []orb.LineString
+	byStart map[orb.Point][]int
+}
+
+func (b *builder) geometry(g orb.Geometry) *Geometry {
+	switch g := g.(type) {
+	case nil:
+		return &Geometry{}
+	case orb.Point:
+		return &Geometry{Type: "Point", Point: b.point(g)}
+	case orb.MultiPoint:
+		mp := make(orb.MultiPoint, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, b.point(p))
+		}
+		return &Geometry{Type: "MultiPoint", MultiPoint: mp}
+	case orb.LineString:
+		return &Geometry{Type: "LineString", LineString: b.line(g, false)}
+	case orb.MultiLineString:
+		mls := make([][]int, 0, len(g))
+		for _, ls := range g {
+			mls = append(mls, b.line(ls, false))
+		}
+		return &Geometry{Type: "MultiLineString", MultiLineString: mls}
+	case orb.Ring:
+		return b.geometry(orb.Polygon{g})
+	case orb.Polygon:
+		return &Geometry{Type: "Polygon", Polygon: b.polygon(g)}
+	case orb.MultiPolygon:
+		mp := make([][][]int, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, b.polygon(p))
+		}
+		return &Geometry{Type: "MultiPolygon", MultiPolygon: mp}
+	case orb.Collection:
+		c := make([]*Geometry, 0, len(g))
+		for _, child := range g {
+			c = append(c, b.geometry(child))
+		}
+		return &Geometry{Type: "GeometryCollection", Geometries: c}
+	case orb.Bound:
+		return b.geometry(g.ToPolygon())
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func (b *builder) point(p orb.Point) orb.Point {
+	if b.transform == nil {
+		return p
+	}
+
+	return b.transform.quantize(p)
+}
+
+func (b *builder) polygon(p orb.Polygon) [][]int {
+	result := make([][]int, 0, len(p))
+	for _, r := range p {
+		result = append(result, b.line(orb.LineString(r), true))
+	}
+
+	return result
+}
+
+// line adds the line and returns a placeholder, the line index,
+// to be replaced by the arcs.
+func (b *builder) line(ls orb.LineString, ring bool) []int {
+	points := make(orb.LineString, 0, len(ls)+1)
+	for _, p := range ls {
+		p = b.point(p)
+
+		// quantizing can create duplicates
+		if len(points) > 0 && points[len(points)-1] == p {
+			continue
+		}
+		points = append(points, p)
+	}
+
+	if ring && len(points) > 0 && points[0] != points[len(points)-1] {
+		points = append(points, points[0])
+	}
+
+	b.lines = append(b.lines, &line{points: points, ring: ring})
+	return []int{len(b.lines) - 1}
+}
+
+// cut finds the junctions, points where lines meet or split, and cuts
+// the lines into arcs at them. Shared arcs are only added once.
+func (b *builder) cut() {
+	junctions := make(map[orb.Point]bool)
+	seen := make(map[orb.Point]neighbors)
+
+	visit := func(p, prev, next orb.Point) {
+		if junctions[p] {
+			return
+		}
+
+		n, ok := seen[p]
+		if !ok {
+			seen[p] = neighbors{prev: prev, next: next}
+			return
+		}
+
+		if (n.prev != prev || n.next != next) && (n.prev != next || n.next != prev) {
+			junctions[p] = true
+		}
+	}
+
+	for _, l := range b.lines {
+		ps := l.points
+		if len(ps) == 0 {
+			continue
+		}
+
+		if l.ring {
+			// the last point is the same as the first
+			m := len(ps) - 1
+			for i := 0; i < m; i++ {
+				visit(ps[i], ps[(i+m-1)%m], ps[(i+1)%m])
+			}
+			continue
+		}
+
+		junctions[ps[0]] = true
+		junctions[ps[len(ps)-1]] = true
+		for i := 1; i < len(ps)-1; i++ {
+			visit(ps[i], ps[i-1], ps[i+1])
+		}
+	}
+
+	for _, l := range b.lines {
+		ps := l.points
+		if len(ps) == 0 {
+			l.arcs = []int{}
+			continue
+		}
+
+		if l.ring {
+			ps = rotate(ps, junctions)
+		}
+
+		start := 0
+		for i := 1; i < len(ps); i++ {
+			if junctions[ps[i]] || i == len(ps)-1 {
+				l.arcs = append(l.arcs, b.arc(ps[start:i+1]))
+				start = i
+			}
+		}
+
+		if len(ps) == 1 {
+			l.arcs = append(l.arcs, b.arc(ps))
+		}
+	}
+}
+
+// rotate returns the ring starting at its first junction. If there are no
+// junctions it starts at the smallest point so rings with the same points
+// end up as the same arc.
+func rotate(ring orb.LineString, junctions map[orb.Point]bool) orb.LineString {
+	m := len(ring) - 1
+	if m < 1 {
+		return ring
+	}
+
+	start := -1
+	for i := 0; i < m; i++ {
+		if junctions[ring[i]] {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		start = 0
+		for i := 1; i < m; i++ {
+			if less(ring[i], ring[start]) {
+				start = i
+			}
+		}
+	}
+
+	if start == 0 {
+		return ring
+	}
+
+	result := make(orb.LineString, 0, len(ring))
+	result = append(result, ring[start:m]...)
+	result = append(result, ring[:start+1]...)
+
+	return result
+}
+
+func less(a, b orb.Point) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+
+	return a[1] < b[1]
+}
+
+// arc returns the index of the arc, adding it if it is new.
+// If the reverse already exists its negative index is returned.
+func (b *builder) arc(ls orb.LineString) int {
+	last := len(ls) - 1
+	for _, i := range b.byStart[ls[0]] {
+		if equal(b.arcs[i], ls, false) {
+			return i
+		}
+	}
+
+	for _, i := range b.byStart[ls[last]] {
+		if equal(b.arcs[i], ls, true) {
+			return ^i
+		}
+	}
+
+	arc := make(orb.LineString, len(ls))
+	copy(arc, ls)
+
+	b.arcs = append(b.arcs, arc)
+	b.byStart[ls[0]] = append(b.byStart[ls[0]], len(b.arcs)-1)
+
+	return len(b.arcs) - 1
+}
+
+func equal(a, b orb.LineString, reverse bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		j := i
+		if reverse {
+			j = len(b) - 1 - i
+		}
+
+		if a[i] != b[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// replaceLines replaces the line placeholders with the arc indexes.
+func (b *builder) replaceLines(g *Geometry) {
+	switch g.Type {
+	case "LineString":
+		g.LineString = b.lines[g.LineString[0]].arcs
+	case "MultiLineString":
+		for i, ls := range g.MultiLineString {
+			g.MultiLineString[i] = b.lines[ls[0]].arcs
+		}
+	case "Polygon":
+		for i, r := range g.Polygon {
+			g.Polygon[i] = b.lines[r[0]].arcs
+		}
+	case "MultiPolygon":
+		for _, p := range g.MultiPolygon {
+			for i, r := range p {
+				p[i] = b.lines[r[0]].arcs
+			}
+		}
+	case "GeometryCollection":
+		for _, c := range g.Geometries {
+			b.replaceLines(c)
+		}
+	}
+}
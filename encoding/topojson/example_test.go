@@ -0,0 +1,66 @@
+package topojson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/topojson"
+	"github.com/paulmach/orb/geojson"
+)
+
+func ExampleNew() {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}))
+	fc.Append(geojson.NewFeature(orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}))
+
+	topo := topojson.New(
+		map[string]*geojson.FeatureCollection{"states": fc},
+		topojson.Quantize(1e4),
+	)
+
+	data, err := json.Marshal(topo)
+	if err != nil {
+		log.Fatalf("marshal error: %v", err)
+	}
+
+	fmt.Println(string(data))
+
+	// Output:
+	// {"type":"Topology","bbox":[0,0,2,1],"transform":{"scale":[0.00020002000200020003,0.00010001000100010001],"translate":[0,0]},"objects":{"states":{"type":"GeometryCollection","geometries":[{"type":"Polygon","arcs":[[0,1]]},{"type":"Polygon","arcs":[[2,-1]]}]}},"arcs":[[[5000,0],[0,9999]],[[5000,9999],[-5000,0],[0,-9999],[5000,0]],[[5000,0],[4999,0],[0,9999],[-4999,0]]]}
+}
+
+func ExampleTopology_ToFeatureCollections() {
+	data := []byte(`{
+		"type": "Topology",
+		"objects": {
+			"example": {
+				"type": "GeometryCollection",
+				"geometries": [
+					{"type": "LineString", "id": "a", "arcs": [0, 1]},
+					{"type": "LineString", "id": "b", "arcs": [-2]}
+				]
+			}
+		},
+		"arcs": [[[0, 0], [1, 0]], [[1, 0], [2, 0], [2, 1]]]
+	}`)
+
+	topo, err := topojson.Unmarshal(data)
+	if err != nil {
+		log.Fatalf("unmarshal error: %v", err)
+	}
+
+	collections, err := topo.ToFeatureCollections()
+	if err != nil {
+		log.Fatalf("conversion error: %v", err)
+	}
+
+	for _, f := range collections["example"].Features {
+		fmt.Println(f.ID, f.Geometry)
+	}
+
+	// Output:
+	// a [[0 0] [1 0] [2 0] [2 1]]
+	// b [[2 1] [2 0] [1 0]]
+}
@@ -0,0 +1,21 @@
+package topojson
+
+type options struct {
+	quantization int
+}
+
+// An Option is a possible parameter when creating a topology.
+type Option func(*options)
+
+// Quantize snaps the coordinates to a grid of n by n values over the bound
+// of the data before computing the topology. The arcs are then delta-encoded
+// and the topology has a transform. Values of 1e4 to 1e6 are common.
+// Default is no quantization, values less than 2 are ignored.
+func Quantize(n int) Option {
+	return func(o *options) {
+		if n < 2 {
+			n = 0
+		}
+		o.quantization = n
+	}
+}
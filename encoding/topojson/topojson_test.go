@@ -0,0 +1,340 @@
+package topojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+func squares() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+
+	a := geojson.NewFeature(orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}})
+	a.ID = "a"
+	a.Properties["name"] = "left"
+	fc.Append(a)
+
+	b := geojson.NewFeature(orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}})
+	b.ID = 2.0
+	b.Properties["name"] = "right"
+	fc.Append(b)
+
+	return fc
+}
+
+func TestNew_sharedArcs(t *testing.T) {
+	topo := New(map[string]*geojson.FeatureCollection{"squares": squares()})
+
+	if len(topo.Arcs) != 3 {
+		t.Fatalf("shared edge should only be stored once: %v", topo.Arcs)
+	}
+
+	if !reflect.DeepEqual(topo.BBox, []float64{0, 0, 2, 1}) {
+		t.Errorf("incorrect bbox: %v", topo.BBox)
+	}
+
+	obj := topo.Objects["squares"]
+	if obj.Type != "GeometryCollection" || len(obj.Geometries) != 2 {
+		t.Fatalf("incorrect object: %v", obj)
+	}
+
+	a := obj.Geometries[0].Polygon[0]
+	b := obj.Geometries[1].Polygon[0]
+
+	// the shared arc is used forwards by one and reversed by the other
+	shared := 0
+	for _, i := range a {
+		for _, j := range b {
+			if i == ^j {
+				shared++
+			}
+		}
+	}
+
+	if shared != 1 {
+		t.Errorf("should share one arc in reverse: %v %v", a, b)
+	}
+}
+
+func TestNew_deterministic(t *testing.T) {
+	collections := map[string]*geojson.FeatureCollection{}
+	for i := 0; i < 10; i++ {
+		fc := geojson.NewFeatureCollection()
+		fc.Append(geojson.NewFeature(orb.LineString{{0, float64(i)}, {1, float64(i)}}))
+		collections[fmt.Sprintf("lines%d", i)] = fc
+	}
+
+	expected, err := json.Marshal(New(collections))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// map iteration order is random, the arcs should not depend on it.
+	for i := 0; i < 20; i++ {
+		data, err := json.Marshal(New(collections))
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+
+		if string(data) != string(expected) {
+			t.Fatalf("different topology: %v != %v", string(data), string(expected))
+		}
+	}
+
+	topo := New(collections)
+	if !reflect.DeepEqual(topo.Objects["lines0"].Geometries[0].LineString, []int{0}) {
+		t.Errorf("first collection should have the first arc: %v", topo.Objects["lines0"].Geometries[0].LineString)
+	}
+}
+
+func TestNew_lines(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}}))
+	fc.Append(geojson.NewFeature(orb.LineString{{1, 1}, {1, 0}, {2, 0}, {2, 1}}))
+	fc.Append(geojson.NewFeature(orb.LineString{{3, 0}, {2, 0}, {1, 0}, {0, 0}}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"lines": fc})
+
+	expected := []orb.LineString{
+		{{0, 0}, {1, 0}},
+		{{1, 0}, {2, 0}},
+		{{2, 0}, {3, 0}},
+		{{1, 1}, {1, 0}},
+		{{2, 0}, {2, 1}},
+	}
+	if !reflect.DeepEqual(topo.Arcs, expected) {
+		t.Errorf("incorrect arcs: %v", topo.Arcs)
+	}
+
+	geoms := topo.Objects["lines"].Geometries
+	if v := geoms[1].LineString; !reflect.DeepEqual(v, []int{3, 1, 4}) {
+		t.Errorf("incorrect arcs for second line: %v", v)
+	}
+
+	if v := geoms[2].LineString; !reflect.DeepEqual(v, []int{^2, ^1, ^0}) {
+		t.Errorf("reversed line should reuse arcs: %v", v)
+	}
+}
+
+func TestNew_sameRings(t *testing.T) {
+	// same ring, different start point and direction
+	fc := geojson.NewFeatureCollection()
+	fc.Append(geojson.NewFeature(orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}))
+	fc.Append(geojson.NewFeature(orb.Polygon{{{1, 1}, {1, 0}, {0, 0}, {1, 1}}}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"rings": fc})
+	if len(topo.Arcs) != 1 {
+		t.Errorf("should only have one arc: %v", topo.Arcs)
+	}
+
+	geoms := topo.Objects["rings"].Geometries
+	if geoms[0].Polygon[0][0] != 0 || geoms[1].Polygon[0][0] != ^0 {
+		t.Errorf("incorrect arc indexes: %v %v", geoms[0].Polygon, geoms[1].Polygon)
+	}
+}
+
+func TestTopology_roundTrip(t *testing.T) {
+	// {0, 0} and {5, 5} are line end points, and so junctions, so the
+	// rings keep their start point and can be compared exactly.
+	fc := squares()
+	fc.Append(geojson.NewFeature(orb.Point{5, 6}))
+	fc.Append(geojson.NewFeature(orb.MultiPoint{{5, 6}, {7, 8}}))
+	fc.Append(geojson.NewFeature(orb.LineString{{0, 0}, {1, 0}, {5, 5}}))
+	fc.Append(geojson.NewFeature(orb.MultiLineString{{{0, 0}, {1, 0}}, {{5, 5}, {6, 6}}}))
+	fc.Append(geojson.NewFeature(orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{5, 5}, {6, 5}, {6, 6}, {5, 5}}, {{5.5, 5.2}, {5.8, 5.2}, {5.8, 5.5}, {5.5, 5.2}}},
+	}))
+	fc.Append(geojson.NewFeature(orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}}))
+	fc.Append(geojson.NewFeature(nil))
+
+	topo := New(map[string]*geojson.FeatureCollection{"all": fc})
+
+	data, err := json.Marshal(topo)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	topo, err = Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	collections, err := topo.ToFeatureCollections()
+	if err != nil {
+		t.Fatalf("to feature collections error: %v", err)
+	}
+
+	result := collections["all"]
+	if len(result.Features) != len(fc.Features) {
+		t.Fatalf("incorrect number of features: %d", len(result.Features))
+	}
+
+	for i, f := range fc.Features {
+		r := result.Features[i]
+		if f.Geometry == nil {
+			if r.Geometry != nil {
+				t.Errorf("%d: should have nil geometry: %v", i, r.Geometry)
+			}
+		} else if !orb.Equal(r.Geometry, f.Geometry) {
+			t.Errorf("%d: incorrect geometry", i)
+			t.Logf("%v", r.Geometry)
+			t.Logf("%v", f.Geometry)
+		}
+
+		if !reflect.DeepEqual(r.ID, f.ID) {
+			t.Errorf("%d: incorrect id: %v != %v", i, r.ID, f.ID)
+		}
+
+		if !reflect.DeepEqual(r.Properties, f.Properties) {
+			t.Errorf("%d: incorrect properties: %v != %v", i, r.Properties, f.Properties)
+		}
+	}
+}
+
+func TestTopology_quantize(t *testing.T) {
+	fc := squares()
+	fc.Append(geojson.NewFeature(orb.Point{0.5, 0.5}))
+
+	topo := New(map[string]*geojson.FeatureCollection{"squares": fc}, Quantize(1e4))
+
+	if topo.Transform == nil {
+		t.Fatalf("should have a transform")
+	}
+
+	expected := &Transform{
+		Scale:     [2]float64{2.0 / 9999, 1.0 / 9999},
+		Translate: [2]float64{0, 0},
+	}
+	if !reflect.DeepEqual(topo.Transform, expected) {
+		t.Errorf("incorrect transform: %v", topo.Transform)
+	}
+
+	// arcs are delta encoded integers
+	for _, arc := range topo.Arcs {
+		for _, p := range arc {
+			if p[0] != math.Trunc(p[0]) || p[1] != math.Trunc(p[1]) {
+				t.Errorf("should be quantized: %v", arc)
+			}
+		}
+
+		for _, p := range arc[1:] {
+			if math.Abs(p[0]) > 9999 || math.Abs(p[1]) > 9999 {
+				t.Errorf("should be delta encoded: %v", arc)
+			}
+		}
+	}
+
+	result, err := topo.ToFeatureCollection("squares")
+	if err != nil {
+		t.Fatalf("to feature collection error: %v", err)
+	}
+
+	// rings are rotated to start at a junction so only compare the bounds
+	for i, f := range fc.Features {
+		a, b := f.Geometry, result.Features[i].Geometry
+		if orb.PointCount(a) != orb.PointCount(b) || !approxEqual(a.Bound(), b.Bound(), 1e-3) {
+			t.Errorf("%d: incorrect geometry: %v", i, b)
+		}
+	}
+}
+
+func approxEqual(a, b orb.Bound, e float64) bool {
+	for i := 0; i < 2; i++ {
+		if math.Abs(a.Min[i]-b.Min[i]) > e || math.Abs(a.Max[i]-b.Max[i]) > e {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestTopology_MarshalJSON(t *testing.T) {
+	fc := geojson.NewFeatureCollection()
+	f := geojson.NewFeature(orb.LineString{{0, 0}, {1, 1}})
+	f.ID = 1
+	fc.Append(f)
+	fc.Append(geojson.NewFeature(orb.Point{2, 3}))
+	fc.Append(geojson.NewFeature(nil))
+
+	data, err := json.Marshal(New(map[string]*geojson.FeatureCollection{"example": fc}))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Topology","bbox":[0,0,2,3],"objects":{"example":{"type":"GeometryCollection","geometries":[` +
+		`{"id":1,"type":"LineString","arcs":[0]},{"type":"Point","coordinates":[2,3]},{"type":null}]}},` +
+		`"arcs":[[[0,0],[1,1]]]}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %s", data)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{
+			name: "not a topology",
+			data: `{"type":"FeatureCollection","features":[]}`,
+			err:  ErrInvalidTopology,
+		},
+		{
+			name: "unknown geometry type",
+			data: `{"type":"Topology","objects":{"a":{"type":"Foo"}},"arcs":[]}`,
+			err:  ErrInvalidGeometry,
+		},
+		{
+			name: "missing arcs",
+			data: `{"type":"Topology","objects":{"a":{"type":"LineString"}},"arcs":[]}`,
+			err:  ErrInvalidGeometry,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tc.data))
+			if err != tc.err {
+				t.Errorf("incorrect error: %v != %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestToFeatureCollection_invalidArc(t *testing.T) {
+	topo, err := Unmarshal([]byte(`{"type":"Topology","objects":{"a":{"type":"LineString","arcs":[1]}},"arcs":[[[0,0],[1,1]]]}`))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	_, err = topo.ToFeatureCollection("a")
+	if err != ErrInvalidTopology {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	fc, err := topo.ToFeatureCollection("missing")
+	if fc != nil || err != nil {
+		t.Errorf("missing object should return nil: %v %v", fc, err)
+	}
+}
+
+func TestNew_allGeometries(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		t.Run(fmt.Sprintf("%T", g), func(t *testing.T) {
+			fc := geojson.NewFeatureCollection()
+			fc.Append(geojson.NewFeature(g))
+
+			topo := New(map[string]*geojson.FeatureCollection{"all": fc}, Quantize(100))
+			if _, err := topo.ToFeatureCollections(); err != nil {
+				t.Errorf("to feature collections error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package topojson
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+)
+
+var (
+	// ErrInvalidTopology is returned when the data is not a valid topology,
+	// for example if it references arcs that do not exist.
+	ErrInvalidTopology = errors.New("topojson: invalid topology")
+
+	// ErrInvalidGeometry is returned when the json of a geometry object is invalid.
+	ErrInvalidGeometry = errors.New("topojson: invalid geometry")
+)
+
+// A Topology matches the structure of a TopoJSON topology. The objects are
+// keyed by name and reference the shared arcs by index. Negative indexes,
+// ^i or -i-1, reference arc i in reverse.
+type Topology struct {
+	Type      string               `json:"type"`
+	BBox      []float64            `json:"bbox,omitempty"`
+	Transform *Transform           `json:"transform,omitempty"`
+	Objects   map[string]*Geometry `json:"objects"`
+
+	// Arcs are the positions of the arcs. If there is a transform the
+	// positions are quantized and delta-encoded.
+	Arcs []orb.LineString `json:"arcs"`
+}
+
+// A Transform converts quantized positions to the original coordinates:
+// x*scale[0] + translate[0], y*scale[1] + translate[1].
+type Transform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+// A Geometry is a TopoJSON geometry object. Points are defined by their
+// coordinates, the other types reference the arcs of the topology.
+// Only the field matching the type is set.
+type Geometry struct {
+	ID         interface{}        `json:"id,omitempty"`
+	Type       string             `json:"type"`
+	Properties geojson.Properties `json:"properties,omitempty"`
+
+	Point           orb.Point      `json:"-"`
+	MultiPoint      orb.MultiPoint `json:"-"`
+	LineString      []int          `json:"-"`
+	MultiLineString [][]int        `json:"-"`
+	Polygon         [][]int        `json:"-"`
+	MultiPolygon    [][][]int      `json:"-"`
+	Geometries      []*Geometry    `json:"-"`
+}
+
+// Unmarshal decodes the data into a topology.
+func Unmarshal(data []byte) (*Topology, error) {
+	t := &Topology{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+
+	if t.Type != "Topology" {
+		return nil, ErrInvalidTopology
+	}
+
+	return t, nil
+}
+
+type jsonGeometry struct {
+	ID          interface{}        `json:"id,omitempty"`
+	Type        *string            `json:"type"`
+	Properties  geojson.Properties `json:"properties,omitempty"`
+	Coordinates interface{}        `json:"coordinates,omitempty"`
+	Arcs        interface{}        `json:"arcs,omitempty"`
+	Geometries  interface{}        `json:"geometries,omitempty"`
+}
+
+// MarshalJSON converts the geometry object into the correct json structure.
+// An empty type is encoded as null, a geometry object with no geometry.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	jg := &jsonGeometry{
+		ID:         g.ID,
+		Properties: g.Properties,
+	}
+
+	if g.Type != "" {
+		jg.Type = &g.Type
+	}
+
+	switch g.Type {
+	case "Point":
+		jg.Coordinates = g.Point
+	case "MultiPoint":
+		jg.Coordinates = nonNil(g.MultiPoint)
+	case "LineString":
+		jg.Arcs = nonNil(g.LineString)
+	case "MultiLineString":
+		jg.Arcs = nonNil(g.MultiLineString)
+	case "Polygon":
+		jg.Arcs = nonNil(g.Polygon)
+	case "MultiPolygon":
+		jg.Arcs = nonNil(g.MultiPolygon)
+	case "GeometryCollection":
+		jg.Geometries = nonNil(g.Geometries)
+	}
+
+	return json.Marshal(jg)
+}
+
+// nonNil makes sure empty values are encoded as [] and not null.
+func nonNil(v interface{}) interface{} {
+	switch v := v.(type) {
+	case orb.MultiPoint:
+		if v == nil {
+			return orb.MultiPoint{}
+		}
+	case []int:
+		if v == nil {
+			return []int{}
+		}
+	case [][]int:
+		if v == nil {
+			return [][]int{}
+		}
+	case [][][]int:
+		if v == nil {
+			return [][][]int{}
+		}
+	case []*Geometry:
+		if v == nil {
+			return []*Geometry{}
+		}
+	}
+
+	return v
+}
+
+// UnmarshalJSON will unmarshal the correct geometry from the json structure.
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	jg := &struct {
+		ID          interface{}        `json:"id"`
+		Type        *string            `json:"type"`
+		Properties  geojson.Properties `json:"properties"`
+		Coordinates json.RawMessage    `json:"coordinates"`
+		Arcs        json.RawMessage    `json:"arcs"`
+		Geometries  []*Geometry        `json:"geometries"`
+	}{}
+
+	if err := json.Unmarshal(data, jg); err != nil {
+		return err
+	}
+
+	*g = Geometry{
+		ID:         jg.ID,
+		Properties: jg.Properties,
+	}
+
+	if jg.Type == nil {
+		return nil
+	}
+	g.Type = *jg.Type
+
+	var err error
+	switch g.Type {
+	case "Point":
+		err = unmarshalRequired(jg.Coordinates, &g.Point)
+	case "MultiPoint":
+		err = unmarshalRequired(jg.Coordinates, &g.MultiPoint)
+	case "LineString":
+		err = unmarshalRequired(jg.Arcs, &g.LineString)
+	case "MultiLineString":
+		err = unmarshalRequired(jg.Arcs, &g.MultiLineString)
+	case "Polygon":
+		err = unmarshalRequired(jg.Arcs, &g.Polygon)
+	case "MultiPolygon":
+		err = unmarshalRequired(jg.Arcs, &g.MultiPolygon)
+	case "GeometryCollection":
+		g.Geometries = jg.Geometries
+	default:
+		return ErrInvalidGeometry
+	}
+
+	return err
+}
+
+func unmarshalRequired(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return ErrInvalidGeometry
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,22 @@
+package twkb_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/twkb"
+)
+
+func ExampleMarshal() {
+	ls := orb.LineString{{-122.4194155, 37.7749295}, {-122.4193155, 37.7750295}}
+
+	data, _ := twkb.Marshal(ls, 5)
+	fmt.Printf("%x\n", data)
+
+	g, _ := twkb.Unmarshal(data)
+	fmt.Println(g)
+
+	// Output:
+	// a20002abb0d60baa8fcd031414
+	// [[-122.41942 37.77493] [-122.41932 37.77503]]
+}
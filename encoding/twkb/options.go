@@ -0,0 +1,52 @@
+package twkb
+
+type options struct {
+	bbox  bool
+	size  bool
+	ids   []int64
+	zPrec int
+	mPrec int
+}
+
+// An Option is a possible parameter to the marshal functions.
+type Option func(*options)
+
+// BBox is an option to include the bounding box in the header.
+// It will also be included for each geometry of a collection.
+func BBox(yes bool) Option {
+	return func(o *options) {
+		o.bbox = yes
+	}
+}
+
+// Size is an option to include the size of the geometry, in bytes,
+// in the header. This allows readers to skip over geometries.
+func Size(yes bool) Option {
+	return func(o *options) {
+		o.size = yes
+	}
+}
+
+// IDList is an option to include an id for each part of a multi geometry
+// or collection. There must be one id for each part.
+func IDList(ids []int64) Option {
+	return func(o *options) {
+		o.ids = ids
+	}
+}
+
+// ZPrecision sets the number of decimal places kept for the Z values
+// when marshalling a GeometryZM. Must be between 0 and 7, the default is 0.
+func ZPrecision(p int) Option {
+	return func(o *options) {
+		o.zPrec = p
+	}
+}
+
+// MPrecision sets the number of decimal places kept for the M values
+// when marshalling a GeometryZM. Must be between 0 and 7, the default is 0.
+func MPrecision(p int) Option {
+	return func(o *options) {
+		o.mPrec = p
+	}
+}
@@ -0,0 +1,704 @@
+// Package twkb is for encoding and decoding Tiny Well Known Binary (TWKB)
+// as specified at https://github.com/TWKB/Specification. It is interoperable
+// with PostGIS's ST_AsTWKB and ST_GeomFromTWKB.
+package twkb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	// ErrNotTWKB is returned when unmarshalling TWKB and the data is not valid.
+	ErrNotTWKB = errors.New("twkb: invalid data")
+
+	// ErrUnsupportedGeometry is returned when geometry type is not supported by this lib.
+	ErrUnsupportedGeometry = errors.New("twkb: unsupported geometry")
+
+	// ErrInvalidPrecision is returned when marshalling with an xy precision
+	// outside of -8 to 7 or a z/m precision outside of 0 to 7.
+	ErrInvalidPrecision = errors.New("twkb: invalid precision")
+
+	// ErrInvalidIDs is returned when marshalling with an id list and the geometry
+	// is not a multi geometry or collection, or the number of ids does not match.
+	ErrInvalidIDs = errors.New("twkb: id list does not match the geometry")
+
+	// ErrInvalidZM is returned when marshalling a GeometryZM and the number of
+	// extra values does not match the points and layout.
+	ErrInvalidZM = errors.New("twkb: extra values do not match the geometry and layout")
+)
+
+const (
+	pointType              = 1
+	lineStringType         = 2
+	polygonType            = 3
+	multiPointType         = 4
+	multiLineStringType    = 5
+	multiPolygonType       = 6
+	geometryCollectionType = 7
+)
+
+// flags in the metadata header byte.
+const (
+	bboxFlag     = 0x01
+	sizeFlag     = 0x02
+	idListFlag   = 0x04
+	extendedFlag = 0x08
+	emptyFlag    = 0x10
+)
+
+const (
+	// limits so that bad data can't come in and preallocate tons of memory.
+	// Well formed data with less elements will allocate the correct amount just fine.
+	maxPointsAlloc = 10000
+	maxMultiAlloc  = 100
+)
+
+// Marshal encodes the geometry keeping the given number of decimal places.
+// The precision can be negative, e.g. -2 will round to the nearest 100.
+func Marshal(g orb.Geometry, precision int, opts ...Option) ([]byte, error) {
+	return MarshalZM(orb.GeometryZM{Geometry: g}, precision, opts...)
+}
+
+// MarshalZM encodes the geometry, including its Z and M values, keeping
+// the given number of decimal places for x and y. See the ZPrecision and
+// MPrecision options for the other values.
+func MarshalZM(g orb.GeometryZM, precision int, opts ...Option) ([]byte, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if precision < -8 || precision > 7 ||
+		o.zPrec < 0 || o.zPrec > 7 || o.mPrec < 0 || o.mPrec > 7 {
+		return nil, ErrInvalidPrecision
+	}
+
+	if g.Geometry == nil {
+		return nil, nil
+	}
+
+	if !g.Valid() {
+		return nil, ErrInvalidZM
+	}
+
+	e := &encoder{
+		opts:   o,
+		layout: g.Layout,
+		extra:  g.Extra,
+	}
+
+	e.precision[0], e.precision[1] = precision, precision
+	e.precision[2], e.precision[3] = o.zPrec, o.mPrec
+	if g.Layout == orb.XYM {
+		e.precision[2] = o.mPrec
+	}
+
+	data, _, err := e.geometry(nil, g.Geometry, o.ids)
+	return data, err
+}
+
+// Unmarshal decodes the data into a geometry. Any Z and M values are dropped.
+func Unmarshal(data []byte) (orb.Geometry, error) {
+	g, _, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Geometry, nil
+}
+
+// UnmarshalZM decodes the data into a geometry keeping any Z and M values.
+func UnmarshalZM(data []byte) (orb.GeometryZM, error) {
+	g, _, err := decode(data)
+	return g, err
+}
+
+// UnmarshalIDs decodes the data into a geometry and returns the id list
+// of the multi geometry or collection, nil if not present.
+func UnmarshalIDs(data []byte) (orb.Geometry, []int64, error) {
+	g, ids, err := decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return g.Geometry, ids, nil
+}
+
+func decode(data []byte) (orb.GeometryZM, []int64, error) {
+	d := &decoder{data: data}
+	g, layout, ids, err := d.geometry(false, orb.XY)
+	if err != nil {
+		return orb.GeometryZM{}, nil, err
+	}
+
+	if layout == orb.XY {
+		d.extra = nil
+	}
+
+	return orb.GeometryZM{Geometry: g, Layout: layout, Extra: d.extra}, ids, nil
+}
+
+type encoder struct {
+	opts      *options
+	precision [4]int
+
+	layout orb.Layout
+	extra  []float64
+	index  int
+}
+
+// body is the encoded coordinates of a single twkb geometry. Each
+// value is the delta from the previous one.
+type body struct {
+	buf  []byte
+	dims int
+
+	last     [4]int64
+	min, max [4]int64
+	points   int
+}
+
+// geometry appends the encoded geometry to the buffer. The coordinate
+// body is returned so collections can include the bounds of their children.
+func (e *encoder) geometry(buf []byte, g orb.Geometry, ids []int64) ([]byte, *body, error) {
+	switch t := g.(type) {
+	case orb.Ring:
+		g = orb.Polygon{t}
+	case orb.Bound:
+		g = t.ToPolygon()
+		if e.layout != orb.XY {
+			// the bound's extra values are for the min and max points,
+			// repeat them for each corner of the polygon.
+			n := e.layout.Stride() - 2
+			min := e.extra[e.index : e.index+n]
+			max := e.extra[e.index+n : e.index+2*n]
+
+			extra := make([]float64, 0, 5*n)
+			for _, v := range [][]float64{min, min, max, max, min} {
+				extra = append(extra, v...)
+			}
+
+			sub := &encoder{opts: e.opts, precision: e.precision, layout: e.layout, extra: extra}
+			e.index += 2 * n
+			return sub.geometry(buf, g, ids)
+		}
+	}
+
+	b := &body{dims: e.layout.Stride()}
+
+	var typ byte
+	count := -1
+	switch g := g.(type) {
+	case orb.Point:
+		typ = pointType
+		e.point(b, g)
+	case orb.LineString:
+		typ = lineStringType
+		if len(g) > 0 {
+			e.points(b, g)
+		}
+	case orb.Polygon:
+		typ = polygonType
+		if len(g) > 0 {
+			e.polygon(b, g)
+		}
+	case orb.MultiPoint:
+		typ = multiPointType
+		count = len(g)
+		if len(g) > 0 {
+			b.buf = e.idList(b.buf, len(g), ids)
+			for _, p := range g {
+				e.point(b, p)
+			}
+		}
+	case orb.MultiLineString:
+		typ = multiLineStringType
+		count = len(g)
+		if len(g) > 0 {
+			b.buf = e.idList(b.buf, len(g), ids)
+			for _, ls := range g {
+				e.points(b, ls)
+			}
+		}
+	case orb.MultiPolygon:
+		typ = multiPolygonType
+		count = len(g)
+		if len(g) > 0 {
+			b.buf = e.idList(b.buf, len(g), ids)
+			for _, p := range g {
+				e.polygon(b, p)
+			}
+		}
+	case orb.Collection:
+		typ = geometryCollectionType
+		count = len(g)
+		if len(g) > 0 {
+			b.buf = e.idList(b.buf, len(g), ids)
+			for _, c := range g {
+				var (
+					cb  *body
+					err error
+				)
+				b.buf, cb, err = e.geometry(b.buf, c, nil)
+				if err != nil {
+					return nil, nil, err
+				}
+				b.extend(cb)
+			}
+		}
+	default:
+		return nil, nil, ErrUnsupportedGeometry
+	}
+
+	if len(ids) > 0 && len(ids) != count {
+		return nil, nil, ErrInvalidIDs
+	}
+
+	empty := len(b.buf) == 0
+
+	// header
+	buf = append(buf, typ|byte(zigzag(int64(e.precision[0])))<<4)
+
+	var meta byte
+	if e.opts.bbox && !empty && b.points > 0 {
+		meta |= bboxFlag
+	}
+	if e.opts.size {
+		meta |= sizeFlag
+	}
+	if len(ids) > 0 && !empty {
+		meta |= idListFlag
+	}
+	if e.layout != orb.XY {
+		meta |= extendedFlag
+	}
+	if empty {
+		meta |= emptyFlag
+	}
+	buf = append(buf, meta)
+
+	if e.layout != orb.XY {
+		var ext byte
+		if e.layout.HasZ() {
+			ext |= 0x01 | byte(e.precision[2])<<2
+		}
+		if e.layout.HasM() {
+			ext |= 0x02 | byte(e.precision[b.dims-1])<<5
+		}
+		buf = append(buf, ext)
+	}
+
+	var bbox []byte
+	if meta&bboxFlag != 0 {
+		for i := 0; i < b.dims; i++ {
+			bbox = appendVarint(bbox, b.min[i])
+			bbox = appendVarint(bbox, b.max[i]-b.min[i])
+		}
+	}
+
+	if e.opts.size {
+		buf = appendUvarint(buf, uint64(len(bbox)+len(b.buf)))
+	}
+
+	buf = append(buf, bbox...)
+	return append(buf, b.buf...), b, nil
+}
+
+// extend updates the bounds to include the other body.
+func (b *body) extend(o *body) {
+	if o.points == 0 {
+		return
+	}
+
+	for i := 0; i < b.dims; i++ {
+		if b.points == 0 || o.min[i] < b.min[i] {
+			b.min[i] = o.min[i]
+		}
+		if b.points == 0 || o.max[i] > b.max[i] {
+			b.max[i] = o.max[i]
+		}
+	}
+	b.points += o.points
+}
+
+func (e *encoder) idList(buf []byte, n int, ids []int64) []byte {
+	buf = appendUvarint(buf, uint64(n))
+	if len(ids) != n {
+		return buf
+	}
+
+	for _, id := range ids {
+		buf = appendVarint(buf, id)
+	}
+
+	return buf
+}
+
+func (e *encoder) polygon(b *body, p orb.Polygon) {
+	b.buf = appendUvarint(b.buf, uint64(len(p)))
+	for _, r := range p {
+		e.points(b, r)
+	}
+}
+
+func (e *encoder) points(b *body, ps []orb.Point) {
+	b.buf = appendUvarint(b.buf, uint64(len(ps)))
+	for _, p := range ps {
+		e.point(b, p)
+	}
+}
+
+func (e *encoder) point(b *body, p orb.Point) {
+	var values [4]float64
+	values[0], values[1] = p[0], p[1]
+	for i := 2; i < b.dims; i++ {
+		values[i] = e.extra[e.index]
+		e.index++
+	}
+
+	for i := 0; i < b.dims; i++ {
+		v := quantize(values[i], e.precision[i])
+		b.buf = appendVarint(b.buf, v-b.last[i])
+		b.last[i] = v
+
+		if b.points == 0 || v < b.min[i] {
+			b.min[i] = v
+		}
+		if b.points == 0 || v > b.max[i] {
+			b.max[i] = v
+		}
+	}
+	b.points++
+}
+
+func quantize(v float64, precision int) int64 {
+	if precision >= 0 {
+		return int64(math.Round(v * math.Pow10(precision)))
+	}
+
+	return int64(math.Round(v / math.Pow10(-precision)))
+}
+
+func unquantize(v int64, precision int) float64 {
+	if precision >= 0 {
+		return float64(v) / math.Pow10(precision)
+	}
+
+	return float64(v) * math.Pow10(-precision)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	return appendUvarint(buf, zigzag(v))
+}
+
+type decoder struct {
+	data  []byte
+	pos   int
+	extra []float64
+}
+
+// geometry reads the next twkb geometry. If it is part of a collection
+// it must have the same layout as the parent.
+func (d *decoder) geometry(child bool, parent orb.Layout) (orb.Geometry, orb.Layout, []int64, error) {
+	if len(d.data)-d.pos < 2 {
+		return nil, 0, nil, ErrNotTWKB
+	}
+
+	typ := d.data[d.pos] & 0x0f
+	var precision [4]int
+	precision[0] = int(unzigzag(uint64(d.data[d.pos] >> 4)))
+	precision[1] = precision[0]
+	meta := d.data[d.pos+1]
+	d.pos += 2
+
+	layout := orb.XY
+	if meta&extendedFlag != 0 {
+		if d.pos >= len(d.data) {
+			return nil, 0, nil, ErrNotTWKB
+		}
+
+		ext := d.data[d.pos]
+		d.pos++
+
+		hasZ, hasM := ext&0x01 != 0, ext&0x02 != 0
+		switch {
+		case hasZ && hasM:
+			layout = orb.XYZM
+			precision[2], precision[3] = int(ext>>2)&0x07, int(ext>>5)&0x07
+		case hasZ:
+			layout = orb.XYZ
+			precision[2] = int(ext>>2) & 0x07
+		case hasM:
+			layout = orb.XYM
+			precision[2] = int(ext>>5) & 0x07
+		}
+	}
+
+	if child && layout != parent {
+		return nil, 0, nil, ErrNotTWKB
+	}
+
+	if meta&sizeFlag != 0 {
+		size, err := d.uvarint()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		if size > uint64(len(d.data)-d.pos) {
+			return nil, 0, nil, ErrNotTWKB
+		}
+	}
+
+	dims := layout.Stride()
+	if meta&bboxFlag != 0 {
+		for i := 0; i < 2*dims; i++ {
+			if _, err := d.varint(); err != nil {
+				return nil, 0, nil, err
+			}
+		}
+	}
+
+	if meta&emptyFlag != 0 {
+		g, err := emptyGeometry(typ)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		if typ == pointType && layout != orb.XY {
+			for i := 2; i < dims; i++ {
+				d.extra = append(d.extra, math.NaN())
+			}
+		}
+
+		return g, layout, nil, nil
+	}
+
+	c := &coords{d: d, dims: dims, precision: precision}
+
+	var ids []int64
+	readCount := func() (int, error) {
+		n, err := d.uvarint()
+		if err != nil {
+			return 0, err
+		}
+
+		if meta&idListFlag != 0 {
+			ids = make([]int64, 0, alloc(n, maxMultiAlloc))
+			for i := uint64(0); i < n; i++ {
+				id, err := d.varint()
+				if err != nil {
+					return 0, err
+				}
+				ids = append(ids, id)
+			}
+		}
+
+		return int(n), nil
+	}
+
+	var (
+		g   orb.Geometry
+		err error
+	)
+	switch typ {
+	case pointType:
+		g, err = c.point()
+	case lineStringType:
+		var ls []orb.Point
+		ls, err = c.points()
+		g = orb.LineString(ls)
+	case polygonType:
+		g, err = c.polygon()
+	case multiPointType:
+		var n int
+		if n, err = readCount(); err != nil {
+			break
+		}
+
+		mp := make(orb.MultiPoint, 0, alloc(uint64(n), maxPointsAlloc))
+		for i := 0; i < n && err == nil; i++ {
+			var p orb.Point
+			if p, err = c.point(); err == nil {
+				mp = append(mp, p)
+			}
+		}
+		g = mp
+	case multiLineStringType:
+		var n int
+		if n, err = readCount(); err != nil {
+			break
+		}
+
+		mls := make(orb.MultiLineString, 0, alloc(uint64(n), maxMultiAlloc))
+		for i := 0; i < n && err == nil; i++ {
+			var ls []orb.Point
+			if ls, err = c.points(); err == nil {
+				mls = append(mls, ls)
+			}
+		}
+		g = mls
+	case multiPolygonType:
+		var n int
+		if n, err = readCount(); err != nil {
+			break
+		}
+
+		mp := make(orb.MultiPolygon, 0, alloc(uint64(n), maxMultiAlloc))
+		for i := 0; i < n && err == nil; i++ {
+			var p orb.Polygon
+			if p, err = c.polygon(); err == nil {
+				mp = append(mp, p)
+			}
+		}
+		g = mp
+	case geometryCollectionType:
+		var n int
+		if n, err = readCount(); err != nil {
+			break
+		}
+
+		col := make(orb.Collection, 0, alloc(uint64(n), maxMultiAlloc))
+		for i := 0; i < n && err == nil; i++ {
+			var cg orb.Geometry
+			if cg, _, _, err = d.geometry(true, layout); err == nil {
+				col = append(col, cg)
+			}
+		}
+		g = col
+	default:
+		return nil, 0, nil, ErrUnsupportedGeometry
+	}
+
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return g, layout, ids, nil
+}
+
+func emptyGeometry(typ byte) (orb.Geometry, error) {
+	switch typ {
+	case pointType:
+		return orb.Point{}, nil
+	case lineStringType:
+		return orb.LineString{}, nil
+	case polygonType:
+		return orb.Polygon{}, nil
+	case multiPointType:
+		return orb.MultiPoint{}, nil
+	case multiLineStringType:
+		return orb.MultiLineString{}, nil
+	case multiPolygonType:
+		return orb.MultiPolygon{}, nil
+	case geometryCollectionType:
+		return orb.Collection{}, nil
+	}
+
+	return nil, ErrUnsupportedGeometry
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, ErrNotTWKB
+	}
+	d.pos += n
+
+	return v, nil
+}
+
+func (d *decoder) varint() (int64, error) {
+	v, err := d.uvarint()
+	return unzigzag(v), err
+}
+
+// coords reads the delta encoded coordinates of a single twkb geometry.
+type coords struct {
+	d         *decoder
+	dims      int
+	precision [4]int
+	last      [4]int64
+}
+
+func (c *coords) polygon() (orb.Polygon, error) {
+	n, err := c.d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	p := make(orb.Polygon, 0, alloc(n, maxMultiAlloc))
+	for i := uint64(0); i < n; i++ {
+		r, err := c.points()
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, orb.Ring(r))
+	}
+
+	return p, nil
+}
+
+func (c *coords) points() ([]orb.Point, error) {
+	n, err := c.d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]orb.Point, 0, alloc(n, maxPointsAlloc))
+	for i := uint64(0); i < n; i++ {
+		p, err := c.point()
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+
+	return ps, nil
+}
+
+func (c *coords) point() (orb.Point, error) {
+	var p orb.Point
+	for i := 0; i < c.dims; i++ {
+		delta, err := c.d.varint()
+		if err != nil {
+			return orb.Point{}, err
+		}
+
+		c.last[i] += delta
+		v := unquantize(c.last[i], c.precision[i])
+		if i < 2 {
+			p[i] = v
+		} else {
+			c.d.extra = append(c.d.extra, v)
+		}
+	}
+
+	return p, nil
+}
+
+func alloc(n uint64, max int) int {
+	// invalid data can come in here and allocate tons of memory.
+	if n > uint64(max) {
+		return max
+	}
+
+	return int(n)
+}
@@ -0,0 +1,282 @@
+package twkb
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestMarshal(t *testing.T) {
+	cases := []struct {
+		name      string
+		geom      orb.Geometry
+		precision int
+		opts      []Option
+		expected  string
+	}{
+		{
+			name:     "point",
+			geom:     orb.Point{1, 2},
+			expected: "01000204",
+		},
+		{
+			name:      "point with precision",
+			geom:      orb.Point{1.5, 2.25},
+			precision: 2,
+			expected:  "4100ac02c203",
+		},
+		{
+			name:      "negative precision",
+			geom:      orb.Point{1260, -340},
+			precision: -1,
+			expected:  "1100fc0143",
+		},
+		{
+			// SELECT ST_AsTWKB('LINESTRING(1 1,5 5)'::geometry, 0)
+			name:     "line string",
+			geom:     orb.LineString{{1, 1}, {5, 5}},
+			expected: "02000202020808",
+		},
+		{
+			// SELECT ST_AsTWKB('LINESTRING(1 1,5 5)'::geometry, 0, 0, 0, false, true)
+			name:     "line string with bbox",
+			geom:     orb.LineString{{1, 1}, {5, 5}},
+			opts:     []Option{BBox(true)},
+			expected: "0201020802080202020808",
+		},
+		{
+			name:     "line string with size",
+			geom:     orb.LineString{{1, 1}, {5, 5}},
+			opts:     []Option{Size(true)},
+			expected: "0202050202020808",
+		},
+		{
+			// SELECT ST_AsTWKB(array_agg(geom), array_agg(gid)) FROM (VALUES
+			// (2, 'POINT(1 1)'::geometry), (4, 'POINT(5 5)'::geometry)) AS geoms(gid, geom)
+			name:     "multi point with ids",
+			geom:     orb.MultiPoint{{1, 1}, {5, 5}},
+			opts:     []Option{IDList([]int64{2, 4})},
+			expected: "040402040802020808",
+		},
+		{
+			name:     "polygon",
+			geom:     orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			expected: "030001040000020000020101",
+		},
+		{
+			name:     "empty line string",
+			geom:     orb.LineString{},
+			opts:     []Option{BBox(true)},
+			expected: "0210",
+		},
+		{
+			name:     "collection",
+			geom:     orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 1}, {5, 5}}},
+			opts:     []Option{BBox(true)},
+			expected: "0701020802080201010200040002040201020802080202020808",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Marshal(tc.geom, tc.precision, tc.opts...)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if v := hex.EncodeToString(data); v != tc.expected {
+				t.Errorf("incorrect data")
+				t.Logf("%v", v)
+				t.Logf("%v", tc.expected)
+			}
+
+			g, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !orb.Equal(g, tc.geom) {
+				t.Errorf("incorrect round trip: %v", g)
+			}
+		})
+	}
+}
+
+func TestMarshal_allGeometries(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		data, err := Marshal(g, 6, BBox(true), Size(true))
+		if err != nil {
+			t.Fatalf("marshal error for %T: %v", g, err)
+		}
+
+		if data == nil {
+			continue
+		}
+
+		if _, err := Unmarshal(data); err != nil {
+			t.Errorf("unmarshal error for %T: %v", g, err)
+		}
+	}
+}
+
+func TestMarshal_rounding(t *testing.T) {
+	ls := orb.LineString{{-122.4194155, 37.7749295}, {-122.4194155, 37.7849295}, {2.3522219, 48.856614}}
+
+	data, err := Marshal(ls, 5)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	g, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := orb.LineString{{-122.41942, 37.77493}, {-122.41942, 37.78493}, {2.35222, 48.85661}}
+	if !orb.Equal(g, expected) {
+		t.Errorf("incorrect line string: %v", g)
+	}
+}
+
+func TestMarshal_errors(t *testing.T) {
+	if _, err := Marshal(orb.Point{1, 2}, 8); err != ErrInvalidPrecision {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if _, err := Marshal(orb.Point{1, 2}, 0, ZPrecision(-1)); err != ErrInvalidPrecision {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if _, err := Marshal(orb.Point{1, 2}, 0, IDList([]int64{1})); err != ErrInvalidIDs {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if _, err := Marshal(orb.MultiPoint{{1, 2}}, 0, IDList([]int64{1, 2})); err != ErrInvalidIDs {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	_, err := MarshalZM(orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ}, 0)
+	if err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestMarshalZM(t *testing.T) {
+	cases := []struct {
+		name string
+		geom orb.GeometryZM
+		opts []Option
+	}{
+		{
+			name: "point z",
+			geom: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3.5}},
+			opts: []Option{ZPrecision(1)},
+		},
+		{
+			name: "line string m",
+			geom: orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYM, Extra: []float64{10, 20}},
+		},
+		{
+			name: "multi polygon zm",
+			geom: orb.GeometryZM{
+				Geometry: orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+				Layout:   orb.XYZM,
+				Extra:    []float64{1, 0.25, 2, 0.5, 3, 0.75, 1, 0.25},
+			},
+			opts: []Option{MPrecision(2), BBox(true)},
+		},
+		{
+			name: "collection z",
+			geom: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.LineString{{3, 4}, {5, 6}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{7, 8, 9},
+			},
+			opts: []Option{Size(true)},
+		},
+		{
+			name: "bound z",
+			geom: orb.GeometryZM{
+				Geometry: orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{1, 2},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := MarshalZM(tc.geom, 0, tc.opts...)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			g, err := UnmarshalZM(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			expected := tc.geom
+			if b, ok := expected.Geometry.(orb.Bound); ok {
+				expected.Geometry = b.ToPolygon()
+				expected.Extra = []float64{1, 1, 2, 2, 1}
+			}
+
+			if !reflect.DeepEqual(g, expected) {
+				t.Errorf("incorrect geometry")
+				t.Logf("%v", g)
+				t.Logf("%v", expected)
+			}
+
+			g2, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !orb.Equal(g2, expected.Geometry) {
+				t.Errorf("incorrect 2d geometry: %v", g2)
+			}
+		})
+	}
+}
+
+func TestUnmarshalIDs(t *testing.T) {
+	data, err := Marshal(orb.Collection{orb.Point{1, 2}, orb.Point{3, 4}}, 0, IDList([]int64{-1, 100}))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	g, ids, err := UnmarshalIDs(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !orb.Equal(g, orb.Collection{orb.Point{1, 2}, orb.Point{3, 4}}) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+
+	if !reflect.DeepEqual(ids, []int64{-1, 100}) {
+		t.Errorf("incorrect ids: %v", ids)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	cases := []string{
+		"",
+		"01",
+		"0100",
+		"010002",
+		"0208",
+		"02020a020202",
+		"0800",
+	}
+
+	for _, c := range cases {
+		data, _ := hex.DecodeString(c)
+		if _, err := Unmarshal(data); err == nil {
+			t.Errorf("expected error for %v", c)
+		}
+	}
+}
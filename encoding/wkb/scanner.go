@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/internal/wkbcommon"
 )
 
 var (
@@ -24,14 +25,14 @@ var (
 	ErrUnsupportedDataType = errors.New("wkb: scan value must be []byte")
 
 	// ErrNotWKB is returned when unmarshalling WKB and the data is not valid.
-	ErrNotWKB = errors.New("wkb: invalid data")
+	ErrNotWKB = wkbcommon.ErrNotWKB
 
 	// ErrIncorrectGeometry is returned when unmarshalling WKB data into the wrong type.
 	// For example, unmarshaling linestring data into a point.
 	ErrIncorrectGeometry = errors.New("wkb: incorrect geometry")
 
 	// ErrUnsupportedGeometry is returned when geometry type is not supported by this lib.
-	ErrUnsupportedGeometry = errors.New("wkb: unsupported geometry")
+	ErrUnsupportedGeometry = wkbcommon.ErrUnsupportedGeometry
 )
 
 // GeometryScanner is a thing that can scan in sql query results.
@@ -113,6 +114,19 @@ func (s *GeometryScanner) Scan(d interface{}) error {
 		data = data[:n]
 	}
 
+	// the typed scanners below only support 2d data, so drop any z and m values.
+	if _, typ, _, err := unmarshalByteOrderType(data); err == nil && wkbcommon.IsZM(typ) {
+		g, err := unmarshalZM(data)
+		if err != nil {
+			return err
+		}
+
+		data, err = Marshal(g)
+		if err != nil {
+			return err
+		}
+	}
+
 	switch g := s.g.(type) {
 	case nil:
 		m, err := Unmarshal(data)
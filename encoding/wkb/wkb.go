@@ -8,6 +8,7 @@ import (
 	"io"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/internal/wkbcommon"
 )
 
 // byteOrder represents little or big endian encoding.
@@ -207,6 +208,10 @@ func Unmarshal(data []byte) (orb.Geometry, error) {
 		return g, err
 	}
 
+	if wkbcommon.IsZM(typ) {
+		return unmarshalZM(data)
+	}
+
 	return nil, ErrUnsupportedGeometry
 }
 
@@ -242,6 +247,11 @@ func (d *Decoder) Decode() (orb.Geometry, error) {
 		return readCollection(d.r, order, buf)
 	}
 
+	if wkbcommon.IsZM(typ) {
+		g, _, err := wkbcommon.DecodeBody(d.r, wkbcommon.ByteOrder(order), typ)
+		return g.Geometry, err
+	}
+
 	return nil, ErrUnsupportedGeometry
 }
 
@@ -0,0 +1,61 @@
+package wkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/internal/wkbcommon"
+)
+
+// ErrInvalidZM is returned when marshalling a GeometryZM and the number of
+// extra values does not match the points and layout.
+var ErrInvalidZM = errors.New("wkb: extra values do not match the geometry and layout")
+
+// MarshalZM encodes the geometry, including the Z and M values, using the
+// ISO WKB type codes, e.g. 1001 for a Point Z.
+func MarshalZM(g orb.GeometryZM, byteOrder ...binary.ByteOrder) ([]byte, error) {
+	order := DefaultByteOrder
+	if len(byteOrder) > 0 {
+		order = byteOrder[0]
+	}
+
+	if g.Geometry == nil {
+		return nil, nil
+	}
+
+	if !g.Valid() {
+		return nil, ErrInvalidZM
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, geomLength(g.Geometry)))
+	if err := wkbcommon.Encode(buf, order, g, 0, false); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalZM decodes the data into a geometry keeping any Z and M values.
+// Both ISO and EWKB type codes are supported, any EWKB SRID is ignored.
+// Data without Z or M values is returned with the XY layout.
+func UnmarshalZM(data []byte) (orb.GeometryZM, error) {
+	g, _, err := wkbcommon.Decode(bytes.NewReader(data))
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return orb.GeometryZM{}, ErrNotWKB
+	}
+
+	return g, err
+}
+
+// unmarshalZM decodes the data and drops any Z and M values.
+func unmarshalZM(data []byte) (orb.Geometry, error) {
+	g, err := UnmarshalZM(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Geometry, nil
+}
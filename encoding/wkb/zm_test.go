@@ -0,0 +1,173 @@
+package wkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestMarshalZM(t *testing.T) {
+	cases := []struct {
+		name string
+		geom orb.GeometryZM
+	}{
+		{
+			name: "point z",
+			geom: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}},
+		},
+		{
+			name: "line string m",
+			geom: orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYM, Extra: []float64{5, 6}},
+		},
+		{
+			name: "polygon zm",
+			geom: orb.GeometryZM{
+				Geometry: orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				Layout:   orb.XYZM,
+				Extra:    []float64{1, 2, 3, 4, 5, 6, 1, 2},
+			},
+		},
+		{
+			name: "multi polygon z",
+			geom: orb.GeometryZM{
+				Geometry: orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{2, 2}, {3, 2}, {3, 3}, {2, 2}}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{1, 2, 3, 1, 4, 5, 6, 4},
+			},
+		},
+		{
+			name: "collection z",
+			geom: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.MultiPoint{{3, 4}, {5, 6}}, orb.MultiLineString{{{7, 8}}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "xy",
+			geom: orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+				data, err := MarshalZM(tc.geom, order)
+				if err != nil {
+					t.Fatalf("marshal error: %v", err)
+				}
+
+				g, err := UnmarshalZM(data)
+				if err != nil {
+					t.Fatalf("unmarshal error: %v", err)
+				}
+
+				if !reflect.DeepEqual(g, tc.geom) {
+					t.Errorf("incorrect geometry")
+					t.Logf("%v", g)
+					t.Logf("%v", tc.geom)
+				}
+
+				// the 2d decoders should drop the extra values
+				g2, err := Unmarshal(data)
+				if err != nil {
+					t.Fatalf("unmarshal error: %v", err)
+				}
+
+				if !orb.Equal(g2, tc.geom.Geometry) {
+					t.Errorf("incorrect 2d geometry: %v", g2)
+				}
+
+				g2, err = NewDecoder(bytes.NewReader(data)).Decode()
+				if err != nil {
+					t.Fatalf("decode error: %v", err)
+				}
+
+				if !orb.Equal(g2, tc.geom.Geometry) {
+					t.Errorf("incorrect decoded geometry: %v", g2)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalZM_isoType(t *testing.T) {
+	data, err := MarshalZM(orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZM, Extra: []float64{3, 4}})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if typ := binary.LittleEndian.Uint32(data[1:]); typ != 3001 {
+		t.Errorf("incorrect type: %v", typ)
+	}
+}
+
+func TestMarshalZM_invalid(t *testing.T) {
+	_, err := MarshalZM(orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYZ, Extra: []float64{1}})
+	if err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestUnmarshalZM_ewkb(t *testing.T) {
+	// SRID=4326;POINT Z(1 2 3) from PostGIS
+	data, _ := hex.DecodeString("01010000a0e6100000000000000000f03f00000000000000400000000000000840")
+
+	g, err := UnmarshalZM(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+
+	p, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !p.(orb.Point).Equal(orb.Point{1, 2}) {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	var sp orb.Point
+	err = Scanner(&sp).Scan(data)
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	if !sp.Equal(orb.Point{1, 2}) {
+		t.Errorf("incorrect scanned point: %v", sp)
+	}
+}
+
+func TestGeometryZM_ranges(t *testing.T) {
+	data, _ := MarshalZM(orb.GeometryZM{
+		Geometry: orb.LineString{{1, 2}, {3, 4}, {5, 6}},
+		Layout:   orb.XYZM,
+		Extra:    []float64{10, 100, -5, 200, 7, 50},
+	})
+
+	g, err := UnmarshalZM(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if min, max := g.ZRange(); min != -5 || max != 10 {
+		t.Errorf("incorrect z range: %v %v", min, max)
+	}
+
+	if min, max := g.MRange(); min != 50 || max != 200 {
+		t.Errorf("incorrect m range: %v %v", min, max)
+	}
+
+	if b := g.Bound(); !b.Equal(orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{5, 6}}) {
+		t.Errorf("incorrect bound: %v", b)
+	}
+}
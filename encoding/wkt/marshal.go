@@ -14,3 +14,12 @@ func MarshalString(g orb.Geometry) string {
 	wkt.Marshal(buf, g)
 	return buf.String()
 }
+
+// MarshalStringZM returns a WKT representation of the geometry with its
+// z and/or m values, e.g. POINT Z(1 2 3).
+func MarshalStringZM(g orb.GeometryZM) string {
+	buf := bytes.NewBuffer(nil)
+
+	wkt.MarshalZM(buf, g)
+	return buf.String()
+}
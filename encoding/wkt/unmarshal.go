@@ -95,3 +95,10 @@ func UnmarshalCollection(s string) (p orb.Collection, err error) {
 	}
 	return g, nil
 }
+
+// UnmarshalZM return geometry with z and/or m values by parse wkt string,
+// e.g. POINT Z(1 2 3) or POINT ZM(1 2 3 4). Without a tag 3 values per point
+// are XYZ and 4 are XYZM. The other Unmarshal functions drop these values.
+func UnmarshalZM(s string) (orb.GeometryZM, error) {
+	return wkt.UnmarshalZM(s)
+}
@@ -1,6 +1,9 @@
 package wkt
 
 import (
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/paulmach/orb"
@@ -238,3 +241,47 @@ func TestUnmarshalCollection(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkUnmarshalPolygon(b *testing.B) {
+	s := benchmarkPolygon(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := UnmarshalPolygon(s)
+		if err != nil {
+			b.Fatalf("unmarshal error: %v", err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalZM(b *testing.B) {
+	s := strings.Replace(benchmarkPolygon(1000), "POLYGON", "POLYGON Z", 1)
+	s = strings.Replace(s, ",", " 1,", -1)
+	s = strings.Replace(s, "))", " 1))", 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := UnmarshalZM(s)
+		if err != nil {
+			b.Fatalf("unmarshal error: %v", err)
+		}
+	}
+}
+
+// benchmarkPolygon returns the wkt of a polygon with n points.
+func benchmarkPolygon(n int) string {
+	var sb strings.Builder
+	sb.WriteString("POLYGON((")
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		sb.WriteString(strconv.FormatFloat(math.Cos(a), 'f', -1, 64))
+		sb.WriteByte(' ')
+		sb.WriteString(strconv.FormatFloat(math.Sin(a), 'f', -1, 64))
+		sb.WriteByte(',')
+	}
+	sb.WriteString("1 0))")
+
+	return sb.String()
+}
@@ -0,0 +1,165 @@
+package wkt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestMarshalStringZM(t *testing.T) {
+	cases := []struct {
+		name     string
+		geo      orb.GeometryZM
+		expected string
+	}{
+		{
+			name:     "point z",
+			geo:      orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}},
+			expected: "POINT Z(1 2 3)",
+		},
+		{
+			name:     "point m",
+			geo:      orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYM, Extra: []float64{4}},
+			expected: "POINT M(1 2 4)",
+		},
+		{
+			name:     "linestring zm",
+			geo:      orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYZM, Extra: []float64{5, 6, 7, 8}},
+			expected: "LINESTRING ZM(1 2 5 6,3 4 7 8)",
+		},
+		{
+			name:     "empty",
+			geo:      orb.GeometryZM{Geometry: orb.LineString{}, Layout: orb.XYZ},
+			expected: "LINESTRING Z EMPTY",
+		},
+		{
+			name: "collection",
+			geo: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.MultiPoint{{3, 4}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{5, 6},
+			},
+			expected: "GEOMETRYCOLLECTION Z(POINT Z(1 2 5),MULTIPOINT Z((3 4 6)))",
+		},
+		{
+			name:     "bound",
+			geo:      orb.GeometryZM{Geometry: orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}, Layout: orb.XYZ, Extra: []float64{1, 2}},
+			expected: "POLYGON Z((0 0 1,1 0 1,1 1 2,0 1 2,0 0 1))",
+		},
+		{
+			name:     "xy",
+			geo:      orb.GeometryZM{Geometry: orb.Point{1, 2}},
+			expected: "POINT(1 2)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := MarshalStringZM(tc.geo)
+			if v != tc.expected {
+				t.Log(v)
+				t.Log(tc.expected)
+				t.Errorf("incorrect wkt marshalling")
+			}
+		})
+	}
+}
+
+func TestUnmarshalZM(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected orb.GeometryZM
+	}{
+		{
+			name:     "point z",
+			s:        "POINT Z (1 2 3)",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}},
+		},
+		{
+			name:     "point m",
+			s:        "point m(1 2 3)",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYM, Extra: []float64{3}},
+		},
+		{
+			name:     "no tag",
+			s:        "LINESTRING(1 2 3 4,5 6 7 8)",
+			expected: orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {5, 6}}, Layout: orb.XYZM, Extra: []float64{3, 4, 7, 8}},
+		},
+		{
+			name: "polygon",
+			s:    "POLYGON Z((0 0 1,1 0 2,1 1 3,0 0 1))",
+			expected: orb.GeometryZM{
+				Geometry: orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{1, 2, 3, 1},
+			},
+		},
+		{
+			name: "collection",
+			s:    "GEOMETRYCOLLECTION Z(POINT Z(1 2 5),MULTIPOINT Z((3 4 6)))",
+			expected: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.MultiPoint{{3, 4}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{5, 6},
+			},
+		},
+		{
+			name:     "empty",
+			s:        "LINESTRING Z EMPTY",
+			expected: orb.GeometryZM{Geometry: orb.LineString{}, Layout: orb.XYZ},
+		},
+		{
+			name:     "xy",
+			s:        "POINT(1 2)",
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := UnmarshalZM(tc.s)
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !reflect.DeepEqual(g, tc.expected) {
+				t.Log(g)
+				t.Log(tc.expected)
+				t.Errorf("incorrect wkt unmarshalling")
+			}
+
+			if !g.Valid() {
+				t.Errorf("should be valid")
+			}
+		})
+	}
+}
+
+func TestUnmarshalZM_errors(t *testing.T) {
+	cases := []string{
+		"POINT Z(1 2)",
+		"POINT ZM(1 2 3)",
+		"LINESTRING(1 2 3,4 5)",
+		"GEOMETRYCOLLECTION(POINT Z(1 2 3),POINT M(1 2 3))",
+	}
+
+	for _, s := range cases {
+		_, err := UnmarshalZM(s)
+		if err == nil {
+			t.Errorf("expected error for %s", s)
+		}
+	}
+}
+
+func TestUnmarshal_dropsZ(t *testing.T) {
+	p, err := UnmarshalPoint("POINT Z(1 2 3)")
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !p.Equal(orb.Point{1, 2}) {
+		t.Errorf("incorrect point: %v", p)
+	}
+}
@@ -9,7 +9,21 @@ import (
 )
 
 // Area returns the area of the geometry on the earth.
+// Bounds with Min longitude greater than Max longitude wrap around the
+// antimeridian, for other geometries see AreaAntimeridian.
 func Area(g orb.Geometry) float64 {
+	return area(g, false)
+}
+
+// AreaAntimeridian returns the area of the geometry on the earth with each
+// edge taking the shorter way around, so polygons crossing the antimeridian,
+// e.g. from 179 to -179 longitude, are handled. Edges can not be longer
+// than 180 degrees of longitude.
+func AreaAntimeridian(g orb.Geometry) float64 {
+	return area(g, true)
+}
+
+func area(g orb.Geometry, wrap bool) float64 {
 	if g == nil {
 		return 0
 	}
@@ -18,15 +32,15 @@ func Area(g orb.Geometry) float64 {
 	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
 		return 0
 	case orb.Ring:
-		return math.Abs(ringArea(g))
+		return math.Abs(ringArea(g, wrap))
 	case orb.Polygon:
-		return polygonArea(g)
+		return polygonArea(g, wrap)
 	case orb.MultiPolygon:
-		return multiPolygonArea(g)
+		return multiPolygonArea(g, wrap)
 	case orb.Collection:
-		return collectionArea(g)
+		return collectionArea(g, wrap)
 	case orb.Bound:
-		return Area(g.ToRing())
+		return boundArea(g)
 	}
 
 	panic(fmt.Sprintf("geometry type not supported: %T", g))
@@ -36,10 +50,10 @@ func Area(g orb.Geometry) float64 {
 // Will return negative if the ring is in the clockwise direction.
 // Will implicitly close the ring.
 func SignedArea(r orb.Ring) float64 {
-	return ringArea(r)
+	return ringArea(r, false)
 }
 
-func ringArea(r orb.Ring) float64 {
+func ringArea(r orb.Ring, wrap bool) float64 {
 	if len(r) < 3 {
 		return 0
 	}
@@ -74,39 +88,58 @@ func ringArea(r orb.Ring) float64 {
 			hi = i + 2
 		}
 
-		area += (deg2rad(r[hi][0]) - deg2rad(r[lo][0])) * math.Sin(deg2rad(r[mi][1]))
+		dLon := deg2rad(r[hi][0]) - deg2rad(r[lo][0])
+		if wrap {
+			// use the change in longitude of each edge so rings
+			// crossing the antimeridian are handled.
+			dLon = deg2rad(lonDelta(r[lo][0], r[mi][0]) + lonDelta(r[mi][0], r[hi][0]))
+		}
+
+		area += dLon * math.Sin(deg2rad(r[mi][1]))
 	}
 
 	return -area * orb.EarthRadius * orb.EarthRadius / 2
 }
 
-func polygonArea(p orb.Polygon) float64 {
+func polygonArea(p orb.Polygon, wrap bool) float64 {
 	if len(p) == 0 {
 		return 0
 	}
 
-	sum := math.Abs(ringArea(p[0]))
+	sum := math.Abs(ringArea(p[0], wrap))
 	for i := 1; i < len(p); i++ {
-		sum -= math.Abs(ringArea(p[i]))
+		sum -= math.Abs(ringArea(p[i], wrap))
 	}
 
 	return sum
 }
 
-func multiPolygonArea(mp orb.MultiPolygon) float64 {
+func multiPolygonArea(mp orb.MultiPolygon, wrap bool) float64 {
 	sum := 0.0
 	for _, p := range mp {
-		sum += polygonArea(p)
+		sum += polygonArea(p, wrap)
 	}
 
 	return sum
 }
 
-func collectionArea(c orb.Collection) float64 {
-	area := 0.0
+func collectionArea(c orb.Collection, wrap bool) float64 {
+	sum := 0.0
 	for _, g := range c {
-		area += Area(g)
+		sum += area(g, wrap)
+	}
+
+	return sum
+}
+
+// boundArea returns the area of the lon/lat box, which may wrap around
+// the antimeridian.
+func boundArea(b orb.Bound) float64 {
+	dLon := b.Max[0] - b.Min[0]
+	if BoundWraps(b) {
+		dLon += 360
 	}
 
-	return area
+	return orb.EarthRadius * orb.EarthRadius * deg2rad(dLon) *
+		math.Abs(math.Sin(deg2rad(b.Max[1]))-math.Sin(deg2rad(b.Min[1])))
 }
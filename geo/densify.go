@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Densify returns a copy of the geometry with points added along the great
+// circle between consecutive points so no segment is longer than the given
+// number of meters. This allows long lines, like flight paths, to follow the
+// curve of the earth when drawn in a projection like web mercator.
+// Bounds are returned unchanged as their edges are not great circles.
+func Densify(g orb.Geometry, maxSegmentMeters float64) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return g
+	case orb.MultiPoint:
+		return append(orb.MultiPoint(nil), g...)
+	case orb.LineString:
+		return densifyLineString(g, maxSegmentMeters)
+	case orb.MultiLineString:
+		mls := make(orb.MultiLineString, 0, len(g))
+		for _, ls := range g {
+			mls = append(mls, densifyLineString(ls, maxSegmentMeters))
+		}
+		return mls
+	case orb.Ring:
+		return orb.Ring(densifyLineString(orb.LineString(g), maxSegmentMeters))
+	case orb.Polygon:
+		return densifyPolygon(g, maxSegmentMeters)
+	case orb.MultiPolygon:
+		mp := make(orb.MultiPolygon, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, densifyPolygon(p, maxSegmentMeters))
+		}
+		return mp
+	case orb.Collection:
+		c := make(orb.Collection, 0, len(g))
+		for _, cg := range g {
+			c = append(c, Densify(cg, maxSegmentMeters))
+		}
+		return c
+	case orb.Bound:
+		return g
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func densifyPolygon(p orb.Polygon, maxSegmentMeters float64) orb.Polygon {
+	result := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		result = append(result, orb.Ring(densifyLineString(orb.LineString(r), maxSegmentMeters)))
+	}
+
+	return result
+}
+
+func densifyLineString(ls orb.LineString, maxSegmentMeters float64) orb.LineString {
+	if len(ls) < 2 || maxSegmentMeters <= 0 {
+		return append(orb.LineString(nil), ls...)
+	}
+
+	result := make(orb.LineString, 0, len(ls))
+	for i := 0; i < len(ls)-1; i++ {
+		result = append(result, ls[i])
+
+		d := DistanceHaversine(ls[i], ls[i+1])
+		n := int(math.Ceil(d / maxSegmentMeters))
+		for j := 1; j < n; j++ {
+			result = append(result, IntermediatePoint(ls[i], ls[i+1], float64(j)/float64(n)))
+		}
+	}
+
+	return append(result, ls[len(ls)-1])
+}
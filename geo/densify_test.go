@@ -0,0 +1,132 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestDensify(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		Densify(g, 1000)
+	}
+
+	// jfk to lhr
+	ls := orb.LineString{{-73.8, 40.6}, {-0.5, 51.6}}
+	result := Densify(ls, 100000).(orb.LineString)
+
+	if result[0] != ls[0] || result[len(result)-1] != ls[1] {
+		t.Errorf("end points should not change: %v", result)
+	}
+
+	total := DistanceHaversine(ls[0], ls[1])
+	if n := int(math.Ceil(total / 100000)); len(result) != n+1 {
+		t.Errorf("incorrect number of points: %v != %v", len(result), n+1)
+	}
+
+	for i := 0; i < len(result)-1; i++ {
+		if d := DistanceHaversine(result[i], result[i+1]); d > 100000 {
+			t.Errorf("segment %d too long: %v", i, d)
+		}
+	}
+
+	if l := LengthHaversign(result); math.Abs(l-total) > 1e-3 {
+		t.Errorf("length should not change: %v != %v", l, total)
+	}
+
+	// great circle goes north of the straight line
+	if p := result[len(result)/2]; p[1] < 52 {
+		t.Errorf("should follow the great circle: %v", p)
+	}
+
+	if len(ls) != 2 {
+		t.Errorf("should not modify the input")
+	}
+}
+
+func TestDensify_antipodal(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {180, 0}}
+	result := Densify(ls, 1000000).(orb.LineString)
+
+	for i, p := range result {
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+			t.Fatalf("point %d is NaN: %v", i, p)
+		}
+	}
+
+	total := DistanceHaversine(ls[0], ls[1])
+	if l := LengthHaversign(result); math.Abs(l-total) > 1e-3 {
+		t.Errorf("length should not change: %v != %v", l, total)
+	}
+}
+
+func TestDensify_types(t *testing.T) {
+	ring := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+
+	cases := []struct {
+		name  string
+		geom  orb.Geometry
+		count int
+	}{
+		{name: "point", geom: orb.Point{1, 2}, count: 1},
+		{name: "short line", geom: orb.LineString{{0, 0}, {0.001, 0}}, count: 2},
+		{name: "ring", geom: ring, count: 9},
+		{name: "polygon", geom: orb.Polygon{ring, ring}, count: 18},
+		{name: "multi polygon", geom: orb.MultiPolygon{{ring}}, count: 9},
+		{name: "collection", geom: orb.Collection{ring, orb.Point{1, 2}}, count: 10},
+		{name: "bound", geom: ring.Bound(), count: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Densify(tc.geom, 600000)
+			if result.GeoJSONType() != tc.geom.GeoJSONType() {
+				t.Errorf("type changed: %v", result.GeoJSONType())
+			}
+
+			if c := count(result); c != tc.count {
+				t.Errorf("incorrect number of points: %v != %v", c, tc.count)
+			}
+		})
+	}
+
+	r := Densify(ring, 600000).(orb.Ring)
+	if !r.Closed() {
+		t.Errorf("ring should still be closed")
+	}
+}
+
+func count(g orb.Geometry) int {
+	switch g := g.(type) {
+	case orb.Point:
+		return 1
+	case orb.LineString:
+		return len(g)
+	case orb.Ring:
+		return len(g)
+	case orb.Polygon:
+		n := 0
+		for _, r := range g {
+			n += len(r)
+		}
+		return n
+	case orb.MultiPolygon:
+		n := 0
+		for _, p := range g {
+			n += count(p)
+		}
+		return n
+	case orb.Collection:
+		n := 0
+		for _, c := range g {
+			n += count(c)
+		}
+		return n
+	case orb.Bound:
+		return 2
+	}
+
+	return 0
+}
@@ -69,3 +69,99 @@ func Midpoint(p, p2 orb.Point) orb.Point {
 
 	return r
 }
+
+// PointAtBearingAndDistance returns the point at the given distance, in meters,
+// from the starting point traveling along the great circle in the direction
+// of the bearing, in degrees clockwise from north.
+func PointAtBearingAndDistance(p orb.Point, bearing, distance float64) orb.Point {
+	aLat := deg2rad(p[1])
+	aLon := deg2rad(p[0])
+	bearingRadians := deg2rad(bearing)
+
+	distanceRatio := distance / orb.EarthRadius
+	bLat := math.Asin(math.Sin(aLat)*math.Cos(distanceRatio) + math.Cos(aLat)*math.Sin(distanceRatio)*math.Cos(bearingRadians))
+	bLon := aLon +
+		math.Atan2(
+			math.Sin(bearingRadians)*math.Sin(distanceRatio)*math.Cos(aLat),
+			math.Cos(distanceRatio)-math.Sin(aLat)*math.Sin(bLat),
+		)
+
+	return orb.Point{normalizeLon(rad2deg(bLon)), rad2deg(bLat)}
+}
+
+// IntermediatePoint returns the point at the given fraction of the way along
+// the great circle path between the two points. A fraction of 0 is the first
+// point, 1 is the second and 0.5 is the same as the Midpoint.
+// Antipodal points are joined by any great circle, the path north along
+// the meridian of the first point is used.
+func IntermediatePoint(p1, p2 orb.Point, fraction float64) orb.Point {
+	if p1 == p2 {
+		return p1
+	}
+
+	aLat, aLon := deg2rad(p1[1]), deg2rad(p1[0])
+	bLat, bLon := deg2rad(p2[1]), deg2rad(p2[0])
+
+	// angular distance between the points
+	d := DistanceHaversine(p1, p2) / orb.EarthRadius
+	if d == 0 {
+		return p1
+	}
+
+	if math.Sin(d) < 1e-12 {
+		// antipodal, the formula below divides by zero.
+		return PointAtBearingAndDistance(p1, 0, fraction*d*orb.EarthRadius)
+	}
+
+	a := math.Sin((1-fraction)*d) / math.Sin(d)
+	b := math.Sin(fraction*d) / math.Sin(d)
+
+	x := a*math.Cos(aLat)*math.Cos(aLon) + b*math.Cos(bLat)*math.Cos(bLon)
+	y := a*math.Cos(aLat)*math.Sin(aLon) + b*math.Cos(bLat)*math.Sin(bLon)
+	z := a*math.Sin(aLat) + b*math.Sin(bLat)
+
+	return orb.Point{
+		rad2deg(math.Atan2(y, x)),
+		rad2deg(math.Atan2(z, math.Sqrt(x*x+y*y))),
+	}
+}
+
+// PointAtDistanceAlongLine returns the point at the given distance, in meters,
+// along the great circle segments of the line. Also returned is the index of the
+// segment containing the point, i.e. the point is between ls[i] and ls[i+1].
+// If the distance is beyond the end of the line the last point is returned.
+// Returns -1 for the index if the line is empty.
+func PointAtDistanceAlongLine(ls orb.LineString, distance float64) (orb.Point, int) {
+	if len(ls) == 0 {
+		return orb.Point{}, -1
+	}
+
+	if len(ls) == 1 || distance <= 0 {
+		return ls[0], 0
+	}
+
+	traveled := 0.0
+	for i := 0; i < len(ls)-1; i++ {
+		d := DistanceHaversine(ls[i], ls[i+1])
+		if traveled+d >= distance && d > 0 {
+			return IntermediatePoint(ls[i], ls[i+1], (distance-traveled)/d), i
+		}
+		traveled += d
+	}
+
+	return ls[len(ls)-1], len(ls) - 2
+}
+
+// normalizeLon puts the longitude in the range [-180, 180].
+func normalizeLon(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+
+	return lon - 180
+}
@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+	"github.com/paulmach/orb/project"
+)
+
+// DistanceBetween returns the minimum distance, in meters, between the two
+// lon/lat geometries along with the closest point on each. The distance is
+// zero if the geometries intersect. The closest points are found using an
+// equirectangular projection around the center of the geometries so the
+// result is most accurate over smaller areas.
+// If either geometry is empty the distance is +Inf.
+func DistanceBetween(g1, g2 orb.Geometry) (float64, orb.Point, orb.Point) {
+	if g1 == nil || g2 == nil {
+		return math.Inf(1), orb.Point{}, orb.Point{}
+	}
+
+	to, from := equirectangular(g1.Bound().Union(g2.Bound()))
+	d, p1, p2 := planar.DistanceBetween(
+		project.Geometry(orb.Clone(g1), to),
+		project.Geometry(orb.Clone(g2), to),
+	)
+	if math.IsInf(d, 1) {
+		return d, p1, p2
+	}
+
+	p1, p2 = from(p1), from(p2)
+	if d == 0 {
+		return 0, p1, p2
+	}
+
+	return DistanceHaversine(p1, p2), p1, p2
+}
+
+// HausdorffDistance returns the largest distance, in meters, from a vertex
+// of one geometry to the closest point on the other. See planar.HausdorffDistance
+// for more information. The geometries are projected using an equirectangular
+// projection around their center so the result is most accurate over smaller areas.
+func HausdorffDistance(g1, g2 orb.Geometry) float64 {
+	if g1 == nil || g2 == nil {
+		return math.Inf(1)
+	}
+
+	to, _ := equirectangular(g1.Bound().Union(g2.Bound()))
+	d := planar.HausdorffDistance(
+		project.Geometry(orb.Clone(g1), to),
+		project.Geometry(orb.Clone(g2), to),
+	)
+
+	return deg2rad(d) * orb.EarthRadius
+}
+
+// FrechetDistance returns the discrete Fréchet distance, in meters, between
+// the two lon/lat lines. See planar.FrechetDistance for more information.
+// If either line is empty the distance is +Inf.
+func FrechetDistance(ls1, ls2 orb.LineString) float64 {
+	return planar.FrechetDistanceWith(ls1, ls2, Distance)
+}
+
+// equirectangular returns the projection, and its inverse, that scales the
+// longitude so distances are roughly the same in both directions around
+// the center of the bound. Projected units are degrees of latitude.
+func equirectangular(b orb.Bound) (orb.Projection, orb.Projection) {
+	scale := math.Cos(deg2rad(b.Center()[1]))
+	if scale < 1e-6 {
+		scale = 1e-6
+	}
+
+	to := func(p orb.Point) orb.Point {
+		return orb.Point{p[0] * scale, p[1]}
+	}
+
+	from := func(p orb.Point) orb.Point {
+		return orb.Point{p[0] / scale, p[1]}
+	}
+
+	return to, from
+}
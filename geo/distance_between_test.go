@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestDistanceBetween(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		DistanceBetween(g, g)
+	}
+
+	// one degree of latitude
+	deg := deg2rad(1) * orb.EarthRadius
+
+	cases := []struct {
+		name     string
+		g1, g2   orb.Geometry
+		distance float64
+		p1, p2   orb.Point
+	}{
+		{
+			name:     "points",
+			g1:       orb.Point{0, 0},
+			g2:       orb.Point{0, 1},
+			distance: deg,
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{0, 1},
+		},
+		{
+			name:     "point and line",
+			g1:       orb.Point{0.5, 60.1},
+			g2:       orb.LineString{{0, 60}, {1, 60}},
+			distance: deg * 0.1,
+			p1:       orb.Point{0.5, 60.1},
+			p2:       orb.Point{0.5, 60},
+		},
+		{
+			name:     "inside polygon",
+			g1:       orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			g2:       orb.Point{0.5, 0.5},
+			distance: 0,
+			p1:       orb.Point{0.5, 0.5},
+			p2:       orb.Point{0.5, 0.5},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, p1, p2 := DistanceBetween(tc.g1, tc.g2)
+			if math.Abs(d-tc.distance) > 1 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if Distance(p1, tc.p1) > 1 || Distance(p2, tc.p2) > 1 {
+				t.Errorf("incorrect points: %v %v != %v %v", p1, p2, tc.p1, tc.p2)
+			}
+		})
+	}
+}
+
+func TestHausdorffDistance(t *testing.T) {
+	route := orb.LineString{{-122.4, 37.8}, {-122.3, 37.8}}
+	trace := orb.LineString{{-122.4, 37.8}, {-122.35, 37.801}, {-122.3, 37.8}}
+
+	d := HausdorffDistance(route, trace)
+	if expected := deg2rad(0.001) * orb.EarthRadius; math.Abs(d-expected) > 0.01 {
+		t.Errorf("incorrect distance: %v != %v", d, expected)
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	route := orb.LineString{{0, 0}, {0, 1}, {0, 2}}
+	trace := orb.LineString{{0.001, 0}, {0.001, 1}, {0.001, 2}}
+
+	d := FrechetDistance(route, trace)
+	if expected := DistanceHaversine(route[0], trace[0]); math.Abs(d-expected) > epsilon {
+		t.Errorf("incorrect distance: %v != %v", d, expected)
+	}
+
+	if d := FrechetDistance(route, nil); !math.IsInf(d, 1) {
+		t.Errorf("expected +Inf, got %v", d)
+	}
+}
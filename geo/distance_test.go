@@ -89,3 +89,144 @@ func TestMidpoint(t *testing.T) {
 		t.Errorf("expected %v, got %v", answer, m)
 	}
 }
+
+func TestPointAtBearingAndDistance(t *testing.T) {
+	cases := []struct {
+		name     string
+		point    orb.Point
+		bearing  float64
+		distance float64
+	}{
+		{name: "north", point: orb.Point{-1.8444, 53.1506}, bearing: 0, distance: 10000},
+		{name: "east", point: orb.Point{-1.8444, 53.1506}, bearing: 90, distance: 10000},
+		{name: "south west", point: orb.Point{0.1406, 52.2047}, bearing: -135, distance: 150000},
+		{name: "across antimeridian", point: orb.Point{179.9, 10}, bearing: 90, distance: 50000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := PointAtBearingAndDistance(tc.point, tc.bearing, tc.distance)
+			if d := DistanceHaversine(tc.point, p); math.Abs(d-tc.distance) > 1e-3 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if b := Bearing(tc.point, p); math.Abs(b-tc.bearing) > 1e-6 {
+				t.Errorf("incorrect bearing: %v != %v", b, tc.bearing)
+			}
+
+			if p[0] < -180 || p[0] > 180 {
+				t.Errorf("longitude not normalized: %v", p)
+			}
+		})
+	}
+}
+
+func TestIntermediatePoint(t *testing.T) {
+	p1 := orb.Point{-1.8444, 53.1506}
+	p2 := orb.Point{0.1406, 52.2047}
+
+	if p := IntermediatePoint(p1, p2, 0); DistanceHaversine(p, p1) > epsilon {
+		t.Errorf("should be first point: %v", p)
+	}
+
+	if p := IntermediatePoint(p1, p2, 1); DistanceHaversine(p, p2) > epsilon {
+		t.Errorf("should be second point: %v", p)
+	}
+
+	if p := IntermediatePoint(p1, p2, 0.5); DistanceHaversine(p, Midpoint(p1, p2)) > epsilon {
+		t.Errorf("should be midpoint: %v != %v", p, Midpoint(p1, p2))
+	}
+
+	total := DistanceHaversine(p1, p2)
+	p := IntermediatePoint(p1, p2, 0.25)
+	if d := DistanceHaversine(p1, p); math.Abs(d-total/4) > 1e-3 {
+		t.Errorf("incorrect distance: %v != %v", d, total/4)
+	}
+
+	if p := IntermediatePoint(p1, p1, 0.5); p != p1 {
+		t.Errorf("same point should be the point: %v", p)
+	}
+}
+
+func TestIntermediatePoint_antipodal(t *testing.T) {
+	cases := []struct {
+		name     string
+		p1, p2   orb.Point
+		midpoint orb.Point
+	}{
+		{
+			name:     "equator",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{180, 0},
+			midpoint: orb.Point{0, 90},
+		},
+		{
+			name:     "poles",
+			p1:       orb.Point{10, -90},
+			p2:       orb.Point{10, 90},
+			midpoint: orb.Point{10, 0},
+		},
+		{
+			name:     "mid latitude",
+			p1:       orb.Point{-30, 45},
+			p2:       orb.Point{150, -45},
+			midpoint: orb.Point{150, 45},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := IntermediatePoint(tc.p1, tc.p2, 0.5)
+			if math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+				t.Fatalf("should not be NaN: %v", p)
+			}
+
+			if d := DistanceHaversine(p, tc.midpoint); d > 1e-3 {
+				t.Errorf("incorrect midpoint: %v != %v", p, tc.midpoint)
+			}
+
+			if p := IntermediatePoint(tc.p1, tc.p2, 1); DistanceHaversine(p, tc.p2) > 1e-3 {
+				t.Errorf("should be second point: %v", p)
+			}
+		})
+	}
+}
+
+func TestPointAtDistanceAlongLine(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {1, 0}, {1, 1}}
+	seg := DistanceHaversine(ls[0], ls[1])
+
+	cases := []struct {
+		name     string
+		distance float64
+		point    orb.Point
+		index    int
+	}{
+		{name: "start", distance: 0, point: orb.Point{0, 0}, index: 0},
+		{name: "first segment", distance: seg / 2, point: orb.Point{0.5, 0}, index: 0},
+		{name: "first vertex", distance: seg, point: orb.Point{1, 0}, index: 0},
+		{name: "second segment", distance: seg * 1.5, point: orb.Point{1, 0.5}, index: 1},
+		{name: "past the end", distance: seg * 3, point: orb.Point{1, 1}, index: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, i := PointAtDistanceAlongLine(ls, tc.distance)
+			if DistanceHaversine(p, tc.point) > 1e-3 {
+				t.Errorf("incorrect point: %v != %v", p, tc.point)
+			}
+
+			if i != tc.index {
+				t.Errorf("incorrect index: %v != %v", i, tc.index)
+			}
+		})
+	}
+
+	if _, i := PointAtDistanceAlongLine(nil, 10); i != -1 {
+		t.Errorf("empty line should have index -1: %v", i)
+	}
+
+	if p, i := PointAtDistanceAlongLine(orb.LineString{{1, 2}}, 10); p != (orb.Point{1, 2}) || i != 0 {
+		t.Errorf("single point line incorrect: %v %v", p, i)
+	}
+}
@@ -52,3 +52,44 @@ func ExampleLength() {
 	// Output:
 	// 325 meters
 }
+
+func ExampleGeodesicInverse() {
+	jfk := orb.Point{-73.8, 40.6}
+	lhr := orb.Point{-0.5, 51.6}
+
+	d, azimuth1, azimuth2 := geo.GeodesicInverse(jfk, lhr)
+
+	fmt.Printf("%0.3f meters, %0.3f, %0.3f", d, azimuth1, azimuth2)
+	// Output:
+	// 5551759.400 meters, 51.199, 107.822
+}
+
+func ExampleGeodesicDirect() {
+	jfk := orb.Point{-73.8, 40.6}
+
+	p, azimuth := geo.GeodesicDirect(jfk, 51.198882845579824, 5551759.400318/2)
+
+	fmt.Printf("%0.6f, %0.3f", p, azimuth)
+	// Output:
+	// [-41.395076 52.273700], 75.101
+}
+
+func ExampleDensify() {
+	flight := orb.LineString{{-73.8, 40.6}, {-0.5, 51.6}}
+
+	path := geo.Densify(flight, 1000000).(orb.LineString)
+
+	fmt.Printf("%d points, %0.1f", len(path), path[3])
+	// Output:
+	// 7 points, [-41.4 52.3]
+}
+
+func ExamplePointAtDistanceAlongLine() {
+	ls := orb.LineString{{0, 0}, {1, 0}, {1, 1}}
+
+	p, i := geo.PointAtDistanceAlongLine(ls, 150000)
+
+	fmt.Printf("%0.3f on segment %d", p, i)
+	// Output:
+	// [1.000 0.347] on segment 1
+}
@@ -0,0 +1,157 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/length"
+)
+
+// The geodesic functions use the WGS84 ellipsoid instead of a sphere.
+// They are slower than their spherical counterparts but accurate to
+// about 15 nanometers for any pair of points, including nearly antipodal ones.
+
+// GeodesicInverse returns the shortest distance, in meters, between the two
+// points on the WGS84 ellipsoid. Also returned is the azimuth, in degrees
+// clockwise from north, at the start and end of the path.
+// Azimuths are in the range [-180, 180].
+func GeodesicInverse(p1, p2 orb.Point) (distance, azimuth1, azimuth2 float64) {
+	s12, azi1, azi2, _ := wgs84.inverse(p1[1], p1[0], p2[1], p2[0])
+	return s12, azi1, azi2
+}
+
+// GeodesicDirect returns the point reached after traveling the distance,
+// in meters, from the point in the direction of the azimuth, in degrees
+// clockwise from north, along the WGS84 ellipsoid. Also returned is
+// the azimuth at the destination.
+func GeodesicDirect(p orb.Point, azimuth, distance float64) (orb.Point, float64) {
+	lat, lon, azi2 := wgs84.direct(p[1], p[0], azimuth, distance)
+	return orb.Point{lon, lat}, azi2
+}
+
+// DistanceGeodesic returns the shortest distance, in meters, between
+// the two points on the WGS84 ellipsoid.
+func DistanceGeodesic(p1, p2 orb.Point) float64 {
+	s12, _, _, _ := wgs84.inverse(p1[1], p1[0], p2[1], p2[0])
+	return s12
+}
+
+// LengthGeodesic returns the length of the boundary of the geometry
+// using the WGS84 ellipsoid.
+func LengthGeodesic(g orb.Geometry) float64 {
+	return length.Length(g, DistanceGeodesic)
+}
+
+// AreaGeodesic returns the area of the geometry, in square meters,
+// on the WGS84 ellipsoid. The edges of the polygons are geodesics.
+func AreaGeodesic(g orb.Geometry) float64 {
+	if g == nil {
+		return 0
+	}
+
+	switch g := g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
+		return 0
+	case orb.Ring:
+		return math.Abs(ringAreaGeodesic(g))
+	case orb.Polygon:
+		return polygonAreaGeodesic(g)
+	case orb.MultiPolygon:
+		sum := 0.0
+		for _, p := range g {
+			sum += polygonAreaGeodesic(p)
+		}
+		return sum
+	case orb.Collection:
+		sum := 0.0
+		for _, c := range g {
+			sum += AreaGeodesic(c)
+		}
+		return sum
+	case orb.Bound:
+		return AreaGeodesic(g.ToRing())
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// SignedAreaGeodesic returns the signed area of the ring on the WGS84 ellipsoid.
+// Will return negative if the ring is in the clockwise direction.
+// Will implicitly close the ring.
+func SignedAreaGeodesic(r orb.Ring) float64 {
+	return ringAreaGeodesic(r)
+}
+
+func polygonAreaGeodesic(p orb.Polygon) float64 {
+	if len(p) == 0 {
+		return 0
+	}
+
+	sum := math.Abs(ringAreaGeodesic(p[0]))
+	for i := 1; i < len(p); i++ {
+		sum -= math.Abs(ringAreaGeodesic(p[i]))
+	}
+
+	return sum
+}
+
+// ringAreaGeodesic sums the area between each edge and the equator.
+// The number of times the ring crosses the prime meridian is tracked
+// so rings around a pole are handled correctly.
+func ringAreaGeodesic(r orb.Ring) float64 {
+	if len(r) < 3 {
+		return 0
+	}
+
+	l := len(r)
+	if r[0] == r[l-1] {
+		l--
+	}
+
+	area, crossings := 0.0, 0
+	for i := 0; i < l; i++ {
+		p1, p2 := r[i], r[(i+1)%l]
+
+		_, _, _, _, _, S12 := wgs84.genInverse(p1[1], p1[0], p2[1], p2[0])
+		area += S12
+		crossings += transit(p1[0], p2[0])
+	}
+
+	area0 := 4 * math.Pi * wgs84.c2
+	if crossings&1 == 1 {
+		if area < 0 {
+			area += area0 / 2
+		} else {
+			area -= area0 / 2
+		}
+	}
+
+	// area is in the clockwise sense, reverse it so counter-clockwise
+	// is positive and put it in (-area0/2, area0/2].
+	area = -area
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+
+	return area
+}
+
+// transit returns 1 or -1 if crossing the prime meridian in the
+// east or west direction, otherwise 0.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	}
+
+	return 0
+}
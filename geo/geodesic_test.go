@@ -0,0 +1,309 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// Expected values are from the GeographicLib test suite and GeodSolve,
+// https://geographiclib.sourceforge.io
+
+func TestGeodesicInverse(t *testing.T) {
+	cases := []struct {
+		name     string
+		p1, p2   orb.Point
+		distance float64
+		azi1     float64
+		azi2     float64
+	}{
+		{
+			name:     "jfk to lhr",
+			p1:       orb.Point{-73.8, 40.6},
+			p2:       orb.Point{-0.5, 51.6},
+			distance: 5551759.400318,
+			azi1:     51.198882845579824,
+			azi2:     107.821776735514248,
+		},
+		{
+			name:     "jfk to cdg",
+			p1:       orb.Point{-73.8, 40.6},
+			p2:       orb.Point{2.55, 49.01666667},
+			distance: 5853226,
+			azi1:     53.47022,
+			azi2:     111.59367,
+		},
+		{
+			name:     "quarter meridian",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{0, 90},
+			distance: 10001965.7293127,
+			azi1:     0,
+			azi2:     0,
+		},
+		{
+			name:     "quarter equator",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{90, 0},
+			distance: 10018754.171394622,
+			azi1:     90,
+			azi2:     90,
+		},
+		{
+			name:     "same point",
+			p1:       orb.Point{1, 2},
+			p2:       orb.Point{1, 2},
+			distance: 0,
+			azi1:     180,
+			azi2:     180,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, azi1, azi2 := GeodesicInverse(tc.p1, tc.p2)
+			if math.Abs(d-tc.distance) > 0.5 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if math.Abs(azi1-tc.azi1) > 1e-5 {
+				t.Errorf("incorrect azimuth1: %v != %v", azi1, tc.azi1)
+			}
+
+			if math.Abs(azi2-tc.azi2) > 1e-5 {
+				t.Errorf("incorrect azimuth2: %v != %v", azi2, tc.azi2)
+			}
+		})
+	}
+}
+
+func TestGeodesicInverse_accuracy(t *testing.T) {
+	cases := []struct {
+		name     string
+		p1, p2   orb.Point
+		distance float64
+	}{
+		{
+			name:     "short line",
+			p1:       orb.Point{0, 36.493349428792},
+			p2:       orb.Point{0.0000008, 36.49334942879201},
+			distance: 0.072,
+		},
+		{
+			name:     "antipodal on equator",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{180, 0},
+			distance: 20003931.4586254,
+		},
+		{
+			name:     "near antipodal 1",
+			p1:       orb.Point{0, 88.202499451857},
+			p2:       orb.Point{179.981022032992859592, -88.202499451857},
+			distance: 20003898.214,
+		},
+		{
+			name:     "near antipodal 2",
+			p1:       orb.Point{0, 89.262080389218},
+			p2:       orb.Point{179.992207982775375662, -89.262080389218},
+			distance: 20003925.854,
+		},
+		{
+			name:     "near antipodal 3",
+			p1:       orb.Point{0, 89.333123580033},
+			p2:       orb.Point{179.99295812360148422, -89.333123580032997687},
+			distance: 20003926.881,
+		},
+		{
+			name:     "near antipodal 4",
+			p1:       orb.Point{0, 56.320923501171},
+			p2:       orb.Point{179.664747671772880215, -56.320923501171},
+			distance: 19993558.287,
+		},
+		{
+			name:     "near antipodal 5",
+			p1:       orb.Point{0, 52.784459512564},
+			p2:       orb.Point{179.634407464943777557, -52.784459512563990912},
+			distance: 19991596.095,
+		},
+		{
+			name:     "near antipodal 6",
+			p1:       orb.Point{0, 48.522876735459},
+			p2:       orb.Point{179.599720456223079643, -48.522876735458},
+			distance: 19989144.774,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := DistanceGeodesic(tc.p1, tc.p2)
+			if math.Abs(d-tc.distance) > 0.5e-3 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			// reversing the points should be the same distance
+			if r := DistanceGeodesic(tc.p2, tc.p1); math.Abs(d-r) > 1e-8 {
+				t.Errorf("reverse distance not the same: %v != %v", d, r)
+			}
+		})
+	}
+}
+
+func TestGeodesicDirect(t *testing.T) {
+	cases := []struct {
+		name     string
+		p        orb.Point
+		azimuth  float64
+		distance float64
+		expected orb.Point
+		azi2     float64
+	}{
+		{
+			name:     "jfk heading out",
+			p:        orb.Point{-73.77888889, 40.63972222},
+			azimuth:  53.5,
+			distance: 5850e3,
+			expected: orb.Point{2.56106, 49.01467},
+			azi2:     111.62947,
+		},
+		{
+			name:     "quarter meridian",
+			p:        orb.Point{0, 0},
+			azimuth:  0,
+			distance: 10001965.7293127,
+			expected: orb.Point{0, 90},
+			azi2:     0,
+		},
+		{
+			name:     "along equator",
+			p:        orb.Point{170, 0},
+			azimuth:  90,
+			distance: 10018754.171394622 / 9,
+			expected: orb.Point{-180, 0},
+			azi2:     90,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, azi2 := GeodesicDirect(tc.p, tc.azimuth, tc.distance)
+			if math.Abs(p[0]-tc.expected[0]) > 1e-5 && math.Abs(math.Abs(p[0]-tc.expected[0])-360) > 1e-5 {
+				t.Errorf("incorrect lon: %v != %v", p, tc.expected)
+			}
+
+			if math.Abs(p[1]-tc.expected[1]) > 1e-5 {
+				t.Errorf("incorrect lat: %v != %v", p, tc.expected)
+			}
+
+			if math.Abs(azi2-tc.azi2) > 1e-5 {
+				t.Errorf("incorrect azimuth: %v != %v", azi2, tc.azi2)
+			}
+		})
+	}
+}
+
+func TestGeodesicDirect_roundTrip(t *testing.T) {
+	points := []orb.Point{
+		{-73.8, 40.6},
+		{-0.5, 51.6},
+		{151.2, -33.9},
+		{-179.9, 89},
+		{179.9, -60},
+	}
+
+	for _, p1 := range points {
+		for _, p2 := range points {
+			d, azi1, azi2 := GeodesicInverse(p1, p2)
+			p, a := GeodesicDirect(p1, azi1, d)
+			if DistanceGeodesic(p, p2) > 1e-6 {
+				t.Errorf("%v to %v: incorrect point: %v", p1, p2, p)
+			}
+
+			if d > 0 && math.Abs(a-azi2) > 1e-8 {
+				t.Errorf("%v to %v: incorrect azimuth: %v != %v", p1, p2, a, azi2)
+			}
+		}
+	}
+}
+
+func TestAreaGeodesic(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		AreaGeodesic(g)
+	}
+
+	cases := []struct {
+		name   string
+		geom   orb.Geometry
+		area   float64
+		length float64
+	}{
+		{
+			name:   "octant",
+			geom:   orb.Ring{{0, 0}, {90, 0}, {0, 90}, {0, 0}},
+			area:   510065621724088.45 / 8,
+			length: 2*10001965.7293127 + 10018754.171394622,
+		},
+		{
+			name:   "around the north pole",
+			geom:   orb.Polygon{{{0, 89}, {90, 89}, {180, 89}, {270, 89}, {0, 89}}},
+			area:   24952305678.0,
+			length: 631819.8745,
+		},
+		{
+			name:   "around the north pole, offset",
+			geom:   orb.Ring{{0.1, 89}, {90.1, 89}, {-179.9, 89}, {0.1, 89}},
+			area:   12476152838.5,
+			length: 539297,
+		},
+		{
+			name: "polygon with hole",
+			geom: orb.Polygon{
+				{{0, 0}, {90, 0}, {0, 90}, {0, 0}},
+				{{0, 0}, {90, 0}, {0, 90}, {0, 0}},
+			},
+			area:   0,
+			length: 4*10001965.7293127 + 2*10018754.171394622,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := AreaGeodesic(tc.geom)
+			if math.Abs(a-tc.area) > 1 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+
+			l := LengthGeodesic(tc.geom)
+			if math.Abs(l-tc.length) > 1 {
+				t.Errorf("incorrect length: %v != %v", l, tc.length)
+			}
+		})
+	}
+}
+
+func TestSignedAreaGeodesic(t *testing.T) {
+	ring := orb.Ring{{0, 0}, {0.001, 0}, {0.001, 0.001}, {0, 0.001}, {0, 0}}
+
+	ccw := SignedAreaGeodesic(ring)
+	if ccw <= 0 {
+		t.Errorf("ccw ring should be positive: %v", ccw)
+	}
+
+	// close to the spherical area
+	if a := Area(ring); math.Abs(ccw-a)/a > 0.01 {
+		t.Errorf("area not close to spherical: %v != %v", ccw, a)
+	}
+
+	ring.Reverse()
+	if cw := SignedAreaGeodesic(ring); cw != -ccw {
+		t.Errorf("cw ring should be negative: %v", cw)
+	}
+}
+
+func TestLengthGeodesic(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		LengthGeodesic(g)
+	}
+}
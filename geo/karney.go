@@ -0,0 +1,1176 @@
+package geo
+
+import "math"
+
+// This file is a port of the geodesic routines from GeographicLib by
+// Charles Karney, https://geographiclib.sourceforge.io, which is MIT licensed.
+// See C. F. F. Karney, Algorithms for geodesics, J. Geodesy 87, 43-55 (2013),
+// https://doi.org/10.1007/s00190-012-0578-z
+//
+// Only the parts needed for an oblate ellipsoid like WGS84 are included
+// and the series are to order 6, accurate to about 15 nanometers.
+
+const (
+	order = 6
+	nA1   = order
+	nC1   = order
+	nC1p  = order
+	nA2   = order
+	nC2   = order
+	nA3   = order
+	nC3   = order
+	nC4   = order
+	nC3x  = (nC3 * (nC3 - 1)) / 2
+	nC4x  = (nC4 * (nC4 + 1)) / 2
+	nC    = order + 1
+
+	maxit1 = 20
+	maxit2 = maxit1 + 53 + 10
+)
+
+var (
+	tiny    = math.Sqrt(2.2250738585072014e-308)
+	tol0    = 2.220446049250313e-16
+	tol1    = 200 * tol0
+	tol2    = math.Sqrt(tol0)
+	tolb    = tol0 * tol2
+	xthresh = 1000 * tol2
+)
+
+// ellipsoid holds the constants needed to solve geodesic problems.
+type ellipsoid struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	A3x [nA3]float64
+	C3x [nC3x]float64
+	C4x [nC4x]float64
+}
+
+// wgs84 is the ellipsoid used by GPS.
+var wgs84 = newEllipsoid(6378137, 1/298.257223563)
+
+func newEllipsoid(a, f float64) *ellipsoid {
+	e := &ellipsoid{a: a, f: f}
+	e.f1 = 1 - f
+	e.e2 = f * (2 - f)
+	e.ep2 = e.e2 / (e.f1 * e.f1)
+	e.n = f / (2 - f)
+	e.b = a * e.f1
+
+	e.c2 = (a*a + e.b*e.b*math.Atanh(math.Sqrt(e.e2))/math.Sqrt(e.e2)) / 2
+	if e.e2 == 0 {
+		e.c2 = a * a
+	}
+
+	e.etol2 = 0.1 * tol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+
+	e.a3coeff()
+	e.c3coeff()
+	e.c4coeff()
+
+	return e
+}
+
+// inverse solves the inverse geodesic problem, returning the distance
+// between the points, the azimuths at each end and the area between
+// the geodesic and the equator.
+func (e *ellipsoid) inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2, S12 float64) {
+	salp1, calp1, salp2, calp2, s12, S12 := e.genInverse(lat1, lon1, lat2, lon2)
+	return s12, atan2d(salp1, calp1), atan2d(salp2, calp2), S12
+}
+
+func (e *ellipsoid) genInverse(lat1, lon1, lat2, lon2 float64) (salp1, calp1, salp2, calp2, s12, S12 float64) {
+	var Ca [nC]float64
+
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if lon12 < 0 {
+		lonsign = -1
+	}
+
+	// if very close to being on the same half-meridian, then make it so.
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := deg2rad(lon12)
+
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// if really close to the equator, treat as on equator.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+
+	// swap points so that point with higher (abs) latitude is point 1.
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+
+	// make lat1 <= -0
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	// Now we have
+	//     0 <= lon12 <= 180
+	//     -90 <= lat1 <= -0
+	//     lat1 <= lat2 <= -lat1
+	// lonsign, swapp, latsign register the transformation to bring the
+	// coordinates to this canonical form.
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= e.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= e.f1
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(tiny, cbet2)
+
+	// If cbet1 < -sbet1, then cbet2 - cbet1 is a sensitive measure of the
+	// |bet1| - |bet2|. Alternatively (cbet1 >= -sbet1), abs(sbet2) + sbet1 is
+	// a better measure. Sometimes these quantities vanish and in that case
+	// we force bet2 = +/- bet1 exactly.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + e.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + e.ep2*sbet2*sbet2)
+
+	var sig12, s12x, m12x float64
+	omg12, somg12, comg12 := 0.0, 2.0, 0.0 // somg12 > 1 marks it needs to be calculated
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// endpoints are on a single full meridian,
+		// so the geodesic might lie on a meridian.
+		calp1, salp1 = clam12, slam12 // head to the target longitude
+		calp2, salp2 = 1, 0           // at the target we're heading north
+
+		// tan(bet) = tan(sig) * cos(alp)
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, _, _ = e.lengths(e.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2)
+
+		// sig12 > pi/2 for a meridional geodesic which is not a shortest path.
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*tiny || (sig12 < tol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= e.b
+			s12x *= e.b
+		} else {
+			// m12 < 0, i.e., prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (e.f <= 0 || lon12s >= e.f*180) {
+		// geodesic runs along equator
+		calp1, calp2, salp1, salp2 = 0, 0, 1, 1
+		s12x = e.a * lam12
+		sig12 = lam12 / e.f1
+		omg12 = sig12
+		m12x = e.b * math.Sin(sig12)
+	} else if !meridian {
+		// Now point1 and point2 belong within a hemisphere bounded by a
+		// meridian and geodesic is neither meridional or equatorial.
+
+		// figure a starting point for Newton's method
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = e.inverseStart(
+			sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12)
+
+		if sig12 >= 0 {
+			// short lines, inverseStart sets salp2, calp2, dnm
+			s12x = sig12 * e.b * dnm
+			m12x = dnm * dnm * e.b * math.Sin(sig12/dnm)
+			omg12 = lam12 / (e.f1 * dnm)
+		} else {
+			// Newton's method. This is a straightforward solution of f(alp1) =
+			// lambda12(alp1) - lam12 = 0 with one wrinkle. f(alp) has exactly one
+			// root in the interval (0, pi) and its derivative is positive at the
+			// root. Thus f(alp) is positive for alp > alp1 and negative for alp <
+			// alp1. During the course of the iteration, a range (alp1a, alp1b) is
+			// maintained which brackets the root and with each evaluation of
+			// f(alp) the range is shrunk, if possible. Newton's method is
+			// restarted whenever the derivative of f is negative (because the new
+			// value of alp1 is then further from the solution) or if the new
+			// estimate of alp1 lies outside (0,pi); in this case, the new starting
+			// guess is taken to be (alp1a + alp1b) / 2.
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+
+			// bracketing range
+			salp1a, calp1a, salp1b, calp1b := tiny, 1.0, tiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = e.lambda12(
+					sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1,
+					slam12, clam12, numit < maxit1)
+
+				tol := tol0
+				if tripn {
+					tol *= 8
+				}
+
+				// reversed test to allow escape with NaNs
+				if tripb || !(math.Abs(v) >= tol) || numit == maxit2 {
+					break
+				}
+
+				// update bracketing values
+				if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+
+				if numit < maxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sincos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+
+							// In some regimes we don't get quadratic convergence because
+							// slope -> 0. So use convergence conditions based on epsilon
+							// instead of sqrt(epsilon).
+							tripn = math.Abs(v) <= 16*tol0
+							continue
+						}
+					}
+				}
+
+				// Either dv was not positive or updated value was outside legal
+				// range. Use the midpoint of the bracket as the next estimate.
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+			}
+
+			s12x, m12x, _, _, _ = e.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2)
+			m12x *= e.b
+			s12x *= e.b
+
+			// omg12 = lam12 - domg12
+			sdomg12, cdomg12 := math.Sincos(domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+
+	s12 = 0 + s12x // convert -0 to 0
+
+	// the area, from lambda12: sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+	if calp0 != 0 && salp0 != 0 {
+		// from lambda12: tan(bet) = tan(sig) * cos(alp)
+		ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+		ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+		k2 := calp0 * calp0 * e.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+
+		// multiplier = a^2 * e^2 * cos(alpha0) * sin(alpha0).
+		A4 := e.a * e.a * calp0 * salp0 * e.e2
+		e.c4f(eps, Ca[:])
+		B41 := sinCosSeries(false, ssig1, csig1, Ca[:], nC4)
+		B42 := sinCosSeries(false, ssig2, csig2, Ca[:], nC4)
+		S12 = A4 * (B42 - B41)
+	} else {
+		// avoid problems with indeterminate sig1, sig2 on equator
+		S12 = 0
+	}
+
+	if !meridian && somg12 == 2 {
+		somg12, comg12 = math.Sincos(omg12)
+	}
+
+	var alp12 float64
+	if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		// use tan(Gamma/2) = tan(omg12/2)
+		// * (tan(bet1/2)+tan(bet2/2))/(1+tan(bet1/2)*tan(bet2/2))
+		// with tan(x/2) = sin(x)/(1+cos(x))
+		domg12 := 1 + comg12
+		dbet1 := 1 + cbet1
+		dbet2 := 1 + cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		// alp12 = alp2 - alp1, used in atan2 so no need to normalize
+		salp12 := salp2*calp1 - calp2*salp1
+		calp12 := calp2*calp1 + salp2*salp1
+
+		// The right thing appears to happen if alp1 = +/-180 and alp2 = 0, viz
+		// salp12 = -0 and alp12 = -180. However this depends on the sign
+		// being attached to 0 correctly. The following ensures the correct behavior.
+		if salp12 == 0 && calp12 < 0 {
+			salp12 = tiny * calp1
+			calp12 = -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+
+	S12 += e.c2 * alp12
+	S12 *= swapp * lonsign * latsign
+	S12 += 0 // convert -0 to 0
+
+	// convert calp, salp to azimuth accounting for lonsign, swapp, latsign.
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	return salp1, calp1, salp2, calp2, s12, S12
+}
+
+// direct solves the direct geodesic problem, returning the position and
+// azimuth after traveling the distance from the point in the direction.
+func (e *ellipsoid) direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	var C1a, C1pa [nC1 + 1]float64
+	var C3a [nC3]float64
+
+	lat1 = latFix(lat1)
+	azi1 = angNormalize(azi1)
+
+	// guard against underflow in salp0
+	salp1, calp1 := sincosd(angRound(azi1))
+
+	sbet1, cbet1 := sincosd(angRound(lat1))
+	sbet1 *= e.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	// evaluate alp0 from sin(alp1) * cos(bet1) = sin(alp0),
+	salp0 := salp1 * cbet1 // alp0 in [0, pi/2 - |bet1|]
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	// Evaluate sig with tan(bet1) = tan(sig1) * cos(alp1).
+	// sig = 0 is nearest northward crossing of equator.
+	// Evaluate omg1 with tan(omg1) = sin(alp0) * tan(sig1).
+	ssig1, somg1 := sbet1, salp0*sbet1
+	csig1 := 1.0
+	if sbet1 != 0 || calp1 != 0 {
+		csig1 = cbet1 * calp1
+	}
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1) // sig1 in (-pi, pi]
+
+	k2 := calp0 * calp0 * e.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+
+	A1m1 := a1m1f(eps)
+	c1f(eps, C1a[:])
+	B11 := sinCosSeries(true, ssig1, csig1, C1a[:], nC1)
+	s, c := math.Sincos(B11)
+
+	// tau1 = sig1 + B11
+	stau1 := ssig1*c + csig1*s
+	ctau1 := csig1*c - ssig1*s
+
+	c1pf(eps, C1pa[:])
+
+	e.c3f(eps, C3a[:])
+	A3c := -e.f * salp0 * e.a3f(eps)
+	B31 := sinCosSeries(true, ssig1, csig1, C3a[:], nC3-1)
+
+	// interpret s12 as distance
+	tau12 := s12 / (e.b * (1 + A1m1))
+	s, c = math.Sincos(tau12)
+
+	// tau2 = tau1 + tau12
+	B12 := -sinCosSeries(true, stau1*c+ctau1*s, ctau1*c-stau1*s, C1pa[:], nC1p)
+	sig12 := tau12 - (B12 - B11)
+	ssig12, csig12 := math.Sincos(sig12)
+
+	// sig2 = sig1 + sig12
+	ssig2 := ssig1*csig12 + csig1*ssig12
+	csig2 := csig1*csig12 - ssig1*ssig12
+
+	// sin(bet2) = cos(alp0) * sin(sig2)
+	sbet2 := calp0 * ssig2
+	cbet2 := math.Hypot(salp0, calp0*csig2)
+	if cbet2 == 0 {
+		// i.e., salp0 = 0, csig2 = 0. Break the degeneracy in this case
+		cbet2, csig2 = tiny, tiny
+	}
+
+	// tan(omg2) = sin(alp0) * tan(sig2)
+	somg2, comg2 := salp0*ssig2, csig2 // no need to normalize
+
+	// tan(alp0) = cos(sig2)*tan(alp2)
+	salp2, calp2 := salp0, calp0*csig2 // no need to normalize
+
+	// omg12 = omg2 - omg1
+	omg12 := math.Atan2(somg2*comg1-comg2*somg1, comg2*comg1+somg2*somg1)
+
+	lam12 := omg12 + A3c*(sig12+(sinCosSeries(true, ssig2, csig2, C3a[:], nC3-1)-B31))
+	lon12 := rad2deg(lam12)
+
+	lon2 = angNormalize(angNormalize(lon1) + angNormalize(lon12))
+	lat2 = atan2d(sbet2, e.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+
+	return lat2, lon2, azi2
+}
+
+// lengths returns the distance, reduced length, m0 and geodesic scales
+// of the geodesic, the distances are scaled by b.
+func (e *ellipsoid) lengths(
+	eps, sig12,
+	ssig1, csig1, dn1,
+	ssig2, csig2, dn2,
+	cbet1, cbet2 float64,
+) (s12b, m12b, m0, M12, M21 float64) {
+	var Ca, Cb [nC]float64
+
+	A1 := a1m1f(eps)
+	c1f(eps, Ca[:])
+
+	A2 := a2m1f(eps)
+	c2f(eps, Cb[:])
+
+	m0 = A1 - A2
+	A2 = 1 + A2
+	A1 = 1 + A1
+
+	B1 := sinCosSeries(true, ssig2, csig2, Ca[:], nC1) - sinCosSeries(true, ssig1, csig1, Ca[:], nC1)
+	s12b = A1 * (sig12 + B1)
+
+	B2 := sinCosSeries(true, ssig2, csig2, Cb[:], nC2) - sinCosSeries(true, ssig1, csig1, Cb[:], nC2)
+	J12 := m0*sig12 + (A1*B1 - A2*B2)
+
+	// Missing a factor of b.
+	// Add parens around (csig1 * ssig2) and (ssig1 * csig2) to ensure
+	// accurate cancellation in the case of coincident points.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*J12
+
+	csig12 := csig1*csig2 + ssig1*ssig2
+	t := e.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+	M12 = csig12 + (t*ssig2-csig2*J12)*ssig1/dn1
+	M21 = csig12 - (t*ssig1-csig1*J12)*ssig2/dn2
+
+	return s12b, m12b, m0, M12, M21
+}
+
+// inverseStart returns a starting point for Newton's method in salp1 and calp1.
+// If the line is short then sig12 is set, otherwise it is -1.
+func (e *ellipsoid) inverseStart(
+	sbet1, cbet1, dn1,
+	sbet2, cbet2, dn2,
+	lam12, slam12, clam12 float64,
+) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+
+	// bet12 = bet2 - bet1 in [0, pi); bet12a = bet2 + bet1 in (-pi, 0]
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+
+		// sin((bet1+bet2)/2)^2 = (sbet1 + sbet2)^2 / ((sbet1 + sbet2)^2 + (cbet1 + cbet2)^2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + e.ep2*sbetm2)
+		omg12 := lam12 / (e.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < e.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+
+		// set return value
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(e.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(e.n)*math.Pi*cbet1*cbet1 {
+		// Nothing to do, zeroth order spherical approximation is OK
+	} else {
+		// Scale lam12 and bet2 to x, y coordinate system where antipodal point
+		// is at origin and singular point is at y = 0, x = -1.
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - pi
+
+		k2 := sbet1 * sbet1 * e.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := e.f * cbet1 * e.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+
+		if y > -tol1 && x > -1-xthresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			// Estimate alp1, by solving the astroid problem.
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+
+			// update spherical estimate of alp1 using omg12 instead of lam12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+
+	// sanity check on starting guess. Backwards check allows NaN through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the difference between the longitude difference for
+// the azimuth alp1 and the required one, along with the derivative if diffp.
+func (e *ellipsoid) lambda12(
+	sbet1, cbet1, dn1,
+	sbet2, cbet2, dn2,
+	salp1, calp1,
+	slam120, clam120 float64,
+	diffp bool,
+) (lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+	var Ca [nC]float64
+
+	if sbet1 == 0 && calp1 == 0 {
+		// break degeneracy of equatorial line. This case has already been handled.
+		calp1 = -tiny
+	}
+
+	// sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+
+	// tan(bet1) = tan(sig1) * cos(alp1)
+	// tan(omg1) = sin(alp0) * tan(sig1) = tan(omg1)=tan(alp1)*sin(bet1)
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	// Enforce symmetries in the case abs(bet2) = -bet1. Need to be careful
+	// about this case, since this can yield singularities in the Newton
+	// iteration.
+	// sin(alp2) * cos(bet2) = sin(alp0)
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+
+	// calp2 = sqrt(1 - sq(salp2))
+	//       = sqrt(sq(calp0) - sq(sbet2)) / cbet2
+	// and subst for calp0 and rearrange to give (choose positive sqrt
+	// to give alp2 in [0, pi/2]).
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+
+	// tan(bet2) = tan(sig2) * cos(alp2)
+	// tan(omg2) = sin(alp0) * tan(sig2).
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+
+	// sig12 = sig2 - sig1, limit to [0, pi]
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+
+	// omg12 = omg2 - omg1, limit to [0, pi]
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := calp0 * calp0 * e.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	e.c3f(eps, Ca[:])
+	B312 := sinCosSeries(true, ssig2, csig2, Ca[:], nC3-1) - sinCosSeries(true, ssig1, csig1, Ca[:], nC3-1)
+	domg12 = -e.f * e.a3f(eps) * salp0 * (sig12 + B312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * e.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _, _ = e.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2)
+			dlam12 *= e.f1 / (calp2 * cbet2)
+		}
+	}
+
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12
+}
+
+func (e *ellipsoid) a3f(eps float64) float64 {
+	// evaluate A3
+	return polyval(nA3-1, e.A3x[:], eps)
+}
+
+func (e *ellipsoid) c3f(eps float64, c []float64) {
+	// evaluate C3 coeffs
+	// elements c[1] through c[nC3 - 1] are set
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ { // l is index of C3[l]
+		m := nC3 - l - 1 // order of polynomial in eps
+		mult *= eps
+		c[l] = mult * polyval(m, e.C3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func (e *ellipsoid) c4f(eps float64, c []float64) {
+	// evaluate C4 coeffs
+	// elements c[0] through c[nC4 - 1] are set
+	mult := 1.0
+	o := 0
+	for l := 0; l < nC4; l++ { // l is index of C4[l]
+		m := nC4 - l - 1 // order of polynomial in eps
+		c[l] = mult * polyval(m, e.C4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+func (e *ellipsoid) a3coeff() {
+	coeff := [...]float64{
+		// A3, coeff of eps^5, polynomial in n of order 0
+		-3, 128,
+		// A3, coeff of eps^4, polynomial in n of order 1
+		-2, -3, 64,
+		// A3, coeff of eps^3, polynomial in n of order 2
+		-1, -3, -1, 16,
+		// A3, coeff of eps^2, polynomial in n of order 2
+		3, -1, -2, 8,
+		// A3, coeff of eps^1, polynomial in n of order 1
+		1, -1, 2,
+		// A3, coeff of eps^0, polynomial in n of order 0
+		1, 1,
+	}
+
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- { // coeff of eps^j
+		m := nA3 - j - 1 // order of polynomial in n
+		if j < m {
+			m = j
+		}
+		e.A3x[k] = polyval(m, coeff[o:], e.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+func (e *ellipsoid) c3coeff() {
+	coeff := [...]float64{
+		// C3[1], coeff of eps^5, polynomial in n of order 0
+		3, 128,
+		// C3[1], coeff of eps^4, polynomial in n of order 1
+		2, 5, 128,
+		// C3[1], coeff of eps^3, polynomial in n of order 2
+		-1, 3, 3, 64,
+		// C3[1], coeff of eps^2, polynomial in n of order 2
+		-1, 0, 1, 8,
+		// C3[1], coeff of eps^1, polynomial in n of order 1
+		-1, 1, 4,
+		// C3[2], coeff of eps^5, polynomial in n of order 0
+		5, 256,
+		// C3[2], coeff of eps^4, polynomial in n of order 1
+		1, 3, 128,
+		// C3[2], coeff of eps^3, polynomial in n of order 2
+		-3, -2, 3, 64,
+		// C3[2], coeff of eps^2, polynomial in n of order 2
+		1, -3, 2, 32,
+		// C3[3], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[3], coeff of eps^4, polynomial in n of order 1
+		-10, 9, 384,
+		// C3[3], coeff of eps^3, polynomial in n of order 2
+		5, -9, 5, 192,
+		// C3[4], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[4], coeff of eps^4, polynomial in n of order 1
+		-14, 7, 512,
+		// C3[5], coeff of eps^5, polynomial in n of order 0
+		21, 2560,
+	}
+
+	o, k := 0, 0
+	for l := 1; l < nC3; l++ { // l is index of C3[l]
+		for j := nC3 - 1; j >= l; j-- { // coeff of eps^j
+			m := nC3 - j - 1 // order of polynomial in n
+			if j < m {
+				m = j
+			}
+			e.C3x[k] = polyval(m, coeff[o:], e.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (e *ellipsoid) c4coeff() {
+	coeff := [...]float64{
+		// C4[0], coeff of eps^5, polynomial in n of order 0
+		97, 15015,
+		// C4[0], coeff of eps^4, polynomial in n of order 1
+		1088, 156, 45045,
+		// C4[0], coeff of eps^3, polynomial in n of order 2
+		-224, -4784, 1573, 45045,
+		// C4[0], coeff of eps^2, polynomial in n of order 3
+		-10656, 14144, -4576, -858, 45045,
+		// C4[0], coeff of eps^1, polynomial in n of order 4
+		64, 624, -4576, 6864, -3003, 15015,
+		// C4[0], coeff of eps^0, polynomial in n of order 5
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		// C4[1], coeff of eps^5, polynomial in n of order 0
+		1, 9009,
+		// C4[1], coeff of eps^4, polynomial in n of order 1
+		-2944, 468, 135135,
+		// C4[1], coeff of eps^3, polynomial in n of order 2
+		5792, 1040, -1287, 135135,
+		// C4[1], coeff of eps^2, polynomial in n of order 3
+		5952, -11648, 9152, -2574, 135135,
+		// C4[1], coeff of eps^1, polynomial in n of order 4
+		-64, -624, 4576, -6864, 3003, 135135,
+		// C4[2], coeff of eps^5, polynomial in n of order 0
+		8, 10725,
+		// C4[2], coeff of eps^4, polynomial in n of order 1
+		1856, -936, 225225,
+		// C4[2], coeff of eps^3, polynomial in n of order 2
+		-8448, 4992, -1144, 225225,
+		// C4[2], coeff of eps^2, polynomial in n of order 3
+		-1440, 4160, -4576, 1716, 225225,
+		// C4[3], coeff of eps^5, polynomial in n of order 0
+		-136, 63063,
+		// C4[3], coeff of eps^4, polynomial in n of order 1
+		1024, -208, 105105,
+		// C4[3], coeff of eps^3, polynomial in n of order 2
+		3584, -3328, 1144, 315315,
+		// C4[4], coeff of eps^5, polynomial in n of order 0
+		-128, 135135,
+		// C4[4], coeff of eps^4, polynomial in n of order 1
+		-2560, 832, 405405,
+		// C4[5], coeff of eps^5, polynomial in n of order 0
+		128, 99099,
+	}
+
+	o, k := 0, 0
+	for l := 0; l < nC4; l++ { // l is index of C4[l]
+		for j := nC4 - 1; j >= l; j-- { // coeff of eps^j
+			m := nC4 - j - 1 // order of polynomial in n
+			e.C4x[k] = polyval(m, coeff[o:], e.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// a1m1f is the scale factor A1-1 = mean value of (d/dsigma)I1 - 1
+func a1m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (1-eps)*A1-1, polynomial in eps2 of order 3
+		1, 4, 64, 0, 256,
+	}
+
+	m := nA1 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f is the coefficients C1[l] in the Fourier expansion of B1
+func c1f(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C1[1]/eps^1, polynomial in eps2 of order 2
+		-1, 6, -16, 32,
+		// C1[2]/eps^2, polynomial in eps2 of order 2
+		-9, 64, -128, 2048,
+		// C1[3]/eps^3, polynomial in eps2 of order 1
+		9, -16, 768,
+		// C1[4]/eps^4, polynomial in eps2 of order 1
+		3, -5, 512,
+		// C1[5]/eps^5, polynomial in eps2 of order 0
+		-7, 1280,
+		// C1[6]/eps^6, polynomial in eps2 of order 0
+		-7, 2048,
+	}
+
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= nC1; l++ { // l is index of C1p[l]
+		m := (nC1 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// c1pf is the coefficients C1p[l] in the Fourier expansion of B1p
+func c1pf(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C1p[1]/eps^1, polynomial in eps2 of order 2
+		205, -432, 768, 1536,
+		// C1p[2]/eps^2, polynomial in eps2 of order 2
+		4005, -4736, 3840, 12288,
+		// C1p[3]/eps^3, polynomial in eps2 of order 1
+		-225, 116, 384,
+		// C1p[4]/eps^4, polynomial in eps2 of order 1
+		-7173, 2695, 7680,
+		// C1p[5]/eps^5, polynomial in eps2 of order 0
+		3467, 7680,
+		// C1p[6]/eps^6, polynomial in eps2 of order 0
+		38081, 61440,
+	}
+
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= nC1p; l++ { // l is index of C1p[l]
+		m := (nC1p - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a2m1f is the scale factor A2-1 = mean value of (d/dsigma)I2 - 1
+func a2m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (eps+1)*A2-1, polynomial in eps2 of order 3
+		-11, -28, -192, 0, 256,
+	}
+
+	m := nA2 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f is the coefficients C2[l] in the Fourier expansion of B2
+func c2f(eps float64, c []float64) {
+	coeff := [...]float64{
+		// C2[1]/eps^1, polynomial in eps2 of order 2
+		1, 2, 16, 32,
+		// C2[2]/eps^2, polynomial in eps2 of order 2
+		35, 64, 384, 2048,
+		// C2[3]/eps^3, polynomial in eps2 of order 1
+		15, 80, 768,
+		// C2[4]/eps^4, polynomial in eps2 of order 1
+		7, 35, 512,
+		// C2[5]/eps^5, polynomial in eps2 of order 0
+		63, 1280,
+		// C2[6]/eps^6, polynomial in eps2 of order 0
+		77, 2048,
+	}
+
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= nC2; l++ { // l is index of C2[l]
+		m := (nC2 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// sinCosSeries evaluates, using Clenshaw summation, the sum of
+// c[i] * sin(2*i*x) for i = 1..n if sinp, otherwise the sum of
+// c[i] * cos((2*i+1)*x) for i = 0..n-1. N.B. c[0] is unused for sin series
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	k := n // point to one beyond last element
+	if sinp {
+		k++
+	}
+
+	ar := 2 * (cosx - sinx) * (cosx + sinx) // 2 * cos(2 * x)
+
+	y0, y1 := 0.0, 0.0
+	if n&1 == 1 {
+		k--
+		y0 = c[k]
+	}
+
+	for n /= 2; n > 0; n-- { // unroll loop x 2, so accumulators return to their original role
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+
+	if sinp {
+		return 2 * sinx * cosx * y0 // sin(2 * x) * y0
+	}
+
+	return cosx * (y0 - y1) // cos(x) * (y0 - y1)
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for positive root k.
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+
+	if q == 0 && r <= 0 {
+		// y = 0 with |x| <= 1. Handle this case directly.
+		// For y small, positive root is k = abs(y)/sqrt(1-x^2)
+		return 0
+	}
+
+	// avoid possible division by zero when r = 0 by multiplying equations
+	// for s and t by r^3 and r, resp.
+	S := p * q / 4 // S = r^3 * s
+	r2 := r * r
+	r3 := r * r2
+
+	// The discriminant of the quadratic equation for T3. This is zero on
+	// the evolute curve p^(1/3)+q^(1/3) = 1
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+
+		// Pick the sign on the sqrt to maximize abs(T3). This minimizes loss
+		// of precision due to cancellation. The result is unchanged because
+		// of the way the T is used in definition of u.
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc)
+		}
+
+		// N.B. cbrt always returns the real root. cbrt(-8) = -2.
+		T := math.Cbrt(T3) // T = r * t
+
+		// T can be zero; but then r2 / T -> 0.
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		// T is complex, but the way u is defined the result is real.
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+
+		// There are three possible cube roots. We choose the root which
+		// avoids cancellation. Note that disc < 0 implies that r < 0.
+		u += 2 * r * math.Cos(ang/3)
+	}
+
+	v := math.Sqrt(u*u + q) // guaranteed positive
+
+	// avoid loss of accuracy when u < 0.
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v) // positive?
+
+	// Rearrange expression for k to avoid loss of accuracy due to
+	// subtraction. Division by 0 not possible because uv > 0, w >= 0.
+	return uv / (math.Sqrt(uv+w*w) + w) // guaranteed positive
+}
+
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+
+	return y
+}
+
+// sumx returns the error free sum, s + t = u + v.
+func sumx(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	t = -(up + vpp)
+
+	return s, t
+}
+
+func angNormalize(x float64) float64 {
+	x = math.Remainder(x, 360)
+	if x == -180 {
+		return 180
+	}
+
+	return x
+}
+
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+
+	return x
+}
+
+// angDiff computes y - x, reduced to (-180, 180] along with the error.
+func angDiff(x, y float64) (d, e float64) {
+	d, t := sumx(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+
+	return sumx(d, t)
+}
+
+// angRound reduces the precision of small angles. The makes the
+// smallest gap in x = 1/16 - nextafter(1/16, 0) = 1/2^57 for reals = 0.7 pm
+// on the earth if x is an angle in degrees.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+
+	// The compiler mustn't "simplify" z - (z - y) to y
+	if y < z {
+		y = z - (z - y)
+	}
+
+	if x < 0 {
+		return -y
+	}
+
+	return y
+}
+
+// sincosd returns the sine and cosine of x in degrees,
+// exact for multiples of 90 degrees.
+func sincosd(x float64) (sinx, cosx float64) {
+	r := math.Remainder(x, 90)
+	q := int(math.Round((x - r) / 90))
+
+	s, c := math.Sincos(deg2rad(r))
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+
+	// Set sign of 0 results. -0 only produced for sin(-0)
+	if x != 0 {
+		sinx += 0
+		cosx += 0
+	}
+
+	return sinx, cosx
+}
+
+// atan2d returns atan2(y, x) in degrees, with the exact
+// results for the multiples of 90 degrees.
+func atan2d(y, x float64) float64 {
+	// In order to minimize round-off errors, this function rearranges the
+	// arguments so that result of atan2 is in the range [-pi/4, pi/4] before
+	// converting it to degrees and mapping the result to the correct
+	// quadrant.
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+
+	if x < 0 {
+		x = -x
+		q++
+	}
+
+	// here x >= 0 and x >= abs(y), so angle is in [-pi/4, pi/4]
+	ang := rad2deg(math.Atan2(y, x))
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+
+	return ang
+}
+
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
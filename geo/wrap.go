@@ -0,0 +1,184 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// A Bound with Min longitude greater than Max longitude wraps around the
+// antimeridian, e.g. {Min: {170, -20}, Max: {-170, -10}} is 20 degrees wide
+// and covers Fiji. The functions below understand this representation,
+// orb.Bound methods do not. Use clip.Antimeridian to split such a bound,
+// or any geometry crossing the antimeridian, into parts that do not wrap.
+
+// WrappedBound returns the smallest bound containing the lon/lat geometry,
+// allowing it to wrap around the antimeridian. For example, a line from
+// 179 to -179 longitude has a bound 2 degrees wide with Min longitude 179
+// and Max longitude -179. A wrapping bound is returned as is.
+func WrappedBound(g orb.Geometry) orb.Bound {
+	if g == nil {
+		return orb.Bound{}
+	}
+
+	if b, ok := g.(orb.Bound); ok {
+		return b
+	}
+
+	b := g.Bound()
+	if b.Max[0]-b.Min[0] <= 180 {
+		// can't be smaller by wrapping
+		return b
+	}
+
+	spans := lonSpans(g, nil)
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i][0] < spans[j][0]
+	})
+
+	// the bound is everything but the largest gap between the
+	// longitude ranges covered by the geometry.
+	end := spans[0][1]
+	gap, min, max := 0.0, 0.0, 0.0
+	for _, s := range spans[1:] {
+		if d := s[0] - end; d > gap {
+			gap, min, max = d, s[0], end
+		}
+		end = math.Max(end, s[1])
+	}
+
+	if spans[0][0]+360-end >= gap {
+		return b
+	}
+
+	b.Min[0] = min
+	b.Max[0] = max
+	return b
+}
+
+// BoundWraps returns true if the bound wraps around the antimeridian,
+// i.e. the Min longitude is greater than the Max longitude.
+func BoundWraps(b orb.Bound) bool {
+	return b.Min[0] > b.Max[0]
+}
+
+// BoundContains returns true if the point is inside the, possibly wrapping, bound.
+func BoundContains(b orb.Bound, p orb.Point) bool {
+	if p[1] < b.Min[1] || b.Max[1] < p[1] {
+		return false
+	}
+
+	if !BoundWraps(b) {
+		return b.Min[0] <= p[0] && p[0] <= b.Max[0]
+	}
+
+	return b.Min[0] <= p[0] || p[0] <= b.Max[0]
+}
+
+// BoundIntersects returns true if the, possibly wrapping, bounds intersect.
+func BoundIntersects(b1, b2 orb.Bound) bool {
+	if b1.Max[1] < b2.Min[1] || b2.Max[1] < b1.Min[1] {
+		return false
+	}
+
+	for _, r1 := range lonRanges(b1) {
+		for _, r2 := range lonRanges(b2) {
+			if r1[0] <= r2[1] && r2[0] <= r1[1] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lonRanges returns the longitude ranges of the bound,
+// two if the bound wraps.
+func lonRanges(b orb.Bound) [][2]float64 {
+	if !BoundWraps(b) {
+		return [][2]float64{{b.Min[0], b.Max[0]}}
+	}
+
+	return [][2]float64{{b.Min[0], 180}, {-180, b.Max[0]}}
+}
+
+// lonSpans appends the longitude range covered by each point and
+// segment of the geometry. Segments crossing the antimeridian are split.
+func lonSpans(g orb.Geometry, spans [][2]float64) [][2]float64 {
+	switch g := g.(type) {
+	case nil:
+		return spans
+	case orb.Point:
+		return append(spans, [2]float64{g[0], g[0]})
+	case orb.MultiPoint:
+		for _, p := range g {
+			spans = append(spans, [2]float64{p[0], p[0]})
+		}
+		return spans
+	case orb.LineString:
+		if len(g) == 1 {
+			return lonSpans(g[0], spans)
+		}
+
+		for i := 1; i < len(g); i++ {
+			a, b := g[i-1][0], g[i][0]
+			if a > b {
+				a, b = b, a
+			}
+
+			if d := b - a; d <= 180 || d == 360 {
+				spans = append(spans, [2]float64{a, b})
+			} else {
+				spans = append(spans, [2]float64{-180, a}, [2]float64{b, 180})
+			}
+		}
+		return spans
+	case orb.MultiLineString:
+		for _, ls := range g {
+			spans = lonSpans(ls, spans)
+		}
+		return spans
+	case orb.Ring:
+		return lonSpans(orb.LineString(g), spans)
+	case orb.Polygon:
+		for _, r := range g {
+			spans = lonSpans(orb.LineString(r), spans)
+		}
+		return spans
+	case orb.MultiPolygon:
+		for _, p := range g {
+			spans = lonSpans(p, spans)
+		}
+		return spans
+	case orb.Collection:
+		for _, c := range g {
+			spans = lonSpans(c, spans)
+		}
+		return spans
+	case orb.Bound:
+		return append(spans, lonRanges(g)...)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// lonDelta returns the change in longitude going from a to b, taking
+// the shorter way around so crossing the antimeridian is handled.
+// A change of exactly 360 degrees is kept, e.g. the edge of a polygon
+// covering the whole world from -180 to 180.
+func lonDelta(a, b float64) float64 {
+	d := b - a
+	if math.Abs(d) == 360 {
+		return d
+	}
+
+	if d > 180 {
+		d -= 360
+	} else if d < -180 {
+		d += 360
+	}
+
+	return d
+}
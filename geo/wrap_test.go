@@ -0,0 +1,181 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestWrappedBound(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		WrappedBound(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		output orb.Bound
+	}{
+		{
+			name:   "line not crossing",
+			input:  orb.LineString{{-10, 0}, {10, 1}},
+			output: orb.Bound{Min: orb.Point{-10, 0}, Max: orb.Point{10, 1}},
+		},
+		{
+			name:   "line crossing",
+			input:  orb.LineString{{179, 0}, {-179, 1}},
+			output: orb.Bound{Min: orb.Point{179, 0}, Max: orb.Point{-179, 1}},
+		},
+		{
+			name:   "wide line",
+			input:  orb.LineString{{-170, 0}, {0, 1}, {170, 2}},
+			output: orb.Bound{Min: orb.Point{-170, 0}, Max: orb.Point{170, 2}},
+		},
+		{
+			name: "fiji",
+			input: orb.MultiPolygon{
+				{{{177, -19}, {180, -19}, {180, -16}, {177, -16}, {177, -19}}},
+				{{{-180, -19}, {-179, -19}, {-179, -16}, {-180, -16}, {-180, -19}}},
+			},
+			output: orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{-179, -16}},
+		},
+		{
+			name:   "wrapping bound",
+			input:  orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 1}},
+			output: orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if b := WrappedBound(tc.input); b != tc.output {
+				t.Errorf("incorrect bound: %v != %v", b, tc.output)
+			}
+		})
+	}
+}
+
+func TestBoundContains(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 10}}
+
+	cases := []struct {
+		name     string
+		point    orb.Point
+		contains bool
+	}{
+		{name: "west", point: orb.Point{175, 5}, contains: true},
+		{name: "east", point: orb.Point{-175, 5}, contains: true},
+		{name: "on antimeridian", point: orb.Point{180, 5}, contains: true},
+		{name: "outside", point: orb.Point{0, 5}, contains: false},
+		{name: "above", point: orb.Point{175, 11}, contains: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := BoundContains(b, tc.point); v != tc.contains {
+				t.Errorf("incorrect: %v != %v", v, tc.contains)
+			}
+		})
+	}
+
+	if !BoundContains(orb.Bound{Max: orb.Point{1, 1}}, orb.Point{0.5, 0.5}) {
+		t.Errorf("should contain point in normal bound")
+	}
+}
+
+func TestBoundIntersects(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 10}}
+
+	cases := []struct {
+		name       string
+		bound      orb.Bound
+		intersects bool
+	}{
+		{
+			name:       "west",
+			bound:      orb.Bound{Min: orb.Point{160, 0}, Max: orb.Point{175, 5}},
+			intersects: true,
+		},
+		{
+			name:       "east",
+			bound:      orb.Bound{Min: orb.Point{-175, 0}, Max: orb.Point{-160, 5}},
+			intersects: true,
+		},
+		{
+			name:       "between",
+			bound:      orb.Bound{Min: orb.Point{-160, 0}, Max: orb.Point{160, 5}},
+			intersects: false,
+		},
+		{
+			name:       "both wrap",
+			bound:      orb.Bound{Min: orb.Point{179, 0}, Max: orb.Point{-179, 5}},
+			intersects: true,
+		},
+		{
+			name:       "above",
+			bound:      orb.Bound{Min: orb.Point{175, 20}, Max: orb.Point{176, 25}},
+			intersects: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := BoundIntersects(b, tc.bound); v != tc.intersects {
+				t.Errorf("incorrect: %v != %v", v, tc.intersects)
+			}
+
+			if v := BoundIntersects(tc.bound, b); v != tc.intersects {
+				t.Errorf("reverse incorrect: %v != %v", v, tc.intersects)
+			}
+		})
+	}
+}
+
+func TestArea_antimeridian(t *testing.T) {
+	crossing := orb.Polygon{{{177, -19}, {-179, -19}, {-179, -16}, {177, -16}, {177, -19}}}
+	shifted := orb.Polygon{{{-3, -19}, {1, -19}, {1, -16}, {-3, -16}, {-3, -19}}}
+
+	if a, e := AreaAntimeridian(crossing), Area(shifted); math.Abs(a-e) > 1e-3 {
+		t.Errorf("incorrect area: %v != %v", a, e)
+	}
+
+	// wide polygons not crossing the antimeridian are unchanged by default
+	band := orb.Polygon{{{-100, -10}, {100, -10}, {100, 10}, {-100, 10}, {-100, -10}}}
+	if a, e := Area(band), Area(band.Bound()); math.Abs(a-e) > 1e-3 {
+		t.Errorf("incorrect band area: %v != %v", a, e)
+	}
+
+	// the other way around the world
+	wrapped := orb.Bound{Min: orb.Point{100, -10}, Max: orb.Point{-100, 10}}
+	if a, e := AreaAntimeridian(band), Area(wrapped); math.Abs(a-e) > 1e-3 {
+		t.Errorf("incorrect wrapped band area: %v != %v", a, e)
+	}
+
+	if a, e := AreaGeodesic(crossing), AreaGeodesic(shifted); math.Abs(a-e) > 1e-3 {
+		t.Errorf("incorrect geodesic area: %v != %v", a, e)
+	}
+
+	b := orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{-179, -16}}
+	if a, e := Area(b), Area(shifted.Bound()); math.Abs(a-e) > 1e-3 {
+		t.Errorf("incorrect bound area: %v != %v", a, e)
+	}
+
+	// the whole world
+	world := orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}}
+	e := 4 * math.Pi * orb.EarthRadius * orb.EarthRadius
+	if a := Area(world); math.Abs(a-e) > 1 {
+		t.Errorf("incorrect world area: %v != %v", a, e)
+	}
+
+	if a := Area(world.ToRing()); math.Abs(a-e) > 1 {
+		t.Errorf("incorrect world ring area: %v != %v", a, e)
+	}
+
+	// bound area is the same as the ring
+	b = orb.Bound{Min: orb.Point{-3, -19}, Max: orb.Point{1, -16}}
+	if a, e := Area(b), Area(b.ToRing()); math.Abs(a-e) > 1e-3 {
+		t.Errorf("bound area not same as ring: %v != %v", a, e)
+	}
+}
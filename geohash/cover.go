@@ -0,0 +1,160 @@
+package geohash
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/clip"
+	"github.com/paulmach/orb/planar"
+)
+
+// Cover returns the set of geohashes, with the given number of characters,
+// that cover the geometry. This is analogous to maptile/tilecover.
+// Bounds with Min longitude greater than Max longitude wrap around the
+// antimeridian, for other geometries see CoverAntimeridian.
+func Cover(g orb.Geometry, precision int) Set {
+	return cover(g, precision, false)
+}
+
+// CoverAntimeridian returns the set of geohashes that cover the geometry
+// after splitting it where it crosses the antimeridian, see clip.Antimeridian.
+// Segments where the longitude changes by more than 180 degrees, e.g. from
+// 179 to -179, are taken the shorter way around so they do not cover the
+// whole world.
+func CoverAntimeridian(g orb.Geometry, precision int) Set {
+	return cover(g, precision, true)
+}
+
+func cover(g orb.Geometry, precision int, antimeridian bool) Set {
+	if g == nil {
+		return nil
+	}
+
+	if precision < 1 {
+		precision = 1
+	} else if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	bits := uint(5 * precision)
+	c := &coverer{
+		set:       make(Set),
+		precision: precision,
+		bits:      bits,
+		lonBits:   (bits + 1) / 2,
+		latBits:   bits / 2,
+	}
+
+	if antimeridian {
+		g = clip.Antimeridian(g)
+	}
+	c.geometry(g)
+
+	return c.set
+}
+
+type coverer struct {
+	set       Set
+	precision int
+
+	bits, lonBits, latBits uint
+}
+
+func (c *coverer) geometry(g orb.Geometry) {
+	switch g := g.(type) {
+	case nil:
+	case orb.Point:
+		c.point(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			c.point(p)
+		}
+	case orb.LineString:
+		c.lineString(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			c.lineString(ls)
+		}
+	case orb.Ring:
+		c.polygon(orb.Polygon{g})
+	case orb.Polygon:
+		c.polygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			c.polygon(p)
+		}
+	case orb.Collection:
+		for _, cg := range g {
+			c.geometry(cg)
+		}
+	case orb.Bound:
+		if g.Min[0] > g.Max[0] {
+			// wraps around the antimeridian
+			c.geometry(clip.Antimeridian(g))
+			return
+		}
+
+		c.cells(g, func(orb.Bound) bool { return true })
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (c *coverer) point(p orb.Point) {
+	lon, lat := c.cell(p)
+	c.add(lon, lat)
+}
+
+func (c *coverer) lineString(ls orb.LineString) {
+	if len(ls) == 1 {
+		c.point(ls[0])
+		return
+	}
+
+	for i := 1; i < len(ls); i++ {
+		segment := orb.LineString{ls[i-1], ls[i]}
+		c.cells(segment.Bound(), func(cell orb.Bound) bool {
+			return len(clip.LineString(cell, segment)) > 0
+		})
+	}
+}
+
+func (c *coverer) polygon(p orb.Polygon) {
+	if len(p) == 0 {
+		return
+	}
+
+	for _, r := range p {
+		c.lineString(orb.LineString(r))
+	}
+
+	// cells completely inside the polygon
+	c.cells(p.Bound(), func(cell orb.Bound) bool {
+		return planar.PolygonContains(p, cell.Center())
+	})
+}
+
+// cells adds the cells in the bound that pass the test.
+func (c *coverer) cells(b orb.Bound, test func(orb.Bound) bool) {
+	minLon, minLat := c.cell(b.Min)
+	maxLon, maxLat := c.cell(b.Max)
+
+	for lon := minLon; lon <= maxLon; lon++ {
+		for lat := minLat; lat <= maxLat; lat++ {
+			if test(cellBound(lon, lat, c.lonBits, c.latBits)) {
+				c.add(lon, lat)
+			}
+		}
+	}
+}
+
+func (c *coverer) cell(p orb.Point) (uint32, uint32) {
+	lon := quantize(p[0], -180, 360)
+	lat := quantize(p[1], -90, 180)
+
+	return uint32(uint64(lon) >> (32 - c.lonBits)), uint32(uint64(lat) >> (32 - c.latBits))
+}
+
+func (c *coverer) add(lon, lat uint32) {
+	c.set[toString(fromCell(lon, lat, c.bits), c.precision)] = true
+}
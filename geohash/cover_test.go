@@ -0,0 +1,108 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestCover(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.Geometry
+		expected []string
+	}{
+		{
+			name:     "point",
+			geom:     orb.Point{-5.6, 42.6},
+			expected: []string{"ezs"},
+		},
+		{
+			name:     "multi point",
+			geom:     orb.MultiPoint{{-5.6, 42.6}, {10.40744, 57.64911}},
+			expected: []string{"ezs", "u4p"},
+		},
+		{
+			name:     "bound",
+			geom:     orb.Bound{Min: orb.Point{0.1, 0.1}, Max: orb.Point{1.5, 1.5}},
+			expected: []string{"s00", "s01", "s02", "s03"},
+		},
+		{
+			name:     "line string",
+			geom:     orb.LineString{{0.1, 0.1}, {1.5, 1.2}},
+			expected: []string{"s00", "s01"},
+		},
+		{
+			name: "polygon",
+			geom: orb.Polygon{{{0.1, 0.1}, {4, 0.1}, {4, 4}, {0.1, 4}, {0.1, 0.1}}},
+			expected: []string{
+				"s00", "s01", "s02", "s03", "s04", "s06", "s08", "s09", "s0d",
+			},
+		},
+		{
+			name:     "wrapping bound",
+			geom:     orb.Bound{Min: orb.Point{179, 0.1}, Max: orb.Point{-179, 0.2}},
+			expected: []string{"xbp", "800"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := Cover(tc.geom, 3)
+
+			if len(set) != len(tc.expected) {
+				t.Errorf("incorrect number of hashes: %d != %d: %v", len(set), len(tc.expected), set)
+			}
+
+			for _, h := range tc.expected {
+				if !set[h] {
+					t.Errorf("missing %v", h)
+				}
+			}
+		})
+	}
+}
+
+func TestCoverAntimeridian(t *testing.T) {
+	ls := orb.LineString{{179, 0.1}, {-179, 0.1}}
+
+	set := CoverAntimeridian(ls, 3)
+	if len(set) != 2 || !set["xbp"] || !set["800"] {
+		t.Errorf("incorrect hashes: %v", set)
+	}
+
+	// the line goes around the world by default
+	if set := Cover(ls, 3); len(set) <= 2 {
+		t.Errorf("should cover more hashes: %v", set)
+	}
+}
+
+func TestCover_polygonHole(t *testing.T) {
+	// hole covering the center cells
+	b, _ := DecodeBound("s0d")
+	p := orb.Polygon{
+		{{0.1, 0.1}, {4, 0.1}, {4, 4}, {0.1, 4}, {0.1, 0.1}},
+		b.Pad(-0.1).ToRing(),
+	}
+
+	// the hole is inside the cell so it's still covered
+	if set := Cover(p, 3); !set["s0d"] {
+		t.Errorf("cell with hole should be covered")
+	}
+
+	p[1] = orb.Ring{{1.5, 1.5}, {2.9, 1.5}, {2.9, 2.9}, {1.5, 2.9}, {1.5, 1.5}}
+	set := Cover(p, 4)
+	if set[Encode(orb.Point{2.2, 2.2}, 4)] {
+		t.Errorf("cell in the hole should not be covered")
+	}
+}
+
+func TestCover_allGeometries(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Cover(g, 2)
+	}
+
+	if Cover(nil, 5) != nil {
+		t.Errorf("nil geometry should be nil set")
+	}
+}
@@ -0,0 +1,43 @@
+package geohash_test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geohash"
+)
+
+func ExampleEncode() {
+	fmt.Println(geohash.Encode(orb.Point{-5.6, 42.6}, 5))
+
+	b, _ := geohash.DecodeBound("ezs42")
+	fmt.Println(b.Min, b.Max)
+
+	// Output:
+	// ezs42
+	// [-5.625 42.5830078125] [-5.5810546875 42.626953125]
+}
+
+func ExampleNeighbors() {
+	neighbors, _ := geohash.Neighbors("ezs42")
+	fmt.Println(neighbors)
+
+	// Output:
+	// [ezs48 ezs49 ezs43 ezs41 ezs40 ezefp ezefr ezefx]
+}
+
+func ExampleCover() {
+	set := geohash.Cover(orb.Bound{Min: orb.Point{0.1, 0.1}, Max: orb.Point{1.5, 1.5}}, 3)
+
+	hashes := make([]string, 0, len(set))
+	for h := range set {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+
+	fmt.Println(hashes)
+
+	// Output:
+	// [s00 s01 s02 s03]
+}
@@ -0,0 +1,193 @@
+// Package geohash encodes and decodes points as geohashes, both as the
+// base32 string and as an integer, and computes the geohashes covering
+// an orb.Geometry.
+package geohash
+
+import (
+	"errors"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// MaxPrecision is the max number of characters in a string geohash.
+// This is 60 bits, the max for the integer version is 64.
+const MaxPrecision = 12
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// ErrInvalidHash is returned when decoding a string that is not a geohash.
+var ErrInvalidHash = errors.New("geohash: invalid hash")
+
+var decodeMap [256]byte
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = 0xff
+	}
+
+	for i := 0; i < len(base32); i++ {
+		decodeMap[base32[i]] = byte(i)
+		decodeMap[base32[i]-'a'+'A'] = byte(i)
+	}
+}
+
+// Encode returns the geohash of the point with the given number of characters.
+// The precision is clamped to 1 to MaxPrecision.
+func Encode(p orb.Point, precision int) string {
+	if precision < 1 {
+		precision = 1
+	} else if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	return toString(EncodeInt(p, uint(5*precision)), precision)
+}
+
+// EncodeInt returns the geohash of the point as an integer with the
+// given number of bits. The bits are clamped to 1 to 64.
+func EncodeInt(p orb.Point, bits uint) uint64 {
+	bits = clampBits(bits)
+
+	lon := quantize(p[0], -180, 360)
+	lat := quantize(p[1], -90, 180)
+
+	return interleave(lon, lat) >> (64 - bits)
+}
+
+// Decode returns the center of the geohash cell.
+func Decode(hash string) (orb.Point, error) {
+	b, err := DecodeBound(hash)
+	if err != nil {
+		return orb.Point{}, err
+	}
+
+	return b.Center(), nil
+}
+
+// DecodeBound returns the bound of the geohash cell.
+func DecodeBound(hash string) (orb.Bound, error) {
+	v, bits, err := fromString(hash)
+	if err != nil {
+		return orb.Bound{}, err
+	}
+
+	return DecodeIntBound(v, bits), nil
+}
+
+// DecodeInt returns the center of the integer geohash cell
+// with the given number of bits.
+func DecodeInt(hash uint64, bits uint) orb.Point {
+	return DecodeIntBound(hash, bits).Center()
+}
+
+// DecodeIntBound returns the bound of the integer geohash cell
+// with the given number of bits.
+func DecodeIntBound(hash uint64, bits uint) orb.Bound {
+	bits = clampBits(bits)
+	lon, lat := deinterleave(hash << (64 - bits))
+
+	lonBits, latBits := (bits+1)/2, bits/2
+	lon >>= 32 - lonBits
+	lat >>= 32 - latBits
+
+	return cellBound(lon, lat, lonBits, latBits)
+}
+
+func cellBound(lon, lat uint32, lonBits, latBits uint) orb.Bound {
+	w := 360 / math.Pow(2, float64(lonBits))
+	h := 180 / math.Pow(2, float64(latBits))
+
+	return orb.Bound{
+		Min: orb.Point{-180 + float64(lon)*w, -90 + float64(lat)*h},
+		Max: orb.Point{-180 + float64(lon+1)*w, -90 + float64(lat+1)*h},
+	}
+}
+
+// quantize maps the value into the 32 bit range.
+func quantize(v, min, size float64) uint32 {
+	f := math.Floor((v - min) / size * (1 << 32))
+	if f < 0 {
+		return 0
+	}
+
+	if f >= 1<<32 {
+		return math.MaxUint32
+	}
+
+	return uint32(f)
+}
+
+func clampBits(bits uint) uint {
+	if bits < 1 {
+		return 1
+	}
+
+	if bits > 64 {
+		return 64
+	}
+
+	return bits
+}
+
+func toString(v uint64, precision int) string {
+	buf := make([]byte, precision)
+	for i := precision - 1; i >= 0; i-- {
+		buf[i] = base32[v&0x1f]
+		v >>= 5
+	}
+
+	return string(buf)
+}
+
+func fromString(hash string) (uint64, uint, error) {
+	if len(hash) == 0 || len(hash) > MaxPrecision {
+		return 0, 0, ErrInvalidHash
+	}
+
+	var v uint64
+	for i := 0; i < len(hash); i++ {
+		c := decodeMap[hash[i]]
+		if c == 0xff {
+			return 0, 0, ErrInvalidHash
+		}
+
+		v = v<<5 | uint64(c)
+	}
+
+	return v, uint(5 * len(hash)), nil
+}
+
+// interleave the bits with the x/lon bits in the even positions,
+// starting with the most significant bit.
+func interleave(x, y uint32) uint64 {
+	return spread(x)<<1 | spread(y)
+}
+
+func deinterleave(v uint64) (uint32, uint32) {
+	return squash(v >> 1), squash(v)
+}
+
+// spread puts a zero bit between each bit of the value.
+func spread(x uint32) uint64 {
+	v := uint64(x)
+	v = (v | v<<16) & 0x0000ffff0000ffff
+	v = (v | v<<8) & 0x00ff00ff00ff00ff
+	v = (v | v<<4) & 0x0f0f0f0f0f0f0f0f
+	v = (v | v<<2) & 0x3333333333333333
+	v = (v | v<<1) & 0x5555555555555555
+
+	return v
+}
+
+// squash is the inverse of spread, it removes every other bit.
+func squash(v uint64) uint32 {
+	v &= 0x5555555555555555
+	v = (v | v>>1) & 0x3333333333333333
+	v = (v | v>>2) & 0x0f0f0f0f0f0f0f0f
+	v = (v | v>>4) & 0x00ff00ff00ff00ff
+	v = (v | v>>8) & 0x0000ffff0000ffff
+	v = (v | v>>16) & 0x00000000ffffffff
+
+	return uint32(v)
+}
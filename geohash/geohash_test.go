@@ -0,0 +1,172 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		name      string
+		point     orb.Point
+		precision int
+		expected  string
+	}{
+		{
+			name:      "wikipedia",
+			point:     orb.Point{-5.6, 42.6},
+			precision: 5,
+			expected:  "ezs42",
+		},
+		{
+			name:      "wikipedia long",
+			point:     orb.Point{10.40744, 57.64911},
+			precision: 11,
+			expected:  "u4pruydqqvj",
+		},
+		{
+			name:      "origin",
+			point:     orb.Point{0, 0},
+			precision: 4,
+			expected:  "s000",
+		},
+		{
+			name:      "max corner",
+			point:     orb.Point{180, 90},
+			precision: 6,
+			expected:  "zzzzzz",
+		},
+		{
+			name:      "min corner",
+			point:     orb.Point{-180, -90},
+			precision: 6,
+			expected:  "000000",
+		},
+		{
+			name:      "precision clamped",
+			point:     orb.Point{-5.6, 42.6},
+			precision: 20,
+			expected:  Encode(orb.Point{-5.6, 42.6}, 12),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := Encode(tc.point, tc.precision); v != tc.expected {
+				t.Errorf("incorrect hash: %v != %v", v, tc.expected)
+			}
+
+			b, err := DecodeBound(tc.expected)
+			if err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+
+			if !b.Contains(tc.point) {
+				t.Errorf("bound %v should contain point", b)
+			}
+		})
+	}
+}
+
+func TestEncodeInt(t *testing.T) {
+	p := orb.Point{-5.6, 42.6}
+
+	v, bits, err := fromString("ezs42")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if h := EncodeInt(p, bits); h != v {
+		t.Errorf("incorrect int hash: %v != %v", h, v)
+	}
+
+	// odd number of bits has one more longitude bit
+	b := DecodeIntBound(EncodeInt(p, 3), 3)
+	expected := orb.Bound{Min: orb.Point{-90, 0}, Max: orb.Point{0, 90}}
+	if !b.Equal(expected) {
+		t.Errorf("incorrect bound: %v", b)
+	}
+
+	// 64 bits
+	c := DecodeInt(EncodeInt(p, 64), 64)
+	if math.Abs(c[0]-p[0]) > 1e-7 || math.Abs(c[1]-p[1]) > 1e-7 {
+		t.Errorf("incorrect center: %v", c)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	p, err := Decode("ezs42")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if math.Abs(p[0]+5.603) > 0.001 || math.Abs(p[1]-42.605) > 0.001 {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	b, err := DecodeBound("EZS42")
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	expected := orb.Bound{Min: orb.Point{-5.625, 42.5830078125}, Max: orb.Point{-5.5810546875, 42.626953125}}
+	if !b.Equal(expected) {
+		t.Errorf("incorrect bound: %v", b)
+	}
+
+	for _, h := range []string{"", "ezs4a", "ezs42ezs42ezs", "ezs 4"} {
+		if _, err := Decode(h); err != ErrInvalidHash {
+			t.Errorf("expected error for %q: %v", h, err)
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	for _, hash := range []string{"ezs42", "u4pruydqqvj", "s", "9q8yyk8"} {
+		b, _ := DecodeBound(hash)
+		w, h := b.Max[0]-b.Min[0], b.Max[1]-b.Min[1]
+
+		neighbors, err := Neighbors(hash)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+
+		for d, n := range neighbors {
+			o := offsets[d]
+			c := b.Center()
+			expected := Encode(orb.Point{c[0] + float64(o[0])*w, c[1] + float64(o[1])*h}, len(hash))
+
+			if n != expected {
+				t.Errorf("%v: incorrect neighbor %d: %v != %v", hash, d, n, expected)
+			}
+		}
+	}
+}
+
+func TestNeighbor_edges(t *testing.T) {
+	// wraps around the antimeridian
+	n, err := Neighbor(Encode(orb.Point{179.99, 0}, 5), East)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if expected := Encode(orb.Point{-179.99, 0}, 5); n != expected {
+		t.Errorf("incorrect east neighbor: %v != %v", n, expected)
+	}
+
+	// nothing past the pole
+	n, err = Neighbor(Encode(orb.Point{10, 89.99}, 5), NorthWest)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if n != "" {
+		t.Errorf("should not have neighbor: %v", n)
+	}
+
+	if _, err := Neighbors("a"); err != ErrInvalidHash {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
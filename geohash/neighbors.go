@@ -0,0 +1,96 @@
+package geohash
+
+// Direction is used to find the neighbor of a geohash cell.
+type Direction int
+
+// The directions of the neighboring cells.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+var offsets = [...][2]int64{
+	North:     {0, 1},
+	NorthEast: {1, 1},
+	East:      {1, 0},
+	SouthEast: {1, -1},
+	South:     {0, -1},
+	SouthWest: {-1, -1},
+	West:      {-1, 0},
+	NorthWest: {-1, 1},
+}
+
+// Neighbor returns the adjacent geohash, of the same precision, in the
+// given direction. Cells wrap around the antimeridian. There are no cells
+// past the poles so an empty string is returned for those.
+func Neighbor(hash string, d Direction) (string, error) {
+	v, bits, err := fromString(hash)
+	if err != nil {
+		return "", err
+	}
+
+	n, ok := NeighborInt(v, bits, d)
+	if !ok {
+		return "", nil
+	}
+
+	return toString(n, len(hash)), nil
+}
+
+// Neighbors returns the 8 adjacent geohashes in the order
+// North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest.
+// Cells past the poles are returned as empty strings.
+func Neighbors(hash string) ([]string, error) {
+	result := make([]string, 0, len(offsets))
+	for d := range offsets {
+		n, err := Neighbor(hash, Direction(d))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, n)
+	}
+
+	return result, nil
+}
+
+// NeighborInt returns the adjacent integer geohash, with the given number of
+// bits, in the given direction. It returns false if the neighbor would be
+// past a pole.
+func NeighborInt(hash uint64, bits uint, d Direction) (uint64, bool) {
+	bits = clampBits(bits)
+	lon, lat := deinterleave(hash << (64 - bits))
+
+	lonBits, latBits := (bits+1)/2, bits/2
+	lon >>= 32 - lonBits
+	lat >>= 32 - latBits
+
+	o := offsets[d]
+
+	// longitude wraps around the antimeridian
+	lonMask := uint64(1)<<lonBits - 1
+	nlon := uint64(int64(lon)+o[0]) & lonMask
+
+	nlat := int64(lat) + o[1]
+	if nlat < 0 || nlat >= int64(1)<<latBits {
+		return 0, false
+	}
+
+	return fromCell(uint32(nlon), uint32(nlat), bits), true
+}
+
+// fromCell returns the geohash of the cell with the given lon and lat index.
+func fromCell(lon, lat uint32, bits uint) uint64 {
+	lonBits, latBits := (bits+1)/2, bits/2
+
+	x := uint32(uint64(lon) << (32 - lonBits))
+	y := uint32(uint64(lat) << (32 - latBits))
+
+	return interleave(x, y) >> (64 - bits)
+}
@@ -0,0 +1,36 @@
+package geohash
+
+import (
+	"github.com/paulmach/orb/geojson"
+)
+
+// Set is a map/hash of geohashes.
+type Set map[string]bool
+
+// ToFeatureCollection converts a set of geohashes into a feature collection
+// of their cells. This method is mostly useful for debugging output.
+func (s Set) ToFeatureCollection() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	fc.Features = make([]*geojson.Feature, 0, len(s))
+	for h := range s {
+		b, err := DecodeBound(h)
+		if err != nil {
+			continue
+		}
+
+		f := geojson.NewFeature(b.ToPolygon())
+		f.Properties["geohash"] = h
+		fc.Append(f)
+	}
+
+	return fc
+}
+
+// Merge will merge the given set into the existing set.
+func (s Set) Merge(set Set) {
+	for h, v := range set {
+		if v {
+			s[h] = true
+		}
+	}
+}
@@ -0,0 +1,611 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/paulmach/orb"
+)
+
+// ErrInvalidCoordinates will be returned if the nesting of the coordinates
+// does not match the type of the geometry or the positions are not numbers.
+var ErrInvalidCoordinates = errors.New("geojson: invalid coordinates")
+
+// unmarshalCoordinates decodes the coordinates json for the geometry type
+// in one pass, without reflection. The z and m values, the third and fourth
+// values of the positions, are returned with the layout. If some positions
+// have fewer values than others the missing ones are set to NaN.
+func unmarshalCoordinates(typ string, data []byte) (orb.Geometry, orb.Layout, []float64, error) {
+	s := &coordinateScanner{data: data}
+
+	var (
+		g   orb.Geometry
+		err error
+	)
+	switch typ {
+	case "Point":
+		var p orb.Point
+		p, err = s.position()
+		g = p
+	case "MultiPoint":
+		var ps []orb.Point
+		ps, err = s.positions()
+		g = orb.MultiPoint(ps)
+	case "LineString":
+		var ps []orb.Point
+		ps, err = s.positions()
+		g = orb.LineString(ps)
+	case "MultiLineString":
+		var mls orb.MultiLineString
+		mls, err = s.multiLineString()
+		g = mls
+	case "Polygon":
+		var p orb.Polygon
+		p, err = s.polygon()
+		g = p
+	case "MultiPolygon":
+		var mp orb.MultiPolygon
+		mp, err = s.multiPolygon()
+		g = mp
+	default:
+		return nil, orb.XY, nil, ErrInvalidGeometry
+	}
+
+	if err != nil {
+		return nil, orb.XY, nil, err
+	}
+
+	s.skipSpace()
+	if s.pos != len(s.data) {
+		return nil, orb.XY, nil, ErrInvalidCoordinates
+	}
+
+	l, extra := s.result()
+	return g, l, extra, nil
+}
+
+// coordinateScanner reads nested arrays of numbers. The input has already
+// been through the json decoder, so it only needs to check that the nesting
+// is what the geometry type expects.
+type coordinateScanner struct {
+	data []byte
+	pos  int
+
+	// count is the number of positions read. The extra values are stored
+	// as z, m pairs, but only once a position with more than 2 values
+	// has been found. extraN is the max number of extra values seen.
+	count  int
+	extraN int
+	extra  []float64
+}
+
+// result returns the layout and extra values of all the positions.
+func (s *coordinateScanner) result() (orb.Layout, []float64) {
+	switch s.extraN {
+	case 0:
+		return orb.XY, nil
+	case 1:
+		// compact the pairs down to just the z values.
+		for i := 0; i < s.count; i++ {
+			s.extra[i] = s.extra[2*i]
+		}
+		return orb.XYZ, s.extra[:s.count]
+	}
+
+	return orb.XYZM, s.extra
+}
+
+func (s *coordinateScanner) multiPolygon() (orb.MultiPolygon, error) {
+	if s.null() {
+		return nil, nil
+	}
+
+	mp := orb.MultiPolygon{}
+	err := s.array(func() error {
+		p, err := s.polygon()
+		mp = append(mp, p)
+		return err
+	})
+
+	return mp, err
+}
+
+func (s *coordinateScanner) polygon() (orb.Polygon, error) {
+	if s.null() {
+		return nil, nil
+	}
+
+	p := orb.Polygon{}
+	err := s.array(func() error {
+		ps, err := s.positions()
+		p = append(p, orb.Ring(ps))
+		return err
+	})
+
+	return p, err
+}
+
+func (s *coordinateScanner) multiLineString() (orb.MultiLineString, error) {
+	if s.null() {
+		return nil, nil
+	}
+
+	mls := orb.MultiLineString{}
+	err := s.array(func() error {
+		ps, err := s.positions()
+		mls = append(mls, orb.LineString(ps))
+		return err
+	})
+
+	return mls, err
+}
+
+func (s *coordinateScanner) positions() ([]orb.Point, error) {
+	if s.null() {
+		return nil, nil
+	}
+
+	ps := []orb.Point{}
+	err := s.array(func() error {
+		p, err := s.position()
+		ps = append(ps, p)
+		return err
+	})
+
+	return ps, err
+}
+
+// position reads a [x, y, z, m] array. Null or missing x and y values
+// are left as zero, like the standard library would, missing extra
+// values are NaN. Anything past the fourth value is ignored.
+func (s *coordinateScanner) position() (orb.Point, error) {
+	var p orb.Point
+	if s.null() {
+		s.addExtra(0, math.NaN(), math.NaN())
+		return p, nil
+	}
+
+	z, m := math.NaN(), math.NaN()
+	i := 0
+	err := s.array(func() error {
+		if s.null() {
+			i++
+			return nil
+		}
+
+		v, err := s.number()
+		if err != nil {
+			return err
+		}
+
+		switch i {
+		case 0, 1:
+			p[i] = v
+		case 2:
+			z = v
+		case 3:
+			m = v
+		}
+		i++
+
+		return nil
+	})
+	if err != nil {
+		return p, err
+	}
+
+	n := i - 2
+	if n < 0 {
+		n = 0
+	} else if n > 2 {
+		n = 2
+	}
+	s.addExtra(n, z, m)
+
+	return p, nil
+}
+
+func (s *coordinateScanner) addExtra(n int, z, m float64) {
+	if n > s.extraN {
+		if s.extra == nil {
+			// first position with extra values, backfill the previous ones.
+			s.extra = make([]float64, 2*s.count, 2*s.count+16)
+			for i := range s.extra {
+				s.extra[i] = math.NaN()
+			}
+		}
+		s.extraN = n
+	}
+
+	if s.extra != nil {
+		s.extra = append(s.extra, z, m)
+	}
+	s.count++
+}
+
+// array reads the brackets and commas of an array calling the
+// value function for each element.
+func (s *coordinateScanner) array(value func() error) error {
+	s.skipSpace()
+	if s.pos >= len(s.data) || s.data[s.pos] != '[' {
+		return ErrInvalidCoordinates
+	}
+	s.pos++
+
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == ']' {
+		s.pos++
+		return nil
+	}
+
+	for {
+		if err := value(); err != nil {
+			return err
+		}
+
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return ErrInvalidCoordinates
+		}
+
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+		case ']':
+			s.pos++
+			return nil
+		default:
+			return ErrInvalidCoordinates
+		}
+	}
+}
+
+// null will consume a null value if it is next.
+func (s *coordinateScanner) null() bool {
+	s.skipSpace()
+	if len(s.data)-s.pos >= 4 && string(s.data[s.pos:s.pos+4]) == "null" {
+		s.pos += 4
+		return true
+	}
+
+	return false
+}
+
+func (s *coordinateScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// float64pow10 are the powers of 10 that can be exactly represented.
+var float64pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
+}
+
+// number reads a json number. Most coordinates have less than 16
+// significant digits and can be converted exactly with a single
+// division, the rest fall back to strconv.
+func (s *coordinateScanner) number() (float64, error) {
+	start := s.pos
+
+	neg := false
+	if s.pos < len(s.data) && s.data[s.pos] == '-' {
+		neg = true
+		s.pos++
+	}
+
+	var (
+		mantissa uint64
+		digits   int
+		exp      int
+		simple   = true
+	)
+	for ; s.pos < len(s.data); s.pos++ {
+		c := s.data[s.pos]
+		if c < '0' || c > '9' {
+			break
+		}
+		mantissa = mantissa*10 + uint64(c-'0')
+		digits++
+	}
+
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		s.pos++
+		for ; s.pos < len(s.data); s.pos++ {
+			c := s.data[s.pos]
+			if c < '0' || c > '9' {
+				break
+			}
+			mantissa = mantissa*10 + uint64(c-'0')
+			digits++
+			exp--
+		}
+	}
+
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		// exponents are rare in coordinates, let strconv handle them.
+		simple = false
+		s.pos++
+		for ; s.pos < len(s.data); s.pos++ {
+			c := s.data[s.pos]
+			if (c < '0' || c > '9') && c != '+' && c != '-' {
+				break
+			}
+		}
+	}
+
+	if digits == 0 {
+		return 0, ErrInvalidCoordinates
+	}
+
+	// trailing zeros can make the mantissa too large for the fast
+	// path even if the value is exact, e.g. 1.50000000000000000
+	if simple && digits > 15 {
+		simple = false
+	}
+
+	if simple && mantissa < 1<<53 && exp >= -22 {
+		f := float64(mantissa) / float64pow10[-exp]
+		if neg {
+			f = -f
+		}
+		return f, nil
+	}
+
+	f, err := strconv.ParseFloat(string(s.data[start:s.pos]), 64)
+	if err != nil {
+		return 0, ErrInvalidCoordinates
+	}
+
+	return f, nil
+}
+
+// coordinateEncoder writes the coordinates of a geometry as json
+// without reflection. If the layout is not XY the z and/or m values
+// will be written as the third and fourth values of the positions.
+type coordinateEncoder struct {
+	layout orb.Layout
+	extra  []float64
+	index  int
+
+	rightHand bool
+	factor    float64
+}
+
+func newCoordinateEncoder(l orb.Layout, extra []float64, o *encodeOptions) *coordinateEncoder {
+	e := &coordinateEncoder{layout: l, extra: extra}
+	if o != nil {
+		e.rightHand = o.rightHand
+		e.factor = o.factor
+	}
+
+	return e
+}
+
+func (e *coordinateEncoder) coordinates(buf []byte, g orb.Geometry) ([]byte, error) {
+	switch g := g.(type) {
+	case orb.Point:
+		return e.position(buf, g)
+	case orb.MultiPoint:
+		return e.positions(buf, g)
+	case orb.LineString:
+		return e.positions(buf, g)
+	case orb.MultiLineString:
+		if g == nil {
+			return append(buf, "null"...), nil
+		}
+
+		buf = append(buf, '[')
+		for i, ls := range g {
+			if i != 0 {
+				buf = append(buf, ',')
+			}
+
+			var err error
+			buf, err = e.positions(buf, ls)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case orb.Polygon:
+		return e.polygon(buf, g)
+	case orb.MultiPolygon:
+		if g == nil {
+			return append(buf, "null"...), nil
+		}
+
+		buf = append(buf, '[')
+		for i, p := range g {
+			if i != 0 {
+				buf = append(buf, ',')
+			}
+
+			var err error
+			buf, err = e.polygon(buf, p)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func (e *coordinateEncoder) polygon(buf []byte, p orb.Polygon) ([]byte, error) {
+	if p == nil {
+		return append(buf, "null"...), nil
+	}
+
+	buf = append(buf, '[')
+	for i, r := range p {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+
+		// exterior rings are counterclockwise, holes are clockwise.
+		reverse := false
+		if e.rightHand && len(r) > 2 {
+			o := r.Orientation()
+			reverse = (i == 0 && o == orb.CW) || (i != 0 && o == orb.CCW)
+		}
+
+		var err error
+		if reverse {
+			buf, err = e.reversed(buf, r)
+		} else {
+			buf, err = e.positions(buf, r)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+func (e *coordinateEncoder) positions(buf []byte, ps []orb.Point) ([]byte, error) {
+	if ps == nil {
+		return append(buf, "null"...), nil
+	}
+
+	buf = append(buf, '[')
+	for i, p := range ps {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+		buf, err = e.position(buf, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+// reversed writes the positions in reverse order along with their extra values.
+func (e *coordinateEncoder) reversed(buf []byte, ps []orb.Point) ([]byte, error) {
+	start := e.index
+
+	buf = append(buf, '[')
+	for i := len(ps) - 1; i >= 0; i-- {
+		if i != len(ps)-1 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+		e.index = start + i
+		buf, err = e.position(buf, ps[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.index = start + len(ps)
+
+	return append(buf, ']'), nil
+}
+
+func (e *coordinateEncoder) position(buf []byte, p orb.Point) ([]byte, error) {
+	var err error
+
+	buf = append(buf, '[')
+	if buf, err = e.float(buf, p[0]); err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, ',')
+	if buf, err = e.float(buf, p[1]); err != nil {
+		return nil, err
+	}
+
+	if e.layout == orb.XY {
+		return append(buf, ']'), nil
+	}
+
+	stride := e.layout.Stride() - 2
+	if len(e.extra) < (e.index+1)*stride {
+		return nil, ErrInvalidZM
+	}
+
+	extra := e.extra[e.index*stride : (e.index+1)*stride]
+	e.index++
+
+	if e.layout == orb.XYM {
+		// geojson has no way to mark a measure, so it's the fourth value.
+		buf = append(buf, ",null"...)
+	}
+
+	for _, v := range extra {
+		buf = append(buf, ',')
+		if math.IsNaN(v) {
+			buf = append(buf, "null"...)
+			continue
+		}
+
+		if buf, err = e.float(buf, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(buf, ']'), nil
+}
+
+// float writes the value, rounded if there is a precision set.
+func (e *coordinateEncoder) float(buf []byte, f float64) ([]byte, error) {
+	if e.factor != 0 {
+		f = math.Round(f*e.factor) / e.factor
+	}
+
+	return appendFloat(buf, f)
+}
+
+// bufferSize estimates the size of the json of the coordinates
+// so the buffer doesn't need to grow while encoding.
+func bufferSize(g orb.Geometry) int {
+	switch g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString,
+		orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound:
+		return 64 + 40*orb.PointCount(g)
+	}
+
+	return 64
+}
+
+// appendFloat writes the float the same way the encoding/json package does.
+func appendFloat(buf []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, &json.UnsupportedValueError{
+			Value: reflect.ValueOf(f),
+			Str:   strconv.FormatFloat(f, 'g', -1, 64),
+		}
+	}
+
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+
+	return buf, nil
+}
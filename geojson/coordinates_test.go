@@ -0,0 +1,405 @@
+package geojson
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestUnmarshalCoordinates(t *testing.T) {
+	nan := math.NaN()
+	cases := []struct {
+		name   string
+		typ    string
+		data   string
+		geom   orb.Geometry
+		layout orb.Layout
+		extra  []float64
+	}{
+		{
+			name: "point",
+			typ:  "Point",
+			data: `[1.5,-2.25]`,
+			geom: orb.Point{1.5, -2.25},
+		},
+		{
+			name: "whitespace",
+			typ:  "LineString",
+			data: " [ [1 , 2] ,\n\t[3,4]\r\n] ",
+			geom: orb.LineString{{1, 2}, {3, 4}},
+		},
+		{
+			name: "exponents",
+			typ:  "MultiPoint",
+			data: `[[1e2,-2.5E-3],[1.5e+1,0]]`,
+			geom: orb.MultiPoint{{100, -0.0025}, {15, 0}},
+		},
+		{
+			name: "many digits",
+			typ:  "Point",
+			data: `[-122.41941550000000001,37.774929512345678]`,
+			geom: orb.Point{-122.41941550000000001, 37.774929512345678},
+		},
+		{
+			name: "empty",
+			typ:  "LineString",
+			data: `[]`,
+			geom: orb.LineString{},
+		},
+		{
+			name: "null",
+			typ:  "Polygon",
+			data: `null`,
+			geom: orb.Polygon(nil),
+		},
+		{
+			name: "multi line string",
+			typ:  "MultiLineString",
+			data: `[[[1,2],[3,4]],[]]`,
+			geom: orb.MultiLineString{{{1, 2}, {3, 4}}, {}},
+		},
+		{
+			name: "multi polygon",
+			typ:  "MultiPolygon",
+			data: `[[[[0,0],[1,0],[1,1],[0,0]]],[[[2,2],[3,2],[3,3],[2,2]]]]`,
+			geom: orb.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				{{{2, 2}, {3, 2}, {3, 3}, {2, 2}}},
+			},
+		},
+		{
+			name:   "some z values",
+			typ:    "LineString",
+			data:   `[[1,2],[3,4,5],[6,7,null]]`,
+			geom:   orb.LineString{{1, 2}, {3, 4}, {6, 7}},
+			layout: orb.XYZ,
+			extra:  []float64{nan, 5, nan},
+		},
+		{
+			name:   "extra values ignored",
+			typ:    "Point",
+			data:   `[1,2,3,4,5]`,
+			geom:   orb.Point{1, 2},
+			layout: orb.XYZM,
+			extra:  []float64{3, 4},
+		},
+		{
+			name:   "mixed z and m",
+			typ:    "Polygon",
+			data:   `[[[0,0,1],[1,0,2,3],[0,0]]]`,
+			geom:   orb.Polygon{{{0, 0}, {1, 0}, {0, 0}}},
+			layout: orb.XYZM,
+			extra:  []float64{1, nan, 2, 3, nan, nan},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, l, extra, err := unmarshalCoordinates(tc.typ, []byte(tc.data))
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if !reflect.DeepEqual(g, tc.geom) {
+				t.Errorf("incorrect geometry: %v != %v", g, tc.geom)
+			}
+
+			if l != tc.layout {
+				t.Errorf("incorrect layout: %v != %v", l, tc.layout)
+			}
+
+			if !equalFloats(extra, tc.extra) {
+				t.Errorf("incorrect extra: %v != %v", extra, tc.extra)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCoordinates_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		typ  string
+		data string
+		err  error
+	}{
+		{
+			name: "unknown type",
+			typ:  "Arc",
+			data: `[1,2]`,
+			err:  ErrInvalidGeometry,
+		},
+		{
+			name: "missing",
+			typ:  "Point",
+			data: ``,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "too shallow",
+			typ:  "LineString",
+			data: `[1,2]`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "too deep",
+			typ:  "Point",
+			data: `[[1,2]]`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "string",
+			typ:  "Point",
+			data: `["1",2]`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "truncated",
+			typ:  "LineString",
+			data: `[[1,2],[3,`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "trailing data",
+			typ:  "Point",
+			data: `[1,2]]`,
+			err:  ErrInvalidCoordinates,
+		},
+		{
+			name: "out of range",
+			typ:  "Point",
+			data: `[1e400,2]`,
+			err:  ErrInvalidCoordinates,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := unmarshalCoordinates(tc.typ, []byte(tc.data))
+			if err != tc.err {
+				t.Errorf("incorrect error: %v != %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestCoordinateScanner_number(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	values := []string{
+		"0", "-0", "1", "-1", "0.1", "0.3", "123456789012345",
+		"1234567890123456789", "12345678901234567890123",
+		"0.000000000000000000001", "4.9406564584124654e-324",
+		"1.7976931348623157e308", "2.2250738585072014E-308",
+	}
+	for i := 0; i < 10000; i++ {
+		f := (r.Float64() - 0.5) * 360
+		values = append(values,
+			strconv.FormatFloat(f, 'f', -1, 64),
+			strconv.FormatFloat(f, 'f', r.Intn(17), 64),
+		)
+	}
+
+	for _, v := range values {
+		s := &coordinateScanner{data: []byte(v)}
+		f, err := s.number()
+		if err != nil {
+			t.Fatalf("number error for %s: %v", v, err)
+		}
+
+		expected, _ := strconv.ParseFloat(v, 64)
+		if f != expected || math.Signbit(f) != math.Signbit(expected) {
+			t.Errorf("incorrect value for %s: %v != %v", v, f, expected)
+		}
+	}
+}
+
+func TestGeometry_MarshalJSON_stdlib(t *testing.T) {
+	// the hand written encoder should write the same json
+	// as the standard library does using reflection.
+	r := rand.New(rand.NewSource(42))
+	point := func() orb.Point {
+		return orb.Point{
+			(r.Float64() - 0.5) * math.Pow(10, float64(r.Intn(50)-25)),
+			(r.Float64() - 0.5) * 180,
+		}
+	}
+
+	ls := orb.LineString{{0, 0}, {math.Copysign(0, -1), 1e21}, {1e-7, 123456789e15}}
+	for i := 0; i < 1000; i++ {
+		ls = append(ls, point())
+	}
+
+	geoms := []orb.Geometry{
+		orb.Point{1.5, 2.5},
+		orb.MultiPoint(ls),
+		ls,
+		orb.LineString(nil),
+		orb.MultiLineString{ls, nil, {}},
+		orb.Polygon{orb.Ring(ls), nil},
+		orb.MultiPolygon{{orb.Ring(ls)}, nil, {}},
+	}
+
+	for _, g := range geoms {
+		t.Run(g.GeoJSONType(), func(t *testing.T) {
+			data, err := NewGeometry(g).MarshalJSON()
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			expected, err := json.Marshal(stdlibGeometry{
+				Type:        g.GeoJSONType(),
+				Coordinates: g,
+			})
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if string(data) != string(expected) {
+				t.Errorf("incorrect json")
+				t.Logf("%v", string(data))
+				t.Logf("%v", string(expected))
+			}
+		})
+	}
+}
+
+func TestGeometry_MarshalJSON_nan(t *testing.T) {
+	_, err := NewGeometry(orb.LineString{{1, 2}, {math.NaN(), 3}}).MarshalJSON()
+	if _, ok := err.(*json.UnsupportedValueError); !ok {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	_, err = NewGeometry(orb.Point{math.Inf(1), 3}).MarshalJSON()
+	if _, ok := err.(*json.UnsupportedValueError); !ok {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestCoordinateEncoder_extra(t *testing.T) {
+	// the encoder should not rely on the geometry being checked first.
+	ls := orb.LineString{{1, 2}, {3, 4}}
+
+	e := newCoordinateEncoder(orb.XYZM, []float64{1, 2, 3}, nil)
+	if _, err := e.coordinates(nil, ls); err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	e = newCoordinateEncoder(orb.XYZ, nil, nil)
+	if _, err := e.coordinates(nil, ls); err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	e = newCoordinateEncoder(orb.XYZM, []float64{1, 2, 3, 4}, nil)
+	data, err := e.coordinates(nil, ls)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	if string(data) != `[[1,2,1,2],[3,4,3,4]]` {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
+// stdlibGeometry is marshalled by the encoding/json package using reflection.
+type stdlibGeometry struct {
+	Type        string       `json:"type"`
+	Coordinates orb.Geometry `json:"coordinates,omitempty"`
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if math.IsNaN(a[i]) && math.IsNaN(b[i]) {
+			continue
+		}
+
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func BenchmarkCoordinates_marshal(b *testing.B) {
+	g := benchmarkPolygon(10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := NewGeometry(g).MarshalJSON()
+		if err != nil {
+			b.Fatalf("marshal error: %v", err)
+		}
+	}
+}
+
+func BenchmarkCoordinates_marshalStdlib(b *testing.B) {
+	g := benchmarkPolygon(10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := json.Marshal(stdlibGeometry{Type: g.GeoJSONType(), Coordinates: g})
+		if err != nil {
+			b.Fatalf("marshal error: %v", err)
+		}
+	}
+}
+
+func BenchmarkCoordinates_unmarshal(b *testing.B) {
+	data, err := json.Marshal(benchmarkPolygon(10000))
+	if err != nil {
+		b.Fatalf("marshal error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, err := unmarshalCoordinates("Polygon", data)
+		if err != nil {
+			b.Fatalf("unmarshal error: %v", err)
+		}
+	}
+}
+
+func BenchmarkCoordinates_unmarshalStdlib(b *testing.B) {
+	data, err := json.Marshal(benchmarkPolygon(10000))
+	if err != nil {
+		b.Fatalf("marshal error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var p orb.Polygon
+		err := json.Unmarshal(data, &p)
+		if err != nil {
+			b.Fatalf("unmarshal error: %v", err)
+		}
+	}
+}
+
+func benchmarkPolygon(n int) orb.Polygon {
+	r := make(orb.Ring, 0, n)
+	for i := 0; i < n-1; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		r = append(r, orb.Point{
+			-122.4194155 + 0.1*math.Cos(a),
+			37.7749295 + 0.1*math.Sin(a),
+		})
+	}
+
+	// 7 decimal places is about 1cm, typical of real data.
+	return orb.Round(orb.Polygon{append(r, r[0])}, 1e7).(orb.Polygon)
+}
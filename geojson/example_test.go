@@ -182,3 +182,32 @@ func ExampleFeatureCollection_MarshalJSON() {
 	//  "type": "FeatureCollection"
 	// }
 }
+
+func ExampleProperties_Decode() {
+	f, err := geojson.UnmarshalFeature([]byte(`{
+		"type": "Feature",
+		"geometry": {"type": "Point", "coordinates": [1, 2]},
+		"properties": {"name": "Main St", "lanes": 2}
+	}`))
+	if err != nil {
+		log.Fatalf("unmarshal error: %v", err)
+	}
+
+	var road struct {
+		Name  string `json:"name"`
+		Lanes int    `json:"lanes"`
+	}
+	err = f.Properties.Decode(&road)
+	if err != nil {
+		log.Fatalf("decode error: %v", err)
+	}
+
+	fmt.Printf("%s has %d lanes\n", road.Name, road.Lanes)
+
+	_, err = f.Properties.Int("speed")
+	fmt.Println(err)
+
+	// Output:
+	// Main St has 2 lanes
+	// geojson: property not found
+}
@@ -1,7 +1,6 @@
 package geojson
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/paulmach/orb"
@@ -14,6 +13,23 @@ type Feature struct {
 	BBox       BBox         `json:"bbox,omitempty"`
 	Geometry   orb.Geometry `json:"geometry"`
 	Properties Properties   `json:"properties"`
+
+	// Layout and Extra are the z and/or m values of the geometry.
+	// See orb.GeometryZM and NewFeatureZM. If the geometry is changed
+	// these must be updated to match, marshalling returns ErrInvalidZM
+	// if they do not.
+	Layout orb.Layout `json:"-"`
+	Extra  []float64  `json:"-"`
+
+	// ExtraMembers can be used to encoded/decode extra key/members in
+	// the base of the feature, e.g. "title" or "crs". Note that keys of "id",
+	// "type", "bbox", "geometry" and "properties" will not work as those are
+	// reserved by the GeoJSON spec.
+	ExtraMembers Properties `json:"-"`
+
+	// GeometryExtraMembers are the extra members of the geometry object,
+	// they are written back when the feature is marshalled.
+	GeometryExtraMembers Properties `json:"-"`
 }
 
 // NewFeature creates and initializes a GeoJSON feature given the required attributes.
@@ -25,6 +41,25 @@ func NewFeature(geometry orb.Geometry) *Feature {
 	}
 }
 
+// NewFeatureZM creates and initializes a GeoJSON feature with a geometry
+// that has z and/or m values.
+func NewFeatureZM(geometry orb.GeometryZM) *Feature {
+	f := NewFeature(geometry.Geometry)
+	f.Layout = geometry.Layout
+	f.Extra = geometry.Extra
+
+	return f
+}
+
+// GeometryZM returns the geometry of the feature with its z and/or m values.
+func (f *Feature) GeometryZM() orb.GeometryZM {
+	return orb.GeometryZM{
+		Geometry: f.Geometry,
+		Layout:   f.Layout,
+		Extra:    f.Extra,
+	}
+}
+
 // Point implements the orb.Pointer interface so that Features can be used
 // with quadtrees. The point returned is the center of the Bound of the geometry.
 // To represent the geometry with another point you must create a wrapper type.
@@ -38,24 +73,93 @@ var _ orb.Pointer = &Feature{}
 // It will handle the encoding of all the child geometries.
 // Alternately one can call json.Marshal(f) directly for the same result.
 func (f Feature) MarshalJSON() ([]byte, error) {
-	jf := &jsonFeature{
-		ID:         f.ID,
-		Type:       "Feature",
-		Properties: f.Properties,
-		BBox:       f.BBox,
-		Geometry:   NewGeometry(f.Geometry),
+	return f.appendJSON(make([]byte, 0, 128+bufferSize(f.Geometry)), nil)
+}
+
+// appendJSON writes the feature json with the geometry written
+// directly to the buffer.
+func (f *Feature) appendJSON(buf []byte, o *encodeOptions) ([]byte, error) {
+	buf = append(buf, '{')
+	if f.ID != nil {
+		id, err := marshalJSON(f.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, `"id":`...)
+		buf = append(buf, id...)
+		buf = append(buf, ',')
+	}
+
+	buf = append(buf, `"type":"Feature"`...)
+	if len(f.BBox) != 0 {
+		bbox, err := marshalJSON(f.BBox)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, `,"bbox":`...)
+		buf = append(buf, bbox...)
+	}
+
+	zm := f.GeometryZM()
+	if zm.Layout != orb.XY && !validZM(zm) {
+		return nil, ErrInvalidZM
+	}
+
+	var err error
+	buf = append(buf, `,"geometry":`...)
+	jg := NewGeometryZM(zm)
+	jg.ExtraMembers = f.GeometryExtraMembers
+
+	buf, err = jg.appendJSON(buf, o)
+	if err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, `,"properties":`...)
+	if len(f.Properties) == 0 {
+		buf = append(buf, "null"...)
+	} else {
+		props, err := marshalJSON(f.Properties)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, props...)
 	}
 
-	if len(jf.Properties) == 0 {
-		jf.Properties = nil
+	buf, err = appendMembers(buf, f.ExtraMembers, featureMembers)
+	if err != nil {
+		return nil, err
 	}
 
-	return json.Marshal(jf)
+	return append(buf, '}'), nil
+}
+
+// MarshalFeature converts the feature into the proper JSON, like MarshalJSON,
+// with the encode options applied to the geometry.
+func MarshalFeature(f *Feature, opts ...EncodeOption) ([]byte, error) {
+	return marshalFeature(f, newEncodeOptions(opts))
+}
+
+func marshalFeature(f *Feature, o *encodeOptions) ([]byte, error) {
+	if f == nil {
+		return []byte(`null`), nil
+	}
+
+	return f.appendJSON(make([]byte, 0, 128+bufferSize(f.Geometry)), o)
 }
 
 // UnmarshalFeature decodes the data into a GeoJSON feature.
 // Alternately one can call json.Unmarshal(f) directly for the same result.
-func UnmarshalFeature(data []byte) (*Feature, error) {
+// With the Strict option the data is validated against RFC 7946 first.
+func UnmarshalFeature(data []byte, opts ...DecodeOption) (*Feature, error) {
+	if newDecodeOptions(opts).strict {
+		if err := validateStrict(data, "Feature", ""); err != nil {
+			return nil, err
+		}
+	}
+
 	f := &Feature{}
 	err := f.UnmarshalJSON(data)
 	if err != nil {
@@ -66,10 +170,11 @@ func UnmarshalFeature(data []byte) (*Feature, error) {
 }
 
 // UnmarshalJSON handles the correct unmarshalling of the data
-// into the orb.Geometry types.
+// into the orb.Geometry types. Extra/foreign members will be put
+// into the `ExtraMembers` attribute.
 func (f *Feature) UnmarshalJSON(data []byte) error {
 	jf := &jsonFeature{}
-	err := json.Unmarshal(data, &jf)
+	err := unmarshalJSON(data, jf)
 	if err != nil {
 		return err
 	}
@@ -78,29 +183,40 @@ func (f *Feature) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("geojson: not a feature: type=%s", jf.Type)
 	}
 
-	var g orb.Geometry
-	if jf.Geometry != nil {
-		if jf.Geometry.Coordinates == nil && jf.Geometry.Geometries == nil {
-			return ErrInvalidGeometry
-		}
-		g = jf.Geometry.Geometry()
-	}
-
 	*f = Feature{
 		ID:         jf.ID,
 		Type:       jf.Type,
-		Properties: jf.Properties,
 		BBox:       jf.BBox,
-		Geometry:   g,
+		Properties: jf.Properties,
+	}
+
+	if jg := jf.Geometry; jg != nil {
+		if jg.Coordinates == nil && jg.Geometries == nil {
+			return ErrInvalidGeometry
+		}
+
+		g := jg.GeometryZM()
+		f.Geometry, f.Layout, f.Extra = g.Geometry, g.Layout, g.Extra
+		f.GeometryExtraMembers = jg.ExtraMembers
 	}
 
-	return nil
+	f.ExtraMembers, err = unmarshalMembers(data, featureMembers)
+	return err
 }
 
 type jsonFeature struct {
-	ID         interface{} `json:"id,omitempty"`
+	ID         interface{} `json:"id"`
 	Type       string      `json:"type"`
-	BBox       BBox        `json:"bbox,omitempty"`
+	BBox       BBox        `json:"bbox"`
 	Geometry   *Geometry   `json:"geometry"`
 	Properties Properties  `json:"properties"`
 }
+
+// featureMembers are the keys of a feature defined by the GeoJSON spec.
+var featureMembers = map[string]bool{
+	"id":         true,
+	"type":       true,
+	"bbox":       true,
+	"geometry":   true,
+	"properties": true,
+}
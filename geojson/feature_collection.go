@@ -7,8 +7,8 @@ json.Unmarshaler interfaces as well as helper functions such as
 package geojson
 
 import (
-	"encoding/json"
 	"fmt"
+	"sort"
 )
 
 const featureCollection = "FeatureCollection"
@@ -45,6 +45,10 @@ func (fc *FeatureCollection) Append(feature *Feature) *FeatureCollection {
 // Items in the ExtraMembers map will be included in the base of the
 // feature collection object.
 func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	return fc.marshal(nil)
+}
+
+func (fc FeatureCollection) marshal(o *encodeOptions) ([]byte, error) {
 	var tmp map[string]interface{}
 	if fc.ExtraMembers != nil {
 		tmp = fc.ExtraMembers.Clone()
@@ -57,13 +61,73 @@ func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
 	if fc.BBox != nil {
 		tmp["bbox"] = fc.BBox
 	}
-	if fc.Features == nil {
-		tmp["features"] = []*Feature{}
-	} else {
-		tmp["features"] = fc.Features
+	tmp["features"] = nil
+
+	// the features are written directly so the coordinates aren't
+	// validated and copied again by the json package.
+	keys := make([]string, 0, len(tmp))
+	for k := range tmp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	size := 256
+	for _, f := range fc.Features {
+		if f != nil {
+			size += 128 + bufferSize(f.Geometry)
+		}
+	}
+
+	buf := make([]byte, 0, size)
+	buf = append(buf, '{')
+	for i, k := range keys {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+
+		key, err := marshalJSON(k)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+
+		if k == "features" {
+			buf, err = appendFeatures(buf, fc.Features, o)
+		} else {
+			var val []byte
+			val, err = marshalJSON(tmp[k])
+			buf = append(buf, val...)
+		}
+
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return json.Marshal(tmp)
+	return append(buf, '}'), nil
+}
+
+func appendFeatures(buf []byte, features []*Feature, o *encodeOptions) ([]byte, error) {
+	buf = append(buf, '[')
+	for i, f := range features {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+
+		if f == nil {
+			buf = append(buf, "null"...)
+			continue
+		}
+
+		var err error
+		buf, err = f.appendJSON(buf, o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(buf, ']'), nil
 }
 
 // UnmarshalJSON decodes the data into a GeoJSON feature collection.
@@ -71,7 +135,7 @@ func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
 func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
 	tmp := make(map[string]nocopyRawMessage, 4)
 
-	err := json.Unmarshal(data, &tmp)
+	err := unmarshalJSON(data, &tmp)
 	if err != nil {
 		return err
 	}
@@ -80,17 +144,17 @@ func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
 	for key, value := range tmp {
 		switch key {
 		case "type":
-			err := json.Unmarshal(value, &fc.Type)
+			err := unmarshalJSON(value, &fc.Type)
 			if err != nil {
 				return err
 			}
 		case "bbox":
-			err := json.Unmarshal(value, &fc.BBox)
+			err := unmarshalJSON(value, &fc.BBox)
 			if err != nil {
 				return err
 			}
 		case "features":
-			err := json.Unmarshal(value, &fc.Features)
+			err := unmarshalJSON(value, &fc.Features)
 			if err != nil {
 				return err
 			}
@@ -100,7 +164,7 @@ func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
 			}
 
 			var val interface{}
-			err := json.Unmarshal(value, &val)
+			err := unmarshalJSON(value, &val)
 			if err != nil {
 				return err
 			}
@@ -115,9 +179,22 @@ func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalFeatureCollection converts the feature collection into the proper
+// JSON, like MarshalJSON, with the encode options applied to the geometries.
+func MarshalFeatureCollection(fc *FeatureCollection, opts ...EncodeOption) ([]byte, error) {
+	return fc.marshal(newEncodeOptions(opts))
+}
+
 // UnmarshalFeatureCollection decodes the data into a GeoJSON feature collection.
 // Alternately one can call json.Unmarshal(fc) directly for the same result.
-func UnmarshalFeatureCollection(data []byte) (*FeatureCollection, error) {
+// With the Strict option the data is validated against RFC 7946 first.
+func UnmarshalFeatureCollection(data []byte, opts ...DecodeOption) (*FeatureCollection, error) {
+	if newDecodeOptions(opts).strict {
+		if err := validateStrict(data, featureCollection, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	fc := &FeatureCollection{}
 
 	err := fc.UnmarshalJSON(data)
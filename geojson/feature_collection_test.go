@@ -268,3 +268,58 @@ func TestFeatureCollectionMarshalJSON_extraMembers(t *testing.T) {
 		t.Fatalf("extras not in marshalled data")
 	}
 }
+
+func BenchmarkFeatureCollectionMarshalJSON(b *testing.B) {
+	fc := benchmarkFeatureCollection(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := fc.MarshalJSON()
+		if err != nil {
+			b.Fatalf("marshal error: %v", err)
+		}
+	}
+}
+
+func BenchmarkFeatureCollectionUnmarshalJSON(b *testing.B) {
+	data, err := benchmarkFeatureCollection(1000).MarshalJSON()
+	if err != nil {
+		b.Fatalf("marshal error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := UnmarshalFeatureCollection(data)
+		if err != nil {
+			b.Fatalf("unmarshal error: %v", err)
+		}
+	}
+}
+
+// benchmarkFeatureCollection returns a collection of n polygon features
+// with 100 points each, a few properties and coordinates with a realistic
+// number of decimal places.
+func benchmarkFeatureCollection(n int) *FeatureCollection {
+	fc := NewFeatureCollection()
+	for i := 0; i < n; i++ {
+		r := make(orb.Ring, 0, 100)
+		for j := 0; j < 99; j++ {
+			r = append(r, orb.Point{
+				-122.4194155 + float64(i)*0.0013 + float64(j)*0.00007,
+				37.7749295 + float64(i)*0.0017 - float64(j)*0.00003,
+			})
+		}
+		r = append(r, r[0])
+
+		f := NewFeature(orb.Round(orb.Polygon{r}, 1e7))
+		f.ID = i
+		f.Properties["name"] = "feature"
+		f.Properties["index"] = i
+		fc.Append(f)
+	}
+
+	return fc
+}
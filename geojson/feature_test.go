@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -206,6 +207,103 @@ func TestUnmarshalFeature_BBox(t *testing.T) {
 	}
 }
 
+func TestUnmarshalFeature_extraMembers(t *testing.T) {
+	rawJSON := `
+	  { "type": "Feature",
+	    "title": "a point",
+	    "style": {"color": "red"},
+	    "geometry": {"type": "Point", "coordinates": [102.0, 0.5]},
+	    "properties": {"prop0": "value0"}
+	  }`
+
+	f, err := UnmarshalFeature([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := Properties{
+		"title": "a point",
+		"style": map[string]interface{}{"color": "red"},
+	}
+	if !reflect.DeepEqual(f.ExtraMembers, expected) {
+		t.Errorf("incorrect extra members: %v", f.ExtraMembers)
+	}
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !bytes.HasSuffix(data, []byte(`"properties":{"prop0":"value0"},"style":{"color":"red"},"title":"a point"}`)) {
+		t.Errorf("extras not in marshalled data: %v", string(data))
+	}
+
+	f, err = UnmarshalFeature(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(f.ExtraMembers, expected) {
+		t.Errorf("should round trip extra members: %v", f.ExtraMembers)
+	}
+
+	// no extra members
+	f, err = UnmarshalFeature([]byte(`{"type":"Feature","geometry":null,"properties":null}`))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if f.ExtraMembers != nil {
+		t.Errorf("should not have extra members: %v", f.ExtraMembers)
+	}
+}
+
+func TestUnmarshalFeature_geometryExtraMembers(t *testing.T) {
+	rawJSON := `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2],"crs":"EPSG:4326"},"properties":null}`
+
+	f, err := UnmarshalFeature([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if f.ExtraMembers != nil {
+		t.Errorf("should not have extra members: %v", f.ExtraMembers)
+	}
+
+	expected := Properties{"crs": "EPSG:4326"}
+	if !reflect.DeepEqual(f.GeometryExtraMembers, expected) {
+		t.Errorf("incorrect geometry extra members: %v", f.GeometryExtraMembers)
+	}
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if string(data) != rawJSON {
+		t.Errorf("should round trip geometry extra members: %v", string(data))
+	}
+}
+
+func TestFeatureMarshalJSON_extraMembers(t *testing.T) {
+	f := NewFeature(orb.Point{1, 2})
+	f.ExtraMembers = Properties{
+		"crs":      "EPSG:4326",
+		"type":     "Other",
+		"geometry": "ignored",
+	}
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"crs":"EPSG:4326"}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
 func TestMarshalFeatureID(t *testing.T) {
 	f := &Feature{
 		ID: "asdf",
@@ -1,8 +1,8 @@
 package geojson
 
 import (
-	"encoding/json"
 	"errors"
+
 	"github.com/paulmach/orb"
 )
 
@@ -14,6 +14,17 @@ type Geometry struct {
 	Type        string       `json:"type"`
 	Coordinates orb.Geometry `json:"coordinates,omitempty"`
 	Geometries  []*Geometry  `json:"geometries,omitempty"`
+
+	// Layout and Extra are the z and/or m values of the coordinates,
+	// the third and fourth values of the positions. See orb.GeometryZM.
+	Layout orb.Layout `json:"-"`
+	Extra  []float64  `json:"-"`
+
+	// ExtraMembers can be used to encoded/decode extra key/members in
+	// the base of the geometry, e.g. "crs". Note that keys of "type",
+	// "coordinates" and "geometries" will not work as those are reserved
+	// by the GeoJSON spec.
+	ExtraMembers Properties `json:"-"`
 }
 
 // NewGeometry will create a Geometry object but will convert
@@ -56,43 +67,143 @@ func (g Geometry) Geometry() orb.Geometry {
 }
 
 // MarshalJSON will marshal the geometry into the correct json structure.
+// The coordinates are written directly, without reflection.
 func (g Geometry) MarshalJSON() ([]byte, error) {
 	if g.Coordinates == nil && len(g.Geometries) == 0 {
 		return []byte(`null`), nil
 	}
 
-	ng := &jsonGeometryMarshall{}
-	switch g := g.Coordinates.(type) {
+	return g.appendJSON(make([]byte, 0, bufferSize(g.Coordinates)), nil)
+}
+
+func (g Geometry) appendJSON(buf []byte, o *encodeOptions) ([]byte, error) {
+	if g.Coordinates == nil && len(g.Geometries) == 0 {
+		return append(buf, "null"...), nil
+	}
+
+	if g.Layout != orb.XY && g.Coordinates != nil {
+		zm := g.GeometryZM()
+		if !validZM(zm) {
+			return nil, ErrInvalidZM
+		}
+
+		// converts rings, bounds and collections along with their extra values.
+		jg := NewGeometryZM(zm)
+		jg.ExtraMembers = g.ExtraMembers
+		if jg.Layout == orb.XY {
+			return jg.appendJSON(buf, o)
+		}
+		g = *jg
+	}
+
+	var (
+		typ        string
+		coords     orb.Geometry
+		geometries []*Geometry
+	)
+	switch c := g.Coordinates.(type) {
+	case nil:
 	case orb.Ring:
-		ng.Coordinates = orb.Polygon{g}
+		coords = orb.Polygon{c}
 	case orb.Bound:
-		ng.Coordinates = g.ToPolygon()
+		coords = c.ToPolygon()
 	case orb.Collection:
-		ng.Geometries = make([]*Geometry, 0, len(g))
-		for _, c := range g {
-			ng.Geometries = append(ng.Geometries, NewGeometry(c))
+		geometries = make([]*Geometry, 0, len(c))
+		for _, cg := range c {
+			geometries = append(geometries, NewGeometry(cg))
 		}
-		ng.Type = g.GeoJSONType()
+		typ = c.GeoJSONType()
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString, orb.Polygon, orb.MultiPolygon:
+		coords = c
 	default:
-		ng.Coordinates = g
+		// some other orb.Geometry implementation, it is marshalled with
+		// the json package.
+		coords = c
 	}
 
-	if ng.Coordinates != nil {
-		ng.Type = ng.Coordinates.GeoJSONType()
+	if coords != nil {
+		typ = coords.GeoJSONType()
 	}
 
 	if len(g.Geometries) > 0 {
-		ng.Geometries = g.Geometries
-		ng.Type = orb.Collection{}.GeoJSONType()
+		geometries = g.Geometries
+		typ = orb.Collection{}.GeoJSONType()
+	}
+
+	buf = append(buf, `{"type":"`...)
+	buf = append(buf, typ...)
+	buf = append(buf, '"')
+
+	if coords != nil {
+		var err error
+		buf = append(buf, `,"coordinates":`...)
+
+		switch coords.(type) {
+		case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString, orb.Polygon, orb.MultiPolygon:
+			e := newCoordinateEncoder(g.Layout, g.Extra, o)
+			buf, err = e.coordinates(buf, coords)
+		default:
+			var d []byte
+			d, err = marshalJSON(coords)
+			buf = append(buf, d...)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(geometries) > 0 {
+		buf = append(buf, `,"geometries":[`...)
+		for i, cg := range geometries {
+			if i != 0 {
+				buf = append(buf, ',')
+			}
+
+			if cg == nil {
+				buf = append(buf, "null"...)
+				continue
+			}
+
+			var err error
+			buf, err = cg.appendJSON(buf, o)
+			if err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	}
+
+	buf, err := appendMembers(buf, g.ExtraMembers, geometryMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '}'), nil
+}
+
+// MarshalGeometry converts the geometry into the proper JSON, like
+// MarshalJSON, with the encode options applied.
+func MarshalGeometry(g *Geometry, opts ...EncodeOption) ([]byte, error) {
+	if g == nil {
+		return []byte(`null`), nil
 	}
-	return json.Marshal(ng)
+
+	return g.appendJSON(make([]byte, 0, bufferSize(g.Coordinates)), newEncodeOptions(opts))
 }
 
 // UnmarshalGeometry decodes the data into a GeoJSON feature.
 // Alternately one can call json.Unmarshal(g) directly for the same result.
-func UnmarshalGeometry(data []byte) (*Geometry, error) {
+// With the Strict option the data is validated against RFC 7946 first.
+func UnmarshalGeometry(data []byte, opts ...DecodeOption) (*Geometry, error) {
+	if newDecodeOptions(opts).strict {
+		if err := validateStrict(data, "Geometry", ""); err != nil {
+			return nil, err
+		}
+	}
+
 	g := &Geometry{}
-	err := json.Unmarshal(data, g)
+	err := unmarshalJSON(data, g)
 	if err != nil {
 		return nil, err
 	}
@@ -101,42 +212,29 @@ func UnmarshalGeometry(data []byte) (*Geometry, error) {
 }
 
 // UnmarshalJSON will unmarshal the correct geometry from the json structure.
+// Extra/foreign members will be put into the `ExtraMembers` attribute.
 func (g *Geometry) UnmarshalJSON(data []byte) error {
 	jg := &jsonGeometry{}
-	err := json.Unmarshal(data, jg)
+	err := unmarshalJSON(data, jg)
 	if err != nil {
 		return err
 	}
 
 	switch jg.Type {
-	case "Point":
-		p := orb.Point{}
-		err = json.Unmarshal(jg.Coordinates, &p)
-		g.Coordinates = p
-	case "MultiPoint":
-		mp := orb.MultiPoint{}
-		err = json.Unmarshal(jg.Coordinates, &mp)
-		g.Coordinates = mp
-	case "LineString":
-		ls := orb.LineString{}
-		err = json.Unmarshal(jg.Coordinates, &ls)
-		g.Coordinates = ls
-	case "MultiLineString":
-		mls := orb.MultiLineString{}
-		err = json.Unmarshal(jg.Coordinates, &mls)
-		g.Coordinates = mls
-	case "Polygon":
-		p := orb.Polygon{}
-		err = json.Unmarshal(jg.Coordinates, &p)
-		g.Coordinates = p
-	case "MultiPolygon":
-		mp := orb.MultiPolygon{}
-		err = json.Unmarshal(jg.Coordinates, &mp)
-		g.Coordinates = mp
 	case "GeometryCollection":
-		g.Geometries = jg.Geometries
+		*g = Geometry{Geometries: jg.Geometries}
 	default:
-		return ErrInvalidGeometry
+		c, l, extra, err := unmarshalCoordinates(jg.Type, jg.Coordinates)
+		if err != nil {
+			return err
+		}
+
+		*g = Geometry{Coordinates: c, Layout: l, Extra: extra}
+	}
+
+	g.ExtraMembers, err = unmarshalMembers(data, geometryMembers)
+	if err != nil {
+		return err
 	}
 
 	g.Type = g.Geometry().GeoJSONType()
@@ -144,6 +242,19 @@ func (g *Geometry) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type jsonGeometry struct {
+	Type        string           `json:"type"`
+	Coordinates nocopyRawMessage `json:"coordinates"`
+	Geometries  []*Geometry      `json:"geometries"`
+}
+
+// geometryMembers are the keys of a geometry defined by the GeoJSON spec.
+var geometryMembers = map[string]bool{
+	"type":        true,
+	"coordinates": true,
+	"geometries":  true,
+}
+
 // A Point is a helper type that will marshal to/from a GeoJSON Point geometry.
 type Point orb.Point
 
@@ -154,13 +265,13 @@ func (p Point) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the Point into a GeoJSON Point geometry.
 func (p Point) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.Point(p)})
+	return Geometry{Coordinates: orb.Point(p)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON Point geometry.
 func (p *Point) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -184,13 +295,13 @@ func (mp MultiPoint) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the MultiPoint into a GeoJSON MultiPoint geometry.
 func (mp MultiPoint) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.MultiPoint(mp)})
+	return Geometry{Coordinates: orb.MultiPoint(mp)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON MultiPoint geometry.
 func (mp *MultiPoint) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -214,13 +325,13 @@ func (ls LineString) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the LineString into a GeoJSON LineString geometry.
 func (ls LineString) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.LineString(ls)})
+	return Geometry{Coordinates: orb.LineString(ls)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON MultiPoint geometry.
 func (ls *LineString) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -244,13 +355,13 @@ func (mls MultiLineString) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the MultiLineString into a GeoJSON MultiLineString geometry.
 func (mls MultiLineString) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.MultiLineString(mls)})
+	return Geometry{Coordinates: orb.MultiLineString(mls)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON MultiPoint geometry.
 func (mls *MultiLineString) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -274,13 +385,13 @@ func (p Polygon) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the Polygon into a GeoJSON Polygon geometry.
 func (p Polygon) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.Polygon(p)})
+	return Geometry{Coordinates: orb.Polygon(p)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON Polygon geometry.
 func (p *Polygon) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -304,13 +415,13 @@ func (mp MultiPolygon) Geometry() orb.Geometry {
 
 // MarshalJSON will convert the MultiPolygon into a GeoJSON MultiPolygon geometry.
 func (mp MultiPolygon) MarshalJSON() ([]byte, error) {
-	return json.Marshal(Geometry{Coordinates: orb.MultiPolygon(mp)})
+	return Geometry{Coordinates: orb.MultiPolygon(mp)}.MarshalJSON()
 }
 
 // UnmarshalJSON will unmarshal the GeoJSON MultiPolygon geometry.
 func (mp *MultiPolygon) UnmarshalJSON(data []byte) error {
 	g := &Geometry{}
-	err := json.Unmarshal(data, &g)
+	err := unmarshalJSON(data, &g)
 	if err != nil {
 		return err
 	}
@@ -324,18 +435,6 @@ func (mp *MultiPolygon) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-type jsonGeometry struct {
-	Type        string           `json:"type"`
-	Coordinates nocopyRawMessage `json:"coordinates"`
-	Geometries  []*Geometry      `json:"geometries,omitempty"`
-}
-
-type jsonGeometryMarshall struct {
-	Type        string       `json:"type"`
-	Coordinates orb.Geometry `json:"coordinates,omitempty"`
-	Geometries  []*Geometry  `json:"geometries,omitempty"`
-}
-
 type nocopyRawMessage []byte
 
 func (m *nocopyRawMessage) UnmarshalJSON(data []byte) error {
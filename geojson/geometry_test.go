@@ -195,6 +195,75 @@ func TestGeometryUnmarshal(t *testing.T) {
 	}
 }
 
+func TestGeometryUnmarshal_extraMembers(t *testing.T) {
+	rawJSON := `{
+		"type": "GeometryCollection",
+		"crs": {"type": "name", "properties": {"name": "EPSG:4326"}},
+		"geometries": [
+			{"type": "Point", "coordinates": [1, 2, 3], "title": "peak"}
+		]
+	}`
+
+	g, err := UnmarshalGeometry([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	crs := map[string]interface{}{
+		"type":       "name",
+		"properties": map[string]interface{}{"name": "EPSG:4326"},
+	}
+	if !reflect.DeepEqual(g.ExtraMembers, Properties{"crs": crs}) {
+		t.Errorf("incorrect extra members: %v", g.ExtraMembers)
+	}
+
+	if v := g.Geometries[0].ExtraMembers.MustString("title", ""); v != "peak" {
+		t.Errorf("incorrect child extra members: %v", g.Geometries[0].ExtraMembers)
+	}
+
+	data, err := g.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2,3],"title":"peak"}],"crs":{"properties":{"name":"EPSG:4326"},"type":"name"}}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
+func TestGeometryMarshal_extraMembers(t *testing.T) {
+	g := NewGeometry(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	g.ExtraMembers = Properties{
+		"title":       "box",
+		"coordinates": "ignored",
+	}
+
+	data, err := g.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]],"title":"box"}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+
+	// with z values
+	g = NewGeometryZM(orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}})
+	g.ExtraMembers = Properties{"title": "peak"}
+
+	data, err = g.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected = `{"type":"Point","coordinates":[1,2,3],"title":"peak"}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
 func TestHelperTypes(t *testing.T) {
 	// This test makes sure the marshal-unmarshal loop does the same thing.
 	// The code and types here are complicated to avoid duplicate code.
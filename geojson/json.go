@@ -0,0 +1,159 @@
+package geojson
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// CustomJSONMarshaler can be set to have the code use a different
+// json marshaler than the default in the standard library.
+// One use case in enabling `github.com/json-iterator/go`
+// with something like this:
+//
+//	import (
+//	  jsoniter "github.com/json-iterator/go"
+//	  "github.com/paulmach/orb/geojson"
+//	)
+//
+//	var c = jsoniter.Config{
+//	  EscapeHTML:              true,
+//	  SortMapKeys:             false,
+//	  MarshalFloatWith6Digits: true,
+//	}.Froze()
+//
+//	geojson.CustomJSONMarshaler = c
+//	geojson.CustomJSONUnmarshaler = c
+//
+// This is used for the features, properties and collection members.
+// Coordinates are always encoded and decoded by the hand-written codec in
+// this package since that is faster than any reflection based approach.
+// Note that any errors encountered during marshaling will be different.
+var CustomJSONMarshaler interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// CustomJSONUnmarshaler can be set to have the code use a different
+// json unmarshaler than the default in the standard library.
+// See CustomJSONMarshaler for an example using jsoniter.
+var CustomJSONUnmarshaler interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	if CustomJSONMarshaler == nil {
+		return json.Marshal(v)
+	}
+
+	return CustomJSONMarshaler.Marshal(v)
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	if CustomJSONUnmarshaler == nil {
+		return json.Unmarshal(data, v)
+	}
+
+	return CustomJSONUnmarshaler.Unmarshal(data, v)
+}
+
+// unmarshalMembers decodes the members of the object that are not
+// reserved by GeoJSON. The result is nil if there are none, the object
+// is only decoded again if there are.
+func unmarshalMembers(data []byte, reserved map[string]bool) (Properties, error) {
+	if !hasMembers(data, reserved) {
+		return nil, nil
+	}
+
+	tmp := make(map[string]nocopyRawMessage, len(reserved)+1)
+	if err := unmarshalJSON(data, &tmp); err != nil {
+		return nil, err
+	}
+
+	result := Properties{}
+	for key, value := range tmp {
+		if reserved[key] {
+			continue
+		}
+
+		var val interface{}
+		if err := unmarshalJSON(value, &val); err != nil {
+			return nil, err
+		}
+		result[key] = val
+	}
+
+	return result, nil
+}
+
+// hasMembers scans the keys of the json object and returns true if
+// any are not reserved. The data must be valid json, keys with escaped
+// characters are treated as not reserved.
+func hasMembers(data []byte, reserved map[string]bool) bool {
+	depth := 0
+	key := false
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '{':
+			depth++
+			key = depth == 1
+		case '[':
+			depth++
+		case '}', ']':
+			depth--
+		case ',':
+			key = depth == 1
+		case '"':
+			start, escaped := i+1, false
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					escaped = true
+					i++
+				}
+			}
+
+			if key {
+				if escaped || !reserved[string(data[start:i])] {
+					return true
+				}
+				key = false
+			}
+		}
+	}
+
+	return false
+}
+
+// appendMembers writes the members, sorted by key, that are not
+// reserved by the GeoJSON object. The buffer should have at least
+// one member already written.
+func appendMembers(buf []byte, members Properties, reserved map[string]bool) ([]byte, error) {
+	if len(members) == 0 {
+		return buf, nil
+	}
+
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		if !reserved[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key, err := marshalJSON(k)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := marshalJSON(members[k])
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, ',')
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, val...)
+	}
+
+	return buf, nil
+}
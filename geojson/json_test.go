@@ -0,0 +1,113 @@
+package geojson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+type countingJSON struct {
+	marshal   int
+	unmarshal int
+}
+
+func (c *countingJSON) Marshal(v interface{}) ([]byte, error) {
+	c.marshal++
+	return json.Marshal(v)
+}
+
+func (c *countingJSON) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshal++
+	return json.Unmarshal(data, v)
+}
+
+func TestCustomJSONMarshaler(t *testing.T) {
+	c := &countingJSON{}
+	CustomJSONMarshaler = c
+	CustomJSONUnmarshaler = c
+	defer func() {
+		CustomJSONMarshaler = nil
+		CustomJSONUnmarshaler = nil
+	}()
+
+	f := NewFeature(orb.LineString{{1, 2}, {3, 4}})
+	f.ID = 1
+	f.Properties["a"] = "b"
+
+	fc := NewFeatureCollection().Append(f)
+	fc.ExtraMembers = Properties{"foo": "bar"}
+
+	data, err := fc.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"features":[{"id":1,"type":"Feature","geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":{"a":"b"}}],"foo":"bar","type":"FeatureCollection"}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+
+	if c.marshal == 0 {
+		t.Errorf("should use the custom marshaler")
+	}
+
+	fc, err = UnmarshalFeatureCollection(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if c.unmarshal == 0 {
+		t.Errorf("should use the custom unmarshaler")
+	}
+
+	if !orb.Equal(fc.Features[0].Geometry, f.Geometry) {
+		t.Errorf("incorrect geometry: %v", fc.Features[0].Geometry)
+	}
+}
+
+func TestHasMembers(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected bool
+	}{
+		{
+			name:     "reserved",
+			data:     `{"type":"Feature","geometry":{"type":"Point","title":"a"},"properties":{"title":"b"}}`,
+			expected: false,
+		},
+		{
+			name:     "extra member",
+			data:     `{"type":"Feature","properties":null,"title":"a"}`,
+			expected: true,
+		},
+		{
+			name:     "values that look like keys",
+			data:     `{"type":"title","id":["title",{"title":1}],"bbox":"\\\"title"}`,
+			expected: false,
+		},
+		{
+			name:     "escaped key",
+			data:     `{"typ\u0065":"Feature"}`,
+			expected: true,
+		},
+		{
+			name:     "empty",
+			data:     ` { } `,
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !json.Valid([]byte(tc.data)) {
+				t.Fatalf("invalid json: %v", tc.data)
+			}
+
+			if v := hasMembers([]byte(tc.data), featureMembers); v != tc.expected {
+				t.Errorf("incorrect result: %v != %v", v, tc.expected)
+			}
+		})
+	}
+}
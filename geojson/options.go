@@ -0,0 +1,76 @@
+package geojson
+
+import "math"
+
+type decodeOptions struct {
+	strict bool
+}
+
+// A DecodeOption is a possible parameter to the unmarshal functions
+// and the streaming Decoder.
+type DecodeOption func(*decodeOptions)
+
+// Strict will validate the data against RFC 7946 before decoding it.
+// All the violations found are returned as ValidationErrors, for example
+// rings that are not closed or do not follow the right-hand rule,
+// positions with less than two numbers and missing required members.
+func Strict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+func newDecodeOptions(opts []DecodeOption) *decodeOptions {
+	o := &decodeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+type encodeOptions struct {
+	rightHand bool
+
+	// factor is 10^precision, zero means no rounding.
+	factor float64
+}
+
+// An EncodeOption is a possible parameter to the marshal functions
+// and the streaming encoders.
+type EncodeOption func(*encodeOptions)
+
+// RightHandRule will write polygon rings following the right-hand rule,
+// exterior rings counterclockwise and holes clockwise, reversing them
+// if needed. The geometry itself is not modified.
+func RightHandRule() EncodeOption {
+	return func(o *encodeOptions) {
+		o.rightHand = true
+	}
+}
+
+// Precision will round the coordinates, and any z or m values, to
+// the number of decimal places.
+func Precision(decimals int) EncodeOption {
+	return func(o *encodeOptions) {
+		o.factor = math.Pow10(decimals)
+	}
+}
+
+// RFC7946 will write the output as recommended by the spec, with the
+// right-hand rule winding and 6 decimal places, about 10 centimeters.
+func RFC7946() EncodeOption {
+	return func(o *encodeOptions) {
+		RightHandRule()(o)
+		Precision(6)(o)
+	}
+}
+
+func newEncodeOptions(opts []EncodeOption) *encodeOptions {
+	o := &encodeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
@@ -0,0 +1,178 @@
+package geojson
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestMarshalGeometry_options(t *testing.T) {
+	cw := orb.Ring{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}
+	ccw := orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+
+	cases := []struct {
+		name     string
+		geom     *Geometry
+		opts     []EncodeOption
+		expected string
+	}{
+		{
+			name:     "no options",
+			geom:     NewGeometry(orb.Polygon{cw}),
+			expected: `{"type":"Polygon","coordinates":[[[0,0],[0,1],[1,1],[1,0],[0,0]]]}`,
+		},
+		{
+			name:     "reverse exterior",
+			geom:     NewGeometry(orb.Polygon{cw}),
+			opts:     []EncodeOption{RightHandRule()},
+			expected: `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}`,
+		},
+		{
+			name:     "reverse hole",
+			geom:     NewGeometry(orb.MultiPolygon{{ccw, ccw}}),
+			opts:     []EncodeOption{RightHandRule()},
+			expected: `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,1],[0,0]],[[0,0],[0,1],[1,1],[1,0],[0,0]]]]}`,
+		},
+		{
+			name: "reverse extra values",
+			geom: &Geometry{
+				Coordinates: orb.Polygon{cw},
+				Layout:      orb.XYZ,
+				Extra:       []float64{1, 2, 3, 4, 1},
+			},
+			opts:     []EncodeOption{RightHandRule()},
+			expected: `{"type":"Polygon","coordinates":[[[0,0,1],[1,0,4],[1,1,3],[0,1,2],[0,0,1]]]}`,
+		},
+		{
+			name:     "precision",
+			geom:     NewGeometry(orb.LineString{{1.23456, -1.23456}, {0.5, 2}}),
+			opts:     []EncodeOption{Precision(2)},
+			expected: `{"type":"LineString","coordinates":[[1.23,-1.23],[0.5,2]]}`,
+		},
+		{
+			name: "precision extra values",
+			geom: &Geometry{
+				Coordinates: orb.Point{1.23456, 2},
+				Layout:      orb.XYZ,
+				Extra:       []float64{3.14159},
+			},
+			opts:     []EncodeOption{Precision(1)},
+			expected: `{"type":"Point","coordinates":[1.2,2,3.1]}`,
+		},
+		{
+			name:     "rfc 7946",
+			geom:     NewGeometry(orb.Polygon{{{0, 0}, {0, 1.0000001}, {1, 1}, {1, 0}, {0, 0}}}),
+			opts:     []EncodeOption{RFC7946()},
+			expected: `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}`,
+		},
+		{
+			name: "geometry collection",
+			geom: &Geometry{
+				Geometries: []*Geometry{NewGeometry(orb.Polygon{cw})},
+			},
+			opts:     []EncodeOption{RightHandRule()},
+			expected: `{"type":"GeometryCollection","geometries":[{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := MarshalGeometry(tc.geom, tc.opts...)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if string(data) != tc.expected {
+				t.Errorf("incorrect json: %v", string(data))
+			}
+		})
+	}
+
+	// the geometry should not be modified
+	p := orb.Polygon{cw.Clone()}
+	_, err := MarshalGeometry(NewGeometry(p), RFC7946())
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !p.Equal(orb.Polygon{cw}) {
+		t.Errorf("polygon was modified: %v", p)
+	}
+}
+
+func TestMarshalFeature_options(t *testing.T) {
+	f := NewFeature(orb.Point{1.23456789, 2})
+	f.Properties["value"] = 1.23456789
+
+	data, err := MarshalFeature(f, Precision(3))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// properties are not rounded
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[1.235,2]},"properties":{"value":1.23456789}}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+
+	data, err = MarshalFeature(nil, Precision(3))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if string(data) != "null" {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
+
+func TestMarshalFeatureCollection_options(t *testing.T) {
+	fc := NewFeatureCollection()
+	fc.Append(NewFeature(orb.Polygon{{{0, 0}, {0, 1}, {1, 1}, {0, 0}}}))
+
+	data, err := MarshalFeatureCollection(fc, RFC7946())
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// the output should pass the strict validation
+	_, err = UnmarshalFeatureCollection(data, Strict())
+	if err != nil {
+		t.Errorf("should be valid: %v", err)
+	}
+
+	_, err = UnmarshalFeatureCollection(mustMarshal(t, fc), Strict())
+	if err == nil {
+		t.Errorf("should not be valid without the option")
+	}
+}
+
+func TestEncoder_options(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf, Precision(0))
+	if err := e.Encode(NewFeature(orb.Point{1.4, 2.6})); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	expected := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,3]},"properties":null}` +
+		`]}`
+	if buf.String() != expected {
+		t.Errorf("incorrect json: %v", buf.String())
+	}
+}
+
+func mustMarshal(t testing.TB, fc *FeatureCollection) []byte {
+	t.Helper()
+
+	data, err := fc.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	return data
+}
@@ -1,6 +1,28 @@
 package geojson
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrPropertyNotFound is returned by the typed property getters
+// if the key is not in the properties.
+var ErrPropertyNotFound = errors.New("geojson: property not found")
+
+// A PropertyTypeError is returned by the typed property getters
+// if the value is present but can not be converted to the type.
+type PropertyTypeError struct {
+	Key   string
+	Type  string
+	Value interface{}
+}
+
+func (e *PropertyTypeError) Error() string {
+	return fmt.Sprintf("geojson: property %s is not a %s, but a %T: %v", e.Key, e.Type, e.Value, e.Value)
+}
 
 // Properties defines the feature properties with some helper methods.
 type Properties map[string]interface{}
@@ -110,3 +132,216 @@ func (p Properties) Clone() Properties {
 
 	return n
 }
+
+// Bool returns the value of the key as a `bool`. Returns ErrPropertyNotFound
+// if the key is not present or a *PropertyTypeError if it is not a bool.
+func (p Properties) Bool(key string) (bool, error) {
+	v, ok := p[key]
+	if !ok {
+		return false, ErrPropertyNotFound
+	}
+
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+
+	return false, &PropertyTypeError{Key: key, Type: "bool", Value: v}
+}
+
+// Int returns the value of the key as an `int`. Any number type is supported,
+// including the float64 values from json decoding, but it must be a whole number.
+func (p Properties) Int(key string) (int, error) {
+	v, ok := p[key]
+	if !ok {
+		return 0, ErrPropertyNotFound
+	}
+
+	switch i := v.(type) {
+	case int:
+		return i, nil
+	case int64:
+		return int(i), nil
+	case json.Number:
+		n, err := i.Int64()
+		if err == nil {
+			return int(n), nil
+		}
+	default:
+		f, ok := toFloat64(v)
+		if ok && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+			return int(f), nil
+		}
+	}
+
+	return 0, &PropertyTypeError{Key: key, Type: "int", Value: v}
+}
+
+// Float64 returns the value of the key as a `float64`.
+// Any number type, including json.Number, is supported.
+func (p Properties) Float64(key string) (float64, error) {
+	v, ok := p[key]
+	if !ok {
+		return 0, ErrPropertyNotFound
+	}
+
+	if f, ok := toFloat64(v); ok {
+		return f, nil
+	}
+
+	return 0, &PropertyTypeError{Key: key, Type: "number", Value: v}
+}
+
+// StringValue returns the value of the key as a `string`. It is not named
+// String so Properties does not implement fmt.Stringer.
+func (p Properties) StringValue(key string) (string, error) {
+	v, ok := p[key]
+	if !ok {
+		return "", ErrPropertyNotFound
+	}
+
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	return "", &PropertyTypeError{Key: key, Type: "string", Value: v}
+}
+
+// Number returns the value of the key as a `json.Number`, this can be used
+// to keep the exact text of the number. Other number types are formatted
+// like the encoding/json package would.
+func (p Properties) Number(key string) (json.Number, error) {
+	v, ok := p[key]
+	if !ok {
+		return "", ErrPropertyNotFound
+	}
+
+	if n, ok := v.(json.Number); ok {
+		return n, nil
+	}
+
+	if f, ok := toFloat64(v); ok {
+		if data, err := appendFloat(nil, f); err == nil {
+			return json.Number(data), nil
+		}
+	}
+
+	return "", &PropertyTypeError{Key: key, Type: "number", Value: v}
+}
+
+// Time returns the value of the key as a `time.Time`. The value can be
+// a time.Time or a string in RFC 3339 format, as encoded by encoding/json.
+func (p Properties) Time(key string) (time.Time, error) {
+	v, ok := p[key]
+	if !ok {
+		return time.Time{}, ErrPropertyNotFound
+	}
+
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, &PropertyTypeError{Key: key, Type: "time", Value: v}
+}
+
+// Map returns the value of the key as a nested `Properties` object.
+func (p Properties) Map(key string) (Properties, error) {
+	v, ok := p[key]
+	if !ok {
+		return nil, ErrPropertyNotFound
+	}
+
+	switch m := v.(type) {
+	case Properties:
+		return m, nil
+	case map[string]interface{}:
+		return Properties(m), nil
+	}
+
+	return nil, &PropertyTypeError{Key: key, Type: "map", Value: v}
+}
+
+// Slice returns the value of the key as an `[]interface{}`,
+// as decoded from a json array.
+func (p Properties) Slice(key string) ([]interface{}, error) {
+	v, ok := p[key]
+	if !ok {
+		return nil, ErrPropertyNotFound
+	}
+
+	if s, ok := v.([]interface{}); ok {
+		return s, nil
+	}
+
+	return nil, &PropertyTypeError{Key: key, Type: "slice", Value: v}
+}
+
+// Decode will decode the properties into the value, a pointer to a struct
+// for example. The `json` struct tags are used to match the keys to the
+// fields, the same as json.Unmarshal of the properties would.
+func (p Properties) Decode(v interface{}) error {
+	data, err := marshalJSON(p)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalJSON(data, v)
+}
+
+// PropertiesFrom returns the properties of the value, a struct or map,
+// using the `json` struct tags for the keys. The values will be the same
+// as if the properties were decoded from json, e.g. numbers are float64
+// and time.Time values are RFC 3339 strings.
+func PropertiesFrom(v interface{}) (Properties, error) {
+	data, err := marshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Properties
+	err = unmarshalJSON(data, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
@@ -1,7 +1,10 @@
 package geojson
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func propertiesTestFeature() *Feature {
@@ -131,3 +134,269 @@ func TestPropertiesClone(t *testing.T) {
 		t.Errorf("should clone properties")
 	}
 }
+
+func TestProperties_typed(t *testing.T) {
+	now := time.Date(2021, 6, 15, 1, 2, 3, 0, time.UTC)
+	props := Properties{
+		"bool":    true,
+		"int":     5,
+		"float64": 1.5,
+		"whole":   2.0,
+		"number":  json.Number("12345678901234567890"),
+		"string":  "text",
+		"time":    now,
+		"rfc3339": "2021-06-15T01:02:03Z",
+		"map":     map[string]interface{}{"a": "b"},
+		"slice":   []interface{}{1.0, "a"},
+	}
+
+	cases := []struct {
+		name     string
+		get      func() (interface{}, error)
+		expected interface{}
+	}{
+		{
+			name:     "bool",
+			get:      func() (interface{}, error) { return props.Bool("bool") },
+			expected: true,
+		},
+		{
+			name:     "int",
+			get:      func() (interface{}, error) { return props.Int("int") },
+			expected: 5,
+		},
+		{
+			name:     "int from whole float",
+			get:      func() (interface{}, error) { return props.Int("whole") },
+			expected: 2,
+		},
+		{
+			name:     "float64",
+			get:      func() (interface{}, error) { return props.Float64("float64") },
+			expected: 1.5,
+		},
+		{
+			name:     "float64 from int",
+			get:      func() (interface{}, error) { return props.Float64("int") },
+			expected: 5.0,
+		},
+		{
+			name:     "float64 from number",
+			get:      func() (interface{}, error) { return props.Float64("number") },
+			expected: 12345678901234567890.0,
+		},
+		{
+			name:     "number",
+			get:      func() (interface{}, error) { return props.Number("number") },
+			expected: json.Number("12345678901234567890"),
+		},
+		{
+			name:     "number from float64",
+			get:      func() (interface{}, error) { return props.Number("float64") },
+			expected: json.Number("1.5"),
+		},
+		{
+			name:     "string",
+			get:      func() (interface{}, error) { return props.StringValue("string") },
+			expected: "text",
+		},
+		{
+			name:     "time",
+			get:      func() (interface{}, error) { return props.Time("time") },
+			expected: now,
+		},
+		{
+			name:     "time from string",
+			get:      func() (interface{}, error) { return props.Time("rfc3339") },
+			expected: now,
+		},
+		{
+			name:     "map",
+			get:      func() (interface{}, error) { return props.Map("map") },
+			expected: Properties{"a": "b"},
+		},
+		{
+			name:     "slice",
+			get:      func() (interface{}, error) { return props.Slice("slice") },
+			expected: []interface{}{1.0, "a"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := tc.get()
+			if err != nil {
+				t.Fatalf("get error: %v", err)
+			}
+
+			if !reflect.DeepEqual(v, tc.expected) {
+				t.Errorf("incorrect value: %v != %v", v, tc.expected)
+			}
+		})
+	}
+}
+
+func TestProperties_typedErrors(t *testing.T) {
+	props := Properties{
+		"string": "text",
+		"float":  1.5,
+	}
+
+	cases := []struct {
+		name string
+		get  func() (interface{}, error)
+		err  error
+	}{
+		{
+			name: "not found",
+			get:  func() (interface{}, error) { return props.StringValue("random") },
+			err:  ErrPropertyNotFound,
+		},
+		{
+			name: "bool",
+			get:  func() (interface{}, error) { return props.Bool("string") },
+			err:  &PropertyTypeError{Key: "string", Type: "bool", Value: "text"},
+		},
+		{
+			name: "int not whole",
+			get:  func() (interface{}, error) { return props.Int("float") },
+			err:  &PropertyTypeError{Key: "float", Type: "int", Value: 1.5},
+		},
+		{
+			name: "float64",
+			get:  func() (interface{}, error) { return props.Float64("string") },
+			err:  &PropertyTypeError{Key: "string", Type: "number", Value: "text"},
+		},
+		{
+			name: "string",
+			get:  func() (interface{}, error) { return props.StringValue("float") },
+			err:  &PropertyTypeError{Key: "float", Type: "string", Value: 1.5},
+		},
+		{
+			name: "time",
+			get:  func() (interface{}, error) { return props.Time("string") },
+			err:  &PropertyTypeError{Key: "string", Type: "time", Value: "text"},
+		},
+		{
+			name: "map",
+			get:  func() (interface{}, error) { return props.Map("string") },
+			err:  &PropertyTypeError{Key: "string", Type: "map", Value: "text"},
+		},
+		{
+			name: "slice",
+			get:  func() (interface{}, error) { return props.Slice("string") },
+			err:  &PropertyTypeError{Key: "string", Type: "slice", Value: "text"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.get()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("incorrect error: %v != %v", err, tc.err)
+			}
+		})
+	}
+}
+
+type testAttributes struct {
+	Name    string            `json:"name"`
+	Lanes   int               `json:"lanes"`
+	Speed   float64           `json:"speed,omitempty"`
+	Opened  time.Time         `json:"opened"`
+	Tags    []string          `json:"tags"`
+	Extra   map[string]string `json:"extra"`
+	Count   json.Number       `json:"count"`
+	Ignored string            `json:"-"`
+}
+
+func TestProperties_Decode(t *testing.T) {
+	rawJSON := `{
+		"type": "Feature",
+		"geometry": {"type": "Point", "coordinates": [1, 2]},
+		"properties": {
+			"name": "Main St",
+			"lanes": 2,
+			"opened": "2021-06-15T01:02:03Z",
+			"tags": ["a", "b"],
+			"extra": {"surface": "asphalt"},
+			"count": 12345678901234567,
+			"other": true
+		}
+	}`
+
+	f, err := UnmarshalFeature([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	var attrs testAttributes
+	err = f.Properties.Decode(&attrs)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	expected := testAttributes{
+		Name:   "Main St",
+		Lanes:  2,
+		Opened: time.Date(2021, 6, 15, 1, 2, 3, 0, time.UTC),
+		Tags:   []string{"a", "b"},
+		Extra:  map[string]string{"surface": "asphalt"},
+		Count:  json.Number("12345678901234568"),
+	}
+	if !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("incorrect struct: %+v", attrs)
+	}
+
+	// wrong types should return an error
+	err = Properties{"lanes": "two"}.Decode(&attrs)
+	if err == nil {
+		t.Errorf("should return error for wrong type")
+	}
+}
+
+func TestPropertiesFrom(t *testing.T) {
+	attrs := testAttributes{
+		Name:    "Main St",
+		Lanes:   2,
+		Opened:  time.Date(2021, 6, 15, 1, 2, 3, 0, time.UTC),
+		Tags:    []string{"a"},
+		Count:   json.Number("10"),
+		Ignored: "ignored",
+	}
+
+	props, err := PropertiesFrom(attrs)
+	if err != nil {
+		t.Fatalf("properties error: %v", err)
+	}
+
+	expected := Properties{
+		"name":   "Main St",
+		"lanes":  2.0,
+		"opened": "2021-06-15T01:02:03Z",
+		"tags":   []interface{}{"a"},
+		"extra":  nil,
+		"count":  10.0,
+	}
+	if !reflect.DeepEqual(props, expected) {
+		t.Errorf("incorrect properties: %v", props)
+	}
+
+	// should round trip
+	var decoded testAttributes
+	err = props.Decode(&decoded)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	attrs.Ignored = ""
+	if !reflect.DeepEqual(decoded, attrs) {
+		t.Errorf("should round trip: %+v", decoded)
+	}
+
+	// not an object
+	_, err = PropertiesFrom([]int{1, 2})
+	if err == nil {
+		t.Errorf("should return error if not an object")
+	}
+}
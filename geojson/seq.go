@@ -0,0 +1,87 @@
+package geojson
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// recordSeparator starts each feature of a GeoJSON text sequence.
+const recordSeparator = 0x1E
+
+// A SeqDecoder reads features from a GeoJSON text sequence, RFC 8142.
+// Newline delimited GeoJSON, without the record separators, is also supported.
+type SeqDecoder struct {
+	d *json.Decoder
+}
+
+// NewSeqDecoder creates a new SeqDecoder for the reader.
+func NewSeqDecoder(r io.Reader) *SeqDecoder {
+	return &SeqDecoder{d: json.NewDecoder(&rsReader{r: bufio.NewReader(r)})}
+}
+
+// Decode returns the next feature in the sequence.
+// It returns io.EOF when there are no more features.
+func (d *SeqDecoder) Decode() (*Feature, error) {
+	f := &Feature{}
+	if err := d.d.Decode(f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// rsReader replaces the record separators with spaces so the
+// json decoder sees whitespace between the texts.
+type rsReader struct {
+	r io.Reader
+}
+
+func (r *rsReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == recordSeparator {
+			p[i] = ' '
+		}
+	}
+
+	return n, err
+}
+
+// A SeqEncoder writes features as a GeoJSON text sequence, RFC 8142.
+type SeqEncoder struct {
+	w    io.Writer
+	rs   bool
+	opts *encodeOptions
+}
+
+// NewSeqEncoder creates a new SeqEncoder that writes to w.
+// The encode options are applied to the geometry of each feature.
+func NewSeqEncoder(w io.Writer, opts ...EncodeOption) *SeqEncoder {
+	return &SeqEncoder{w: w, rs: true, opts: newEncodeOptions(opts)}
+}
+
+// SetRecordSeparator sets if each feature should start with the record
+// separator character. Default is true, as required by RFC 8142. Set to false
+// to write newline delimited GeoJSON.
+func (e *SeqEncoder) SetRecordSeparator(rs bool) {
+	e.rs = rs
+}
+
+// Encode writes the feature followed by a newline.
+func (e *SeqEncoder) Encode(f *Feature) error {
+	data, err := marshalFeature(f, e.opts)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, len(data)+2)
+	if e.rs {
+		buf = append(buf, recordSeparator)
+	}
+	buf = append(buf, data...)
+	buf = append(buf, '\n')
+
+	_, err = e.w.Write(buf)
+	return err
+}
@@ -0,0 +1,103 @@
+package geojson
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestSeqDecoder(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "record separators",
+			data: "\x1e{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":{\"a\":1}}\n" +
+				"\x1e{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},\"properties\":{\"a\":2}}\n",
+		},
+		{
+			name: "newline delimited",
+			data: "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":{\"a\":1}}\n" +
+				"{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},\"properties\":{\"a\":2}}",
+		},
+		{
+			name: "pretty printed",
+			data: "\x1e{\n  \"type\": \"Feature\",\n  \"geometry\": {\"type\": \"Point\", \"coordinates\": [1, 2]},\n  \"properties\": {\"a\": 1}\n}\n" +
+				"\x1e{\n  \"type\": \"Feature\",\n  \"geometry\": {\"type\": \"Point\", \"coordinates\": [3, 4]},\n  \"properties\": {\"a\": 2}\n}\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewSeqDecoder(strings.NewReader(tc.data))
+
+			var features []*Feature
+			for {
+				f, err := d.Decode()
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					t.Fatalf("decode error: %v", err)
+				}
+
+				features = append(features, f)
+			}
+
+			if len(features) != 2 {
+				t.Fatalf("incorrect number of features: %d", len(features))
+			}
+
+			if !orb.Equal(features[1].Geometry, orb.Point{3, 4}) {
+				t.Errorf("incorrect geometry: %v", features[1].Geometry)
+			}
+
+			if v := features[1].Properties["a"]; v != 2.0 {
+				t.Errorf("incorrect property: %v", v)
+			}
+		})
+	}
+}
+
+func TestSeqDecoder_error(t *testing.T) {
+	d := NewSeqDecoder(strings.NewReader("\x1e{\"type\":\"Feature\",\n\x1e{\"type\":\"Feature\"}\n"))
+	if _, err := d.Decode(); err == nil {
+		t.Errorf("should return error for truncated feature")
+	}
+}
+
+func TestSeqEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewSeqEncoder(buf)
+
+	if err := e.Encode(NewFeature(orb.Point{1, 2})); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	e.SetRecordSeparator(false)
+	if err := e.Encode(NewFeature(orb.Point{3, 4})); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	expected := "\x1e{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":null}\n" +
+		"{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},\"properties\":null}\n"
+	if buf.String() != expected {
+		t.Errorf("incorrect output: %q", buf.String())
+	}
+
+	d := NewSeqDecoder(buf)
+	for i := 0; i < 2; i++ {
+		if _, err := d.Decode(); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected eof: %v", err)
+	}
+}
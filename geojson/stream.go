@@ -0,0 +1,250 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// A Decoder reads the features of a feature collection from a stream,
+// one at a time, so the whole collection does not need to be in memory.
+type Decoder struct {
+	d *json.Decoder
+
+	// BBox and ExtraMembers of the feature collection are set as they are
+	// read. If they come after the features in the data, they will only be
+	// set once Decode returns io.EOF.
+	BBox         BBox
+	ExtraMembers Properties
+
+	typ        string
+	started    bool
+	inFeatures bool
+	done       bool
+
+	strict bool
+	index  int
+}
+
+// NewDecoder creates a new Decoder for the feature collection in the reader.
+// With the Strict option each feature is validated against RFC 7946 before
+// it is returned, the error paths start with features[i].
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	return &Decoder{
+		d:      json.NewDecoder(r),
+		strict: newDecodeOptions(opts).strict,
+	}
+}
+
+// Decode returns the next feature of the collection.
+// It returns io.EOF after the end of the collection.
+func (d *Decoder) Decode() (*Feature, error) {
+	if !d.started {
+		d.started = true
+		if err := d.delim('{'); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if d.done {
+			return nil, io.EOF
+		}
+
+		if d.inFeatures {
+			if d.d.More() {
+				return d.feature()
+			}
+
+			d.inFeatures = false
+			if err := d.delim(']'); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		tok, err := d.d.Token()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		if tok == json.Delim('}') {
+			d.done = true
+			if d.typ != featureCollection {
+				return nil, fmt.Errorf("geojson: not a feature collection: type=%s", d.typ)
+			}
+
+			return nil, io.EOF
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("geojson: invalid feature collection: unexpected %v", tok)
+		}
+
+		switch key {
+		case "type":
+			if err := d.d.Decode(&d.typ); err != nil {
+				return nil, err
+			}
+
+			if d.typ != featureCollection {
+				return nil, fmt.Errorf("geojson: not a feature collection: type=%s", d.typ)
+			}
+		case "bbox":
+			if err := d.d.Decode(&d.BBox); err != nil {
+				return nil, err
+			}
+		case "features":
+			if err := d.delim('['); err != nil {
+				return nil, err
+			}
+			d.inFeatures = true
+		default:
+			var val interface{}
+			if err := d.d.Decode(&val); err != nil {
+				return nil, err
+			}
+
+			if d.ExtraMembers == nil {
+				d.ExtraMembers = Properties{}
+			}
+			d.ExtraMembers[key] = val
+		}
+	}
+}
+
+func (d *Decoder) feature() (*Feature, error) {
+	f := &Feature{}
+	if !d.strict {
+		if err := d.d.Decode(f); err != nil {
+			return nil, err
+		}
+
+		return f, nil
+	}
+
+	var raw json.RawMessage
+	if err := d.d.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	// the feature has been read so the next one can still be
+	// decoded if this one is not valid.
+	i := d.index
+	d.index++
+
+	if err := validateStrict(raw, "Feature", index("features", i)); err != nil {
+		return nil, err
+	}
+
+	if err := f.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (d *Decoder) delim(expected json.Delim) error {
+	tok, err := d.d.Token()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	if tok != expected {
+		return fmt.Errorf("geojson: invalid feature collection: expected %v, got %v", expected, tok)
+	}
+
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// An Encoder writes the features of a feature collection to a stream,
+// one at a time. Close must be called to finish the collection.
+type Encoder struct {
+	w io.Writer
+
+	// BBox and ExtraMembers of the feature collection. They are written
+	// at the start of the collection so must be set before the first Encode.
+	BBox         BBox
+	ExtraMembers Properties
+
+	started bool
+	count   int
+	opts    *encodeOptions
+}
+
+// NewEncoder creates a new Encoder that writes to w.
+// The encode options are applied to the geometry of each feature.
+func NewEncoder(w io.Writer, opts ...EncodeOption) *Encoder {
+	return &Encoder{w: w, opts: newEncodeOptions(opts)}
+}
+
+// Encode writes the feature to the collection.
+func (e *Encoder) Encode(f *Feature) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	data, err := marshalFeature(f, e.opts)
+	if err != nil {
+		return err
+	}
+
+	if e.count > 0 {
+		data = append([]byte{','}, data...)
+	}
+	e.count++
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Close finishes the feature collection. It does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write([]byte(`]}`))
+	return err
+}
+
+// start writes the members of the collection and the start of the features array.
+func (e *Encoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	// the same members as FeatureCollection.MarshalJSON, without the features
+	// which are written last so they can be streamed.
+	tmp := make(map[string]interface{}, len(e.ExtraMembers)+2)
+	for k, v := range e.ExtraMembers {
+		tmp[k] = v
+	}
+
+	tmp["type"] = featureCollection
+	delete(tmp, "bbox")
+	delete(tmp, "features")
+	if e.BBox != nil {
+		tmp["bbox"] = e.BBox
+	}
+
+	data, err := marshalJSON(tmp)
+	if err != nil {
+		return err
+	}
+
+	data = append(data[:len(data)-1], `,"features":[`...)
+
+	_, err = e.w.Write(data)
+	return err
+}
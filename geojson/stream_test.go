@@ -0,0 +1,215 @@
+package geojson
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestDecoder(t *testing.T) {
+	rawJSON := `
+	  { "type": "FeatureCollection",
+	    "bbox": [100, 0, 105, 1],
+	    "name": "before",
+	    "features": [
+	      { "type": "Feature",
+	        "geometry": {"type": "Point", "coordinates": [102.0, 0.5]},
+	        "properties": {"prop0": "value0"}
+	      },
+	      { "type": "Feature",
+	        "geometry": {"type": "LineString", "coordinates": [[102.0, 0.0], [103.0, 1.0]]},
+	        "properties": {"prop0": "value1"}
+	      }
+	    ],
+	    "crs": {"type": "name"}
+	  }`
+
+	d := NewDecoder(strings.NewReader(rawJSON))
+
+	var features []*Feature
+	for {
+		f, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		features = append(features, f)
+
+		// members before the features are available right away
+		if !reflect.DeepEqual(d.BBox, BBox{100, 0, 105, 1}) {
+			t.Errorf("incorrect bbox: %v", d.BBox)
+		}
+
+		if v := d.ExtraMembers["name"]; v != "before" {
+			t.Errorf("incorrect extra member: %v", v)
+		}
+	}
+
+	if len(features) != 2 {
+		t.Fatalf("incorrect number of features: %d", len(features))
+	}
+
+	if !orb.Equal(features[0].Geometry, orb.Point{102, 0.5}) {
+		t.Errorf("incorrect geometry: %v", features[0].Geometry)
+	}
+
+	if v := features[1].Properties["prop0"]; v != "value1" {
+		t.Errorf("incorrect property: %v", v)
+	}
+
+	expected := Properties{"name": "before", "crs": map[string]interface{}{"type": "name"}}
+	if !reflect.DeepEqual(d.ExtraMembers, expected) {
+		t.Errorf("incorrect extra members: %v", d.ExtraMembers)
+	}
+
+	// should keep returning eof
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected eof: %v", err)
+	}
+}
+
+func TestDecoder_empty(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"features":[],"type":"FeatureCollection"}`))
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected eof: %v", err)
+	}
+}
+
+func TestDecoder_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not an object",
+			data: `[]`,
+		},
+		{
+			name: "wrong type",
+			data: `{"type":"Feature","features":[]}`,
+		},
+		{
+			name: "wrong type after features",
+			data: `{"features":[],"type":"Feature"}`,
+		},
+		{
+			name: "missing type",
+			data: `{"features":[]}`,
+		},
+		{
+			name: "features not an array",
+			data: `{"type":"FeatureCollection","features":{}}`,
+		},
+		{
+			name: "invalid feature",
+			data: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Foo"}}]}`,
+		},
+		{
+			name: "truncated",
+			data: `{"type":"FeatureCollection","features":[`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(tc.data))
+			for {
+				_, err := d.Decode()
+				if err == io.EOF {
+					t.Fatalf("expected error")
+				}
+
+				if err != nil {
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	e := NewEncoder(buf)
+	e.BBox = BBox{1, 2, 3, 4}
+	e.ExtraMembers = Properties{"name": "value", "features": "ignored"}
+
+	for i := 0; i < 3; i++ {
+		f := NewFeature(orb.Point{float64(i), 2})
+		if err := e.Encode(f); err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	expected := `{"bbox":[1,2,3,4],"name":"value","type":"FeatureCollection","features":[` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0,2]},"properties":null},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[2,2]},"properties":null}]}`
+	if buf.String() != expected {
+		t.Errorf("incorrect json: %s", buf.String())
+	}
+
+	// the result is a valid feature collection
+	fc, err := UnmarshalFeatureCollection(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(fc.Features) != 3 {
+		t.Errorf("incorrect number of features: %d", len(fc.Features))
+	}
+}
+
+func TestEncoder_empty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := NewEncoder(buf).Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	expected := `{"type":"FeatureCollection","features":[]}`
+	if buf.String() != expected {
+		t.Errorf("incorrect json: %s", buf.String())
+	}
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf)
+	for _, g := range orb.AllGeometries {
+		if g == nil {
+			continue
+		}
+
+		if err := e.Encode(NewFeature(g)); err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+
+	d := NewDecoder(buf)
+	for {
+		_, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+	}
+}
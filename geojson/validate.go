@@ -0,0 +1,402 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/paulmach/orb"
+)
+
+// A ValidationError is a violation of RFC 7946 found when decoding
+// with the Strict option.
+type ValidationError struct {
+	// Path is the location of the invalid value in the json,
+	// e.g. features[12].geometry.coordinates[0]
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return "geojson: " + e.Message
+	}
+
+	return "geojson: " + e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the violations of RFC 7946 found in the data.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "geojson: no validation errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
+
+// validateStrict checks the data is a valid RFC 7946 object of the given type,
+// one of "FeatureCollection", "Feature" or "Geometry". The path is the prefix
+// of the paths in the errors.
+func validateStrict(data []byte, typ, path string) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+
+	var val validator
+	switch typ {
+	case featureCollection:
+		val.featureCollection(v, path)
+	case "Feature":
+		val.feature(v, path)
+	default:
+		val.geometry(v, path)
+	}
+
+	if len(val.errs) > 0 {
+		return val.errs
+	}
+
+	return nil
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) featureCollection(value interface{}, path string) {
+	obj := v.object(value, path, featureCollection)
+	if obj == nil {
+		return
+	}
+
+	v.reserved(obj, path, "coordinates", "geometries", "geometry", "properties")
+	v.bbox(obj, path)
+
+	features, ok := obj["features"]
+	if !ok {
+		v.add(path, "missing features member")
+		return
+	}
+
+	list, ok := features.([]interface{})
+	if !ok {
+		v.add(member(path, "features"), "must be an array")
+		return
+	}
+
+	for i, f := range list {
+		v.feature(f, index(member(path, "features"), i))
+	}
+}
+
+func (v *validator) feature(value interface{}, path string) {
+	obj := v.object(value, path, "Feature")
+	if obj == nil {
+		return
+	}
+
+	v.reserved(obj, path, "coordinates", "geometries", "features")
+	v.bbox(obj, path)
+
+	if id, ok := obj["id"]; ok {
+		switch id.(type) {
+		case string, json.Number:
+		default:
+			v.add(member(path, "id"), "must be a string or number")
+		}
+	}
+
+	if g, ok := obj["geometry"]; !ok {
+		v.add(path, "missing geometry member")
+	} else if g != nil {
+		v.geometry(g, member(path, "geometry"))
+	}
+
+	if p, ok := obj["properties"]; !ok {
+		v.add(path, "missing properties member")
+	} else if _, ok := p.(map[string]interface{}); !ok && p != nil {
+		v.add(member(path, "properties"), "must be an object or null")
+	}
+}
+
+func (v *validator) geometry(value interface{}, path string) {
+	obj := v.object(value, path, "")
+	if obj == nil {
+		return
+	}
+
+	v.reserved(obj, path, "geometry", "properties", "features")
+	v.bbox(obj, path)
+
+	typ, _ := obj["type"].(string)
+	if typ == "GeometryCollection" {
+		v.reserved(obj, path, "coordinates")
+
+		geometries, ok := obj["geometries"].([]interface{})
+		if !ok {
+			v.add(member(path, "geometries"), "must be an array")
+			return
+		}
+
+		for i, g := range geometries {
+			v.geometry(g, index(member(path, "geometries"), i))
+		}
+		return
+	}
+
+	switch typ {
+	case "Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+	default:
+		v.add(member(path, "type"), "unknown geometry type %q", typ)
+		return
+	}
+
+	v.reserved(obj, path, "geometries")
+
+	path = member(path, "coordinates")
+	coords, ok := obj["coordinates"].([]interface{})
+	if !ok {
+		v.add(path, "must be an array")
+		return
+	}
+
+	// empty coordinates are allowed for all the types except points,
+	// they can be interpreted as null geometries.
+	if len(coords) == 0 && typ != "Point" {
+		return
+	}
+
+	switch typ {
+	case "Point":
+		v.position(coords, path)
+	case "MultiPoint":
+		v.positions(coords, path, 0)
+	case "LineString":
+		v.positions(coords, path, 2)
+	case "MultiLineString":
+		for i, ls := range coords {
+			v.positions(ls, index(path, i), 2)
+		}
+	case "Polygon":
+		v.polygon(coords, path)
+	case "MultiPolygon":
+		for i, p := range coords {
+			v.polygon(p, index(path, i))
+		}
+	}
+}
+
+func (v *validator) polygon(value interface{}, path string) {
+	rings, ok := value.([]interface{})
+	if !ok {
+		v.add(path, "must be an array of linear rings")
+		return
+	}
+
+	for i, r := range rings {
+		rpath := index(path, i)
+		ring := v.positions(r, rpath, 4)
+		if len(ring) < 4 {
+			continue
+		}
+
+		first, last := r.([]interface{})[0], r.([]interface{})[len(ring)-1]
+		if !equalPositions(first, last) {
+			v.add(rpath, "linear ring must be closed, the first and last positions must be the same")
+			continue
+		}
+
+		o := orb.Ring(ring).Orientation()
+		if i == 0 && o == orb.CW {
+			v.add(rpath, "exterior ring must be counterclockwise, the right-hand rule")
+		} else if i != 0 && o == orb.CCW {
+			v.add(rpath, "interior ring must be clockwise, the right-hand rule")
+		}
+	}
+}
+
+// positions validates an array of at least min positions and returns
+// the points if they are all valid.
+func (v *validator) positions(value interface{}, path string, min int) []orb.Point {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.add(path, "must be an array of positions")
+		return nil
+	}
+
+	if len(list) < min {
+		v.add(path, "must have at least %d positions", min)
+		return nil
+	}
+
+	valid := true
+	points := make([]orb.Point, 0, len(list))
+	for i, p := range list {
+		point, ok := v.position(p, index(path, i))
+		valid = valid && ok
+		points = append(points, point)
+	}
+
+	if !valid {
+		return nil
+	}
+
+	return points
+}
+
+func (v *validator) position(value interface{}, path string) (orb.Point, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.add(path, "position must be an array of numbers")
+		return orb.Point{}, false
+	}
+
+	if len(list) < 2 {
+		v.add(path, "position must have at least two numbers")
+		return orb.Point{}, false
+	}
+
+	var p orb.Point
+	for i, n := range list {
+		num, ok := n.(json.Number)
+		if !ok {
+			v.add(index(path, i), "must be a number")
+			return orb.Point{}, false
+		}
+
+		if i < 2 {
+			f, err := num.Float64()
+			if err != nil {
+				v.add(index(path, i), "must be a number")
+				return orb.Point{}, false
+			}
+			p[i] = f
+		}
+	}
+
+	return p, true
+}
+
+func (v *validator) bbox(obj map[string]interface{}, path string) {
+	value, ok := obj["bbox"]
+	if !ok {
+		return
+	}
+
+	path = member(path, "bbox")
+	list, ok := value.([]interface{})
+	if !ok || len(list) < 4 || len(list)%2 != 0 {
+		v.add(path, "must be an array of 2*n numbers")
+		return
+	}
+
+	values := make([]float64, 0, len(list))
+	for i, n := range list {
+		num, ok := n.(json.Number)
+		if !ok {
+			v.add(index(path, i), "must be a number")
+			return
+		}
+
+		f, err := num.Float64()
+		if err != nil {
+			v.add(index(path, i), "must be a number")
+			return
+		}
+		values = append(values, f)
+	}
+
+	// the west value can be more than the east when crossing the
+	// antimeridian, the other axes must be in order.
+	dims := len(values) / 2
+	for i := 1; i < dims; i++ {
+		if values[i] > values[dims+i] {
+			v.add(path, "minimum of axis %d is greater than the maximum", i)
+		}
+	}
+}
+
+// object checks the value is an object with the type member. If typ is
+// empty any type is allowed, it is checked by the caller.
+func (v *validator) object(value interface{}, path, typ string) map[string]interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		v.add(path, "must be an object")
+		return nil
+	}
+
+	t, ok := obj["type"]
+	if !ok {
+		v.add(path, "missing type member")
+		return nil
+	}
+
+	s, ok := t.(string)
+	if !ok {
+		v.add(member(path, "type"), "must be a string")
+		return nil
+	}
+
+	if typ != "" && s != typ {
+		v.add(member(path, "type"), "must be %q, not %q", typ, s)
+		return nil
+	}
+
+	return obj
+}
+
+// reserved reports members that have a meaning in other GeoJSON objects
+// and must not be used as foreign members.
+func (v *validator) reserved(obj map[string]interface{}, path string, keys ...string) {
+	for _, k := range keys {
+		if _, ok := obj[k]; ok {
+			v.add(member(path, k), "member not allowed in this object")
+		}
+	}
+}
+
+func equalPositions(a, b interface{}) bool {
+	la, lb := a.([]interface{}), b.([]interface{})
+	if len(la) != len(lb) {
+		return false
+	}
+
+	for i := range la {
+		fa, _ := la[i].(json.Number).Float64()
+		fb, _ := lb[i].(json.Number).Float64()
+		if fa != fb {
+			return false
+		}
+	}
+
+	return true
+}
+
+func member(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,256 @@
+package geojson
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestUnmarshalFeatureCollection_strict(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected ValidationErrors
+	}{
+		{
+			name: "valid",
+			data: `{"type":"FeatureCollection","bbox":[170,-10,-170,10],"features":[
+				{"type":"Feature","id":1,"geometry":null,"properties":null},
+				{"type":"Feature","geometry":{"type":"Polygon","coordinates":[
+					[[0,0],[10,0],[10,10],[0,10],[0,0]],
+					[[1,1],[1,2],[2,2],[1,1]]
+				]},"properties":{"a":1}},
+				{"type":"Feature","geometry":{"type":"LineString","coordinates":[]},"properties":{}}
+			]}`,
+		},
+		{
+			name: "not a feature collection",
+			data: `{"type":"Feature","geometry":null,"properties":null}`,
+			expected: ValidationErrors{
+				{Path: "type", Message: `must be "FeatureCollection", not "Feature"`},
+			},
+		},
+		{
+			name: "missing features",
+			data: `{"type":"FeatureCollection","properties":{}}`,
+			expected: ValidationErrors{
+				{Path: "properties", Message: "member not allowed in this object"},
+				{Path: "", Message: "missing features member"},
+			},
+		},
+		{
+			name: "feature members",
+			data: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","id":[1],"properties":[]},
+				{"type":"feature","geometry":null,"properties":null}
+			]}`,
+			expected: ValidationErrors{
+				{Path: "features[0].id", Message: "must be a string or number"},
+				{Path: "features[0]", Message: "missing geometry member"},
+				{Path: "features[0].properties", Message: "must be an object or null"},
+				{Path: "features[1].type", Message: `must be "Feature", not "feature"`},
+			},
+		},
+		{
+			name: "positions",
+			data: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","geometry":{"type":"MultiPoint","coordinates":[[1],[1,"2"],[1,2]]},"properties":null},
+				{"type":"Feature","geometry":{"type":"LineString","coordinates":[[1,2]]},"properties":null}
+			]}`,
+			expected: ValidationErrors{
+				{Path: "features[0].geometry.coordinates[0]", Message: "position must have at least two numbers"},
+				{Path: "features[0].geometry.coordinates[1][1]", Message: "must be a number"},
+				{Path: "features[1].geometry.coordinates", Message: "must have at least 2 positions"},
+			},
+		},
+		{
+			name: "rings",
+			data: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","geometry":{"type":"MultiPolygon","coordinates":[
+					[[[0,0],[0,10],[10,10],[10,0],[0,0]]],
+					[[[0,0],[10,0],[10,10],[0,10]], [[1,1],[2,1],[2,2]]],
+					[[[0,0],[10,0],[10,10],[0,10],[0,0]], [[1,1],[2,1],[2,2],[1,1]]]
+				]},"properties":null}
+			]}`,
+			expected: ValidationErrors{
+				{Path: "features[0].geometry.coordinates[0][0]", Message: "exterior ring must be counterclockwise, the right-hand rule"},
+				{Path: "features[0].geometry.coordinates[1][0]", Message: "linear ring must be closed, the first and last positions must be the same"},
+				{Path: "features[0].geometry.coordinates[1][1]", Message: "must have at least 4 positions"},
+				{Path: "features[0].geometry.coordinates[2][1]", Message: "interior ring must be clockwise, the right-hand rule"},
+			},
+		},
+		{
+			name: "geometries",
+			data: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[
+					{"type":"Arc","coordinates":[1,2]},
+					{"type":"Point","coordinates":null,"properties":{}}
+				]},"properties":null},
+				{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2],"bbox":[1,2,3]},"properties":null}
+			]}`,
+			expected: ValidationErrors{
+				{Path: "features[0].geometry.geometries[0].type", Message: `unknown geometry type "Arc"`},
+				{Path: "features[0].geometry.geometries[1].properties", Message: "member not allowed in this object"},
+				{Path: "features[0].geometry.geometries[1].coordinates", Message: "must be an array"},
+				{Path: "features[1].geometry.bbox", Message: "must be an array of 2*n numbers"},
+			},
+		},
+		{
+			name: "bbox order",
+			data: `{"type":"FeatureCollection","bbox":[0,10,1,0],"features":[]}`,
+			expected: ValidationErrors{
+				{Path: "bbox", Message: "minimum of axis 1 is greater than the maximum"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalFeatureCollection([]byte(tc.data), Strict())
+			if tc.expected == nil {
+				if err != nil {
+					t.Fatalf("should be valid: %v", err)
+				}
+				return
+			}
+
+			errs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("incorrect error type: %T: %v", err, err)
+			}
+
+			if !reflect.DeepEqual(errs, tc.expected) {
+				for _, e := range errs {
+					t.Logf("%v", e)
+				}
+				t.Errorf("incorrect errors")
+			}
+		})
+	}
+}
+
+func TestUnmarshalFeatureCollection_notStrict(t *testing.T) {
+	// without the option the data is decoded as before.
+	data := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[0,1],[1,1]]]}}
+	]}`
+
+	_, err := UnmarshalFeatureCollection([]byte(data))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	_, err = UnmarshalFeatureCollection([]byte(data), Strict())
+	if err == nil {
+		t.Fatalf("should return error in strict mode")
+	}
+
+	expected := "geojson: features[0].geometry.coordinates[0]: must have at least 4 positions (and 1 more errors)"
+	if err.Error() != expected {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestUnmarshalFeature_strict(t *testing.T) {
+	_, err := UnmarshalFeature([]byte(`{"type":"Feature","geometry":{"type":"Point","coordinates":[1]},"properties":null}`), Strict())
+
+	expected := ValidationErrors{
+		{Path: "geometry.coordinates", Message: "position must have at least two numbers"},
+	}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// invalid json
+	_, err = UnmarshalFeature([]byte(`{"type":`), Strict())
+	if err == nil {
+		t.Errorf("should return error for invalid json")
+	}
+}
+
+func TestUnmarshalGeometry_strict(t *testing.T) {
+	g, err := UnmarshalGeometry([]byte(`{"type":"Point","coordinates":[1,2,3]}`), Strict())
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if g.Type != "Point" {
+		t.Errorf("incorrect type: %v", g.Type)
+	}
+
+	_, err = UnmarshalGeometry([]byte(`{"type":"Point","coordinates":[1,2],"geometries":[]}`), Strict())
+
+	expected := "geojson: geometries: member not allowed in this object"
+	if err == nil || err.Error() != expected {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestDecoder_strict(t *testing.T) {
+	data := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1]},"properties":null}
+	]}`
+
+	d := NewDecoder(strings.NewReader(data), Strict())
+	if _, err := d.Decode(); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	_, err := d.Decode()
+	expected := ValidationErrors{
+		{Path: "features[1].geometry.coordinates", Message: "position must have at least two numbers"},
+	}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// not strict
+	d = NewDecoder(strings.NewReader(data))
+	for {
+		_, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+	}
+}
+
+func TestDecoder_strictContinue(t *testing.T) {
+	data := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1]},"properties":null},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[2]},"properties":null},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[3]},"properties":null},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}
+	]}`
+
+	d := NewDecoder(strings.NewReader(data), Strict())
+	for _, path := range []string{"features[0]", "features[1]", "features[2]"} {
+		_, err := d.Decode()
+		expected := ValidationErrors{
+			{Path: path + ".geometry.coordinates", Message: "position must have at least two numbers"},
+		}
+		if !reflect.DeepEqual(err, expected) {
+			t.Errorf("incorrect error: %v", err)
+		}
+	}
+
+	f, err := d.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if !orb.Equal(f.Geometry, orb.Point{1, 2}) {
+		t.Errorf("incorrect geometry: %v", f.Geometry)
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected EOF: %v", err)
+	}
+}
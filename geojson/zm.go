@@ -0,0 +1,132 @@
+package geojson
+
+import (
+	"errors"
+
+	"github.com/paulmach/orb"
+)
+
+// ErrInvalidZM is returned when marshalling a geometry, or feature, and the
+// number of extra values does not match the number of points and the layout.
+// This can happen if the geometry is changed after it was decoded.
+var ErrInvalidZM = errors.New("geojson: extra values do not match the geometry and layout")
+
+// NewGeometryZM will create a Geometry object, like NewGeometry, that
+// includes the z and/or m values as the third and fourth value of each position.
+// GeoJSON has no way of marking a measure so an XYM layout is encoded as XYZM
+// with a NaN z value, which is written as null.
+func NewGeometryZM(g orb.GeometryZM) *Geometry {
+	if g.Layout == orb.XY {
+		return NewGeometry(g.Geometry)
+	}
+
+	stride := g.Layout.Stride() - 2
+	switch c := g.Geometry.(type) {
+	case orb.Ring:
+		g.Geometry = orb.Polygon{c}
+	case orb.Bound:
+		// the bound's extra values are for the min and max points,
+		// repeat them for each corner of the polygon.
+		min, max := g.Extra[:stride], g.Extra[stride:2*stride]
+
+		extra := make([]float64, 0, 5*stride)
+		for _, v := range [][]float64{min, min, max, max, min} {
+			extra = append(extra, v...)
+		}
+
+		g.Geometry = c.ToPolygon()
+		g.Extra = extra
+	case orb.Collection:
+		jg := &Geometry{Type: c.GeoJSONType()}
+
+		offset := 0
+		for _, cg := range c {
+			n := orb.PointCount(cg) * stride
+			jg.Geometries = append(jg.Geometries, NewGeometryZM(orb.GeometryZM{
+				Geometry: cg,
+				Layout:   g.Layout,
+				Extra:    g.Extra[offset : offset+n],
+			}))
+			offset += n
+		}
+
+		return jg
+	}
+
+	jg := NewGeometry(g.Geometry)
+	jg.Layout = g.Layout
+	jg.Extra = g.Extra
+
+	return jg
+}
+
+// GeometryZM returns the geometry with its z and/or m values.
+// For a collection the layout will include any z or m values of the
+// child geometries, with missing values set to NaN.
+func (g Geometry) GeometryZM() orb.GeometryZM {
+	if g.Coordinates != nil {
+		return orb.GeometryZM{
+			Geometry: g.Coordinates,
+			Layout:   g.Layout,
+			Extra:    g.Extra,
+		}
+	}
+
+	children := make([]orb.GeometryZM, 0, len(g.Geometries))
+	hasZ, hasM := false, false
+	for _, geom := range g.Geometries {
+		cg := geom.GeometryZM()
+		hasZ = hasZ || cg.Layout.HasZ()
+		hasM = hasM || cg.Layout.HasM()
+		children = append(children, cg)
+	}
+
+	result := orb.GeometryZM{Layout: layout(hasZ, hasM)}
+
+	c := make(orb.Collection, 0, len(children))
+	for _, cg := range children {
+		c = append(c, cg.Geometry)
+
+		if result.Layout == orb.XY {
+			continue
+		}
+
+		for i := 0; i < orb.PointCount(cg.Geometry); i++ {
+			if result.Layout.HasZ() {
+				result.Extra = append(result.Extra, cg.Z(i))
+			}
+			if result.Layout.HasM() {
+				result.Extra = append(result.Extra, cg.M(i))
+			}
+		}
+	}
+	result.Geometry = c
+
+	return result
+}
+
+func layout(hasZ, hasM bool) orb.Layout {
+	switch {
+	case hasZ && hasM:
+		return orb.XYZM
+	case hasZ:
+		return orb.XYZ
+	case hasM:
+		return orb.XYM
+	}
+
+	return orb.XY
+}
+
+// validZM checks the extra values match the geometry. Other implementations
+// of orb.Geometry are marshalled with the json package and the extra values
+// are ignored.
+func validZM(g orb.GeometryZM) bool {
+	switch g.Geometry.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString,
+		orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound, orb.Collection:
+		return g.Valid()
+	}
+
+	return true
+}
@@ -0,0 +1,207 @@
+package geojson
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestGeometryZM_marshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		geom     orb.GeometryZM
+		expected string
+	}{
+		{
+			name:     "point z",
+			geom:     orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}},
+			expected: `{"type":"Point","coordinates":[1,2,3]}`,
+		},
+		{
+			name:     "line string zm",
+			geom:     orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}, Layout: orb.XYZM, Extra: []float64{5, 6, 7, 8}},
+			expected: `{"type":"LineString","coordinates":[[1,2,5,6],[3,4,7,8]]}`,
+		},
+		{
+			name:     "m only",
+			geom:     orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYM, Extra: []float64{3}},
+			expected: `{"type":"Point","coordinates":[1,2,null,3]}`,
+		},
+		{
+			name:     "ring",
+			geom:     orb.GeometryZM{Geometry: orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}}, Layout: orb.XYZ, Extra: []float64{1, 2, 3, 1}},
+			expected: `{"type":"Polygon","coordinates":[[[0,0,1],[1,0,2],[1,1,3],[0,0,1]]]}`,
+		},
+		{
+			name: "collection",
+			geom: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.MultiPoint{{3, 4}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{5, 6},
+			},
+			expected: `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2,5]},{"type":"MultiPoint","coordinates":[[3,4,6]]}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(NewGeometryZM(tc.geom))
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if string(data) != tc.expected {
+				t.Errorf("incorrect json")
+				t.Logf("%v", string(data))
+				t.Logf("%v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestGeometryZM_unmarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected orb.GeometryZM
+	}{
+		{
+			name:     "point z",
+			data:     `{"type":"Point","coordinates":[1,2,3]}`,
+			expected: orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}},
+		},
+		{
+			name: "polygon z",
+			data: `{"type":"Polygon","coordinates":[[[0,0,1],[1,0,2],[1,1,3],[0,0,1]]]}`,
+			expected: orb.GeometryZM{
+				Geometry: orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{1, 2, 3, 1},
+			},
+		},
+		{
+			name:     "multi point zm",
+			data:     `{"type":"MultiPoint","coordinates":[[1,2,3,4],[5,6,7,8]]}`,
+			expected: orb.GeometryZM{Geometry: orb.MultiPoint{{1, 2}, {5, 6}}, Layout: orb.XYZM, Extra: []float64{3, 4, 7, 8}},
+		},
+		{
+			name: "collection",
+			data: `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2,5]},{"type":"MultiPoint","coordinates":[[3,4,6]]}]}`,
+			expected: orb.GeometryZM{
+				Geometry: orb.Collection{orb.Point{1, 2}, orb.MultiPoint{{3, 4}}},
+				Layout:   orb.XYZ,
+				Extra:    []float64{5, 6},
+			},
+		},
+		{
+			name:     "xy",
+			data:     `{"type":"LineString","coordinates":[[1,2],[3,4]]}`,
+			expected: orb.GeometryZM{Geometry: orb.LineString{{1, 2}, {3, 4}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := UnmarshalGeometry([]byte(tc.data))
+			if err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+
+			if v := g.GeometryZM(); !reflect.DeepEqual(v, tc.expected) {
+				t.Errorf("incorrect geometry")
+				t.Logf("%v", v)
+				t.Logf("%v", tc.expected)
+			}
+
+			// should round trip
+			data, err := json.Marshal(g)
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			if string(data) != tc.data {
+				t.Errorf("incorrect json: %v", string(data))
+			}
+		})
+	}
+}
+
+func TestGeometryZM_unmarshalMissing(t *testing.T) {
+	g, err := UnmarshalGeometry([]byte(`{"type":"LineString","coordinates":[[1,2],[3,4,5]]}`))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if g.Layout != orb.XYZ {
+		t.Errorf("incorrect layout: %v", g.Layout)
+	}
+
+	if !math.IsNaN(g.Extra[0]) || g.Extra[1] != 5 {
+		t.Errorf("incorrect extra: %v", g.Extra)
+	}
+}
+
+func TestFeatureZM(t *testing.T) {
+	f := NewFeatureZM(orb.GeometryZM{Geometry: orb.Point{1, 2}, Layout: orb.XYZ, Extra: []float64{3}})
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2,3]},"properties":null}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+
+	nf, err := UnmarshalFeature(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if g := nf.GeometryZM(); !reflect.DeepEqual(g, f.GeometryZM()) {
+		t.Errorf("incorrect geometry: %v", g)
+	}
+}
+
+func TestFeatureZM_invalid(t *testing.T) {
+	f, err := UnmarshalFeature([]byte(`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2,3]},"properties":null}`))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	// the geometry is changed but the z values are not
+	f.Geometry = orb.LineString{{1, 2}, {3, 4}, {5, 6}}
+	if _, err := json.Marshal(f); err == nil {
+		t.Errorf("expected error")
+	}
+
+	if _, err := f.MarshalJSON(); err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	f.Geometry = orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}
+	if _, err := f.MarshalJSON(); err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	g := &Geometry{Coordinates: orb.Collection{orb.Point{1, 2}, orb.Point{3, 4}}, Layout: orb.XYM, Extra: []float64{1}}
+	if _, err := g.MarshalJSON(); err != ErrInvalidZM {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// extra values match again
+	f.Geometry = orb.Point{3, 4}
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4,3]},"properties":null}`
+	if string(data) != expected {
+		t.Errorf("incorrect json: %v", string(data))
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/clip"
 	"github.com/paulmach/orb/maptile"
 )
 
 // Geometry returns the covering set of tiles for the given geometry.
+// Bounds with Min longitude greater than Max longitude wrap around the
+// antimeridian, for other geometries see GeometryAntimeridian.
 func Geometry(g orb.Geometry, z maptile.Zoom) maptile.Set {
 	if g == nil {
 		return nil
@@ -46,6 +49,19 @@ func Point(ll orb.Point, z maptile.Zoom) maptile.Set {
 	}
 }
 
+// GeometryAntimeridian returns the covering set of tiles for the geometry
+// after splitting it where it crosses the antimeridian, see clip.Antimeridian.
+// Segments where the longitude changes by more than 180 degrees, e.g. from
+// 179 to -179, are taken the shorter way around so they do not cover the
+// whole world.
+func GeometryAntimeridian(g orb.Geometry, z maptile.Zoom) maptile.Set {
+	if g == nil {
+		return nil
+	}
+
+	return wrap(Geometry(clip.Antimeridian(g), z), z)
+}
+
 // MultiPoint creates a tile cover for the set of points,
 func MultiPoint(mp orb.MultiPoint, z maptile.Zoom) maptile.Set {
 	set := make(maptile.Set)
@@ -57,8 +73,18 @@ func MultiPoint(mp orb.MultiPoint, z maptile.Zoom) maptile.Set {
 }
 
 // Bound creates a tile cover for the bound. i.e. all the tiles
-// that intersect the bound.
+// that intersect the bound. A bound with Min longitude greater than
+// Max longitude wraps around the antimeridian.
 func Bound(b orb.Bound, z maptile.Zoom) maptile.Set {
+	if b.Min[0] > b.Max[0] {
+		west := orb.Bound{Min: b.Min, Max: orb.Point{180, b.Max[1]}}
+		east := orb.Bound{Min: orb.Point{-180, b.Min[1]}, Max: b.Max}
+
+		set := Bound(west, z)
+		set.Merge(Bound(east, z))
+		return wrap(set, z)
+	}
+
 	lo := maptile.At(b.Min, z)
 	hi := maptile.At(b.Max, z)
 
@@ -83,3 +109,17 @@ func Collection(c orb.Collection, z maptile.Zoom) maptile.Set {
 
 	return set
 }
+
+// wrap moves tiles just past 180 degrees longitude, from parts
+// that end on the antimeridian, to the other side of the world.
+func wrap(set maptile.Set, z maptile.Zoom) maptile.Set {
+	max := uint32(1) << uint32(z)
+	for t := range set {
+		if t.X >= max {
+			delete(set, t)
+			set[maptile.Tile{X: t.X - max, Y: t.Y, Z: t.Z}] = true
+		}
+	}
+
+	return set
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
 )
 
 func TestGeometry(t *testing.T) {
@@ -11,3 +12,75 @@ func TestGeometry(t *testing.T) {
 		Geometry(g, 1)
 	}
 }
+
+func TestGeometry_antimeridian(t *testing.T) {
+	z := maptile.Zoom(6)
+
+	west := orb.Polygon{{{177, -19}, {180, -19}, {180, -16}, {177, -16}, {177, -19}}}
+	east := orb.Polygon{{{-180, -19}, {-179, -19}, {-179, -16}, {-180, -16}, {-180, -19}}}
+
+	expected := Geometry(west, z)
+	expected.Merge(Geometry(east, z))
+	expected = wrap(expected, z)
+
+	cases := []struct {
+		name string
+		geom orb.Geometry
+	}{
+		{
+			name: "polygon",
+			geom: orb.Polygon{{{177, -19}, {-179, -19}, {-179, -16}, {177, -16}, {177, -19}}},
+		},
+		{
+			name: "wrapping bound",
+			geom: orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{-179, -16}},
+		},
+		{
+			name: "collection",
+			geom: orb.Collection{
+				orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{-179, -16}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tiles := GeometryAntimeridian(tc.geom, z)
+			if len(tiles) != len(expected) {
+				t.Errorf("incorrect number of tiles: %v != %v", len(tiles), len(expected))
+			}
+
+			for tile := range tiles {
+				if !tile.Valid() {
+					t.Errorf("invalid tile: %v", tile)
+				}
+
+				if !expected[tile] {
+					t.Errorf("unexpected tile: %v", tile)
+				}
+			}
+		})
+	}
+
+	// the line should only cover a few tiles, not the whole world.
+	ls := orb.LineString{{179.5, 0.5}, {-179.5, 0.5}}
+	tiles := GeometryAntimeridian(ls, z)
+	if len(tiles) != 2 {
+		t.Errorf("incorrect line tiles: %v", tiles)
+	}
+
+	// explicitly wrapping bounds are handled by default
+	b := orb.Bound{Min: orb.Point{177, -19}, Max: orb.Point{-179, -16}}
+	if tiles := Geometry(b, z); len(tiles) != len(expected) {
+		t.Errorf("incorrect number of bound tiles: %v != %v", len(tiles), len(expected))
+	}
+
+	// long segments are not split by default
+	ls = orb.LineString{{-100, 0.5}, {100, 0.5}}
+	tiles = Geometry(ls, 2)
+	for x := uint32(0); x < 4; x++ {
+		if !tiles[maptile.New(x, 1, 2)] {
+			t.Errorf("missing tile x=%d: %v", x, tiles)
+		}
+	}
+}
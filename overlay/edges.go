@@ -0,0 +1,349 @@
+package overlay
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// An edge is a directed segment of a ring with the interior of
+// its polygon on the left.
+type edge struct {
+	a, b orb.Point
+}
+
+func (e edge) reversed() edge {
+	return edge{a: e.b, b: e.a}
+}
+
+func (e edge) midpoint() orb.Point {
+	return orb.Point{(e.a[0] + e.b[0]) / 2, (e.a[1] + e.b[1]) / 2}
+}
+
+// polygons returns the areal parts of the geometry. Points and
+// lines have no area and are ignored.
+func polygons(g orb.Geometry) orb.MultiPolygon {
+	switch g := g.(type) {
+	case nil:
+		return nil
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
+		return nil
+	case orb.Ring:
+		return orb.MultiPolygon{{g}}
+	case orb.Polygon:
+		return orb.MultiPolygon{g}
+	case orb.MultiPolygon:
+		return g
+	case orb.Bound:
+		return orb.MultiPolygon{g.ToPolygon()}
+	case orb.Collection:
+		var mp orb.MultiPolygon
+		for _, c := range g {
+			mp = append(mp, polygons(c)...)
+		}
+		return mp
+	}
+
+	return nil
+}
+
+// normalize returns a copy of the polygons with closed rings,
+// no repeated points and the outer rings counter-clockwise and the holes
+// clockwise. Degenerate rings with no area are removed.
+func normalize(mp orb.MultiPolygon) orb.MultiPolygon {
+	var result orb.MultiPolygon
+	for _, p := range mp {
+		if len(p) == 0 {
+			continue
+		}
+
+		outer := normalizeRing(p[0], orb.CCW)
+		if outer == nil {
+			continue
+		}
+
+		np := orb.Polygon{outer}
+		for _, r := range p[1:] {
+			if h := normalizeRing(r, orb.CW); h != nil {
+				np = append(np, h)
+			}
+		}
+
+		result = append(result, np)
+	}
+
+	return result
+}
+
+func normalizeRing(r orb.Ring, o orb.Orientation) orb.Ring {
+	if len(r) == 0 {
+		return nil
+	}
+
+	nr := make(orb.Ring, 0, len(r)+1)
+	for _, p := range r {
+		if len(nr) == 0 || nr[len(nr)-1] != p {
+			nr = append(nr, p)
+		}
+	}
+
+	if nr[0] != nr[len(nr)-1] {
+		nr = append(nr, nr[0])
+	}
+
+	if len(nr) < 4 {
+		return nil
+	}
+
+	ro := nr.Orientation()
+	if ro == 0 {
+		return nil
+	}
+
+	if ro != o {
+		nr.Reverse()
+	}
+
+	return nr
+}
+
+func ringEdges(mp orb.MultiPolygon) []edge {
+	var edges []edge
+	for _, p := range mp {
+		for _, r := range p {
+			for i := 0; i < len(r)-1; i++ {
+				edges = append(edges, edge{a: r[i], b: r[i+1]})
+			}
+		}
+	}
+
+	return edges
+}
+
+// tolerance returns the distance under which two points are considered
+// the same. It is relative to the size of the input so it works for
+// lon/lat as well as projected data.
+func tolerance(b orb.Bound) float64 {
+	size := math.Max(b.Max[0]-b.Min[0], b.Max[1]-b.Min[1])
+	mag := math.Max(
+		math.Max(math.Abs(b.Min[0]), math.Abs(b.Max[0])),
+		math.Max(math.Abs(b.Min[1]), math.Abs(b.Max[1])),
+	)
+
+	if size == 0 && mag == 0 {
+		return 1e-12
+	}
+
+	return 1e-12 * math.Max(size, mag)
+}
+
+// A snapper makes sure that points within the tolerance of an already
+// seen point are replaced with that point. This makes the edges
+// created during splitting match up exactly.
+type snapper struct {
+	tol   float64
+	cells map[[2]int64][]orb.Point
+}
+
+func newSnapper(tol float64) *snapper {
+	return &snapper{
+		tol:   tol,
+		cells: make(map[[2]int64][]orb.Point),
+	}
+}
+
+func (s *snapper) cell(p orb.Point) [2]int64 {
+	return [2]int64{
+		int64(math.Floor(p[0] / s.tol)),
+		int64(math.Floor(p[1] / s.tol)),
+	}
+}
+
+func (s *snapper) snap(p orb.Point) orb.Point {
+	c := s.cell(p)
+	for x := c[0] - 1; x <= c[0]+1; x++ {
+		for y := c[1] - 1; y <= c[1]+1; y++ {
+			for _, q := range s.cells[[2]int64{x, y}] {
+				if math.Abs(p[0]-q[0]) <= s.tol && math.Abs(p[1]-q[1]) <= s.tol {
+					return q
+				}
+			}
+		}
+	}
+
+	s.cells[c] = append(s.cells[c], p)
+	return p
+}
+
+// split splits all the edges at the points where they intersect or touch
+// any other edge. The returned edges only meet at their end points.
+// The index of the input edge each result came from is also returned.
+func split(edges []edge, tol float64) ([]edge, []int) {
+	s := newSnapper(tol)
+	for i := range edges {
+		edges[i].a = s.snap(edges[i].a)
+		edges[i].b = s.snap(edges[i].b)
+	}
+
+	// sort by min x so we only need to compare edges that overlap in x.
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Min(edges[order[i]].a[0], edges[order[i]].b[0]) <
+			math.Min(edges[order[j]].a[0], edges[order[j]].b[0])
+	})
+
+	points := make([][]orb.Point, len(edges))
+	for oi, i := range order {
+		e1 := edges[i]
+		maxX := math.Max(e1.a[0], e1.b[0])
+		for _, j := range order[oi+1:] {
+			e2 := edges[j]
+			if math.Min(e2.a[0], e2.b[0]) > maxX+tol {
+				break
+			}
+
+			for _, p := range intersections(e1, e2, tol) {
+				p = s.snap(p)
+				if p != e1.a && p != e1.b {
+					points[i] = append(points[i], p)
+				}
+				if p != e2.a && p != e2.b {
+					points[j] = append(points[j], p)
+				}
+			}
+		}
+	}
+
+	result := make([]edge, 0, len(edges))
+	source := make([]int, 0, len(edges))
+	for i, e := range edges {
+		if len(points[i]) == 0 {
+			if e.a != e.b {
+				result = append(result, e)
+				source = append(source, i)
+			}
+			continue
+		}
+
+		ps := append(points[i], e.a, e.b)
+		dx, dy := e.b[0]-e.a[0], e.b[1]-e.a[1]
+		sort.Slice(ps, func(i, j int) bool {
+			return (ps[i][0]-e.a[0])*dx+(ps[i][1]-e.a[1])*dy <
+				(ps[j][0]-e.a[0])*dx+(ps[j][1]-e.a[1])*dy
+		})
+
+		for k := 1; k < len(ps); k++ {
+			if ps[k-1] != ps[k] {
+				result = append(result, edge{a: ps[k-1], b: ps[k]})
+				source = append(source, i)
+			}
+		}
+	}
+
+	return result, source
+}
+
+// intersections returns the points where the two edges meet.
+// If the edges are collinear and overlap, the end points
+// of the overlap are returned.
+func intersections(e1, e2 edge, tol float64) []orb.Point {
+	if !segmentBound(e1).Pad(tol).Intersects(segmentBound(e2)) {
+		return nil
+	}
+
+	var result []orb.Point
+
+	// end points touching the other edge are used as is
+	// to avoid any precision loss.
+	if onSegment(e2, e1.a, tol) {
+		result = append(result, e1.a)
+	}
+	if onSegment(e2, e1.b, tol) {
+		result = append(result, e1.b)
+	}
+	if onSegment(e1, e2.a, tol) {
+		result = append(result, e2.a)
+	}
+	if onSegment(e1, e2.b, tol) {
+		result = append(result, e2.b)
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	r := orb.Point{e1.b[0] - e1.a[0], e1.b[1] - e1.a[1]}
+	s := orb.Point{e2.b[0] - e2.a[0], e2.b[1] - e2.a[1]}
+
+	d := cross(r, s)
+	if d == 0 {
+		// parallel and not touching
+		return nil
+	}
+
+	qp := orb.Point{e2.a[0] - e1.a[0], e2.a[1] - e1.a[1]}
+	t := cross(qp, s) / d
+	u := cross(qp, r) / d
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return nil
+	}
+
+	return []orb.Point{{e1.a[0] + t*r[0], e1.a[1] + t*r[1]}}
+}
+
+// onSegment returns true if the point is within tolerance of the edge.
+func onSegment(e edge, p orb.Point, tol float64) bool {
+	x, y := e.a[0], e.a[1]
+	dx, dy := e.b[0]-x, e.b[1]-y
+
+	if dx != 0 || dy != 0 {
+		t := ((p[0]-x)*dx + (p[1]-y)*dy) / (dx*dx + dy*dy)
+		if t > 1 {
+			x, y = e.b[0], e.b[1]
+		} else if t > 0 {
+			x += dx * t
+			y += dy * t
+		}
+	}
+
+	dx, dy = p[0]-x, p[1]-y
+	return dx*dx+dy*dy <= tol*tol
+}
+
+func segmentBound(e edge) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{math.Min(e.a[0], e.b[0]), math.Min(e.a[1], e.b[1])},
+		Max: orb.Point{math.Max(e.a[0], e.b[0]), math.Max(e.a[1], e.b[1])},
+	}
+}
+
+func cross(a, b orb.Point) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+// dedupe removes repeated edges. Edges that appear in both directions
+// cancel each other out since they are interior to the area,
+// e.g. polygons in a multi-polygon that share an edge.
+func dedupe(edges []edge) []edge {
+	present := make(map[edge]bool, len(edges))
+	for _, e := range edges {
+		present[e] = true
+	}
+
+	used := make(map[edge]bool, len(edges))
+	result := make([]edge, 0, len(edges))
+	for _, e := range edges {
+		if used[e] || present[e.reversed()] {
+			continue
+		}
+
+		used[e] = true
+		result = append(result, e)
+	}
+
+	return result
+}
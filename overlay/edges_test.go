@@ -0,0 +1,118 @@
+package overlay
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestNormalize(t *testing.T) {
+	mp := normalize(orb.MultiPolygon{
+		{
+			{{0, 0}, {0, 4}, {4, 4}, {4, 0}},
+			{{1, 1}, {2, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}},
+			{{3, 3}, {3, 3}, {3, 3}},
+		},
+		{{{5, 5}, {6, 5}, {7, 5}, {5, 5}}},
+	})
+
+	expected := orb.MultiPolygon{
+		{
+			{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+			{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}},
+		},
+	}
+
+	if !mp.Equal(expected) {
+		t.Errorf("incorrect normalization")
+		t.Logf("%v", mp)
+		t.Logf("%v", expected)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		name   string
+		edges  []edge
+		result []edge
+	}{
+		{
+			name: "crossing",
+			edges: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{2, 2}},
+				{a: orb.Point{0, 2}, b: orb.Point{2, 0}},
+			},
+			result: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{1, 1}},
+				{a: orb.Point{1, 1}, b: orb.Point{2, 2}},
+				{a: orb.Point{0, 2}, b: orb.Point{1, 1}},
+				{a: orb.Point{1, 1}, b: orb.Point{2, 0}},
+			},
+		},
+		{
+			name: "touching",
+			edges: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{2, 0}},
+				{a: orb.Point{1, 0}, b: orb.Point{1, 1}},
+			},
+			result: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+				{a: orb.Point{1, 0}, b: orb.Point{2, 0}},
+				{a: orb.Point{1, 0}, b: orb.Point{1, 1}},
+			},
+		},
+		{
+			name: "collinear overlap",
+			edges: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{2, 0}},
+				{a: orb.Point{3, 0}, b: orb.Point{1, 0}},
+			},
+			result: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+				{a: orb.Point{1, 0}, b: orb.Point{2, 0}},
+				{a: orb.Point{3, 0}, b: orb.Point{2, 0}},
+				{a: orb.Point{2, 0}, b: orb.Point{1, 0}},
+			},
+		},
+		{
+			name: "disjoint",
+			edges: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+				{a: orb.Point{0, 1}, b: orb.Point{1, 1}},
+			},
+			result: []edge{
+				{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+				{a: orb.Point{0, 1}, b: orb.Point{1, 1}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _ := split(tc.edges, 1e-12)
+			if len(result) != len(tc.result) {
+				t.Fatalf("incorrect number of edges: %v", result)
+			}
+
+			for i := range result {
+				if result[i] != tc.result[i] {
+					t.Errorf("edge %d: %v != %v", i, result[i], tc.result[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	edges := dedupe([]edge{
+		{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+		{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+		{a: orb.Point{1, 0}, b: orb.Point{1, 1}},
+		{a: orb.Point{1, 1}, b: orb.Point{1, 0}},
+	})
+
+	expected := []edge{{a: orb.Point{0, 0}, b: orb.Point{1, 0}}}
+	if len(edges) != 1 || edges[0] != expected[0] {
+		t.Errorf("incorrect edges: %v", edges)
+	}
+}
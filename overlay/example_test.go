@@ -0,0 +1,29 @@
+package overlay_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+func ExampleIntersection() {
+	parcel := orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}
+	floodZone := orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}
+
+	flooded := overlay.Intersection(parcel, floodZone)
+
+	fmt.Println(planar.Area(flooded))
+	// Output:
+	// 1
+}
+
+func ExampleUnion() {
+	p1 := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	p2 := orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+
+	fmt.Println(overlay.Union(p1, p2))
+	// Output:
+	// [[[[0 0] [1 0] [2 0] [2 1] [1 1] [0 1] [0 0]]]]
+}
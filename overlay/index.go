@@ -0,0 +1,123 @@
+package overlay
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// A pointIndex answers point in polygon queries without checking every
+// edge. The edges are put into bands along the longer axis of the bound
+// and a ray is cast across the band, so only the edges in the band of the
+// point are checked. The polygons must not overlap, a point is inside if
+// the ray crosses an odd number of edges.
+type pointIndex struct {
+	swap   bool // bands are along x, the coordinates are swapped
+	min    float64
+	width  float64
+	bands  [][]edge
+	bounds orb.Bound
+}
+
+func newPointIndex(mp orb.MultiPolygon) *pointIndex {
+	edges := ringEdges(mp)
+	if len(edges) == 0 {
+		return &pointIndex{}
+	}
+
+	bound := mp.Bound()
+	idx := &pointIndex{
+		swap:   bound.Max[0]-bound.Min[0] > bound.Max[1]-bound.Min[1],
+		bounds: bound,
+	}
+
+	// the bands are about as wide as an average edge so each
+	// edge is in a few bands and each band has a few edges.
+	var extent float64
+	for i, e := range edges {
+		e = idx.transform(e)
+		edges[i] = e
+		extent += math.Abs(e.b[1] - e.a[1])
+	}
+
+	lo, hi := bound.Min[1], bound.Max[1]
+	if idx.swap {
+		lo, hi = bound.Min[0], bound.Max[0]
+	}
+
+	n := len(edges)
+	if extent > 0 {
+		n = int(math.Min(float64(n), math.Ceil((hi-lo)*float64(len(edges))/extent)))
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	idx.min = lo
+	idx.width = (hi - lo) / float64(n)
+	idx.bands = make([][]edge, n)
+	for _, e := range edges {
+		first := idx.band(math.Min(e.a[1], e.b[1]))
+		last := idx.band(math.Max(e.a[1], e.b[1]))
+		for i := first; i <= last; i++ {
+			idx.bands[i] = append(idx.bands[i], e)
+		}
+	}
+
+	return idx
+}
+
+// transform swaps the coordinates so the bands are always along y.
+func (idx *pointIndex) transform(e edge) edge {
+	if !idx.swap {
+		return e
+	}
+
+	return edge{
+		a: orb.Point{e.a[1], e.a[0]},
+		b: orb.Point{e.b[1], e.b[0]},
+	}
+}
+
+func (idx *pointIndex) band(y float64) int {
+	if idx.width == 0 {
+		return 0
+	}
+
+	i := int((y - idx.min) / idx.width)
+	if i < 0 {
+		return 0
+	}
+
+	if i >= len(idx.bands) {
+		return len(idx.bands) - 1
+	}
+
+	return i
+}
+
+// contains returns true if the point is inside the polygons. Points on the
+// boundary may be inside or outside.
+func (idx *pointIndex) contains(p orb.Point) bool {
+	if len(idx.bands) == 0 || !idx.bounds.Contains(p) {
+		return false
+	}
+
+	if idx.swap {
+		p = orb.Point{p[1], p[0]}
+	}
+
+	inside := false
+	for _, e := range idx.bands[idx.band(p[1])] {
+		if (e.a[1] > p[1]) == (e.b[1] > p[1]) {
+			continue
+		}
+
+		x := e.a[0] + (p[1]-e.a[1])*(e.b[0]-e.a[0])/(e.b[1]-e.a[1])
+		if x > p[0] {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,48 @@
+package overlay
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestPointIndex(t *testing.T) {
+	cases := []struct {
+		name  string
+		input orb.MultiPolygon
+	}{
+		{
+			name: "polygon with hole",
+			input: orb.MultiPolygon{{
+				{{0, 0}, {10, 0}, {10, 4}, {0, 4}, {0, 0}},
+				{{2, 1}, {2, 3}, {8, 3}, {8, 1}, {2, 1}},
+			}},
+		},
+		{
+			name: "tall polygons",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 10}, {0, 10}, {0, 0}}},
+				{{{2, 0}, {3, 5}, {2, 10}, {2, 0}}},
+			},
+		},
+		{
+			name:  "empty",
+			input: nil,
+		},
+	}
+
+	r := rand.New(rand.NewSource(42))
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			idx := newPointIndex(tc.input)
+			for i := 0; i < 1000; i++ {
+				p := orb.Point{12*r.Float64() - 1, 12*r.Float64() - 1}
+				if v := idx.contains(p); v != planar.MultiPolygonContains(tc.input, p) {
+					t.Errorf("incorrect for %v: %v", p, v)
+				}
+			}
+		})
+	}
+}
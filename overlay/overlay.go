@@ -0,0 +1,263 @@
+// Package overlay computes boolean operations, such as union and
+// intersection, between polygonal geometries in the 2d plane.
+package overlay
+
+import (
+	"github.com/paulmach/orb"
+)
+
+type operation int
+
+const (
+	union operation = iota
+	intersection
+	difference
+	symDifference
+)
+
+// Union returns the area covered by the subject, the clipping or both.
+// Only the 2d parts of the geometries are considered, points and lines are ignored.
+func Union(subject, clipping orb.Geometry) orb.MultiPolygon {
+	return compute(union, subject, clipping)
+}
+
+// UnionAll returns the area covered by any of the multi-polygons. They are
+// merged pairwise so each union works on similar sized inputs, this is much
+// faster than merging them one at a time.
+func UnionAll(mps []orb.MultiPolygon) orb.MultiPolygon {
+	var nonEmpty []orb.MultiPolygon
+	for _, mp := range mps {
+		if len(mp) > 0 {
+			nonEmpty = append(nonEmpty, mp)
+		}
+	}
+
+	switch len(nonEmpty) {
+	case 0:
+		return nil
+	case 1:
+		return Union(nonEmpty[0], nil)
+	}
+
+	for len(nonEmpty) > 1 {
+		next := make([]orb.MultiPolygon, 0, (len(nonEmpty)+1)/2)
+		for i := 0; i < len(nonEmpty); i += 2 {
+			if i+1 == len(nonEmpty) {
+				next = append(next, nonEmpty[i])
+				continue
+			}
+
+			next = append(next, Union(nonEmpty[i], nonEmpty[i+1]))
+		}
+		nonEmpty = next
+	}
+
+	return nonEmpty[0]
+}
+
+// Intersection returns the area covered by both the subject and the clipping.
+// Only the 2d parts of the geometries are considered, points and lines are ignored.
+func Intersection(subject, clipping orb.Geometry) orb.MultiPolygon {
+	return compute(intersection, subject, clipping)
+}
+
+// Difference returns the area of the subject not covered by the clipping.
+// Only the 2d parts of the geometries are considered, points and lines are ignored.
+func Difference(subject, clipping orb.Geometry) orb.MultiPolygon {
+	return compute(difference, subject, clipping)
+}
+
+// SymDifference returns the area covered by either the subject or
+// the clipping but not by both, i.e. the exclusive or.
+// Only the 2d parts of the geometries are considered, points and lines are ignored.
+func SymDifference(subject, clipping orb.Geometry) orb.MultiPolygon {
+	return compute(symDifference, subject, clipping)
+}
+
+// compute works by splitting the boundaries of both inputs where they
+// meet, after the members of each are dissolved, keeping the pieces that bound the result and then linking them
+// back up into rings. The returned polygons have counter-clockwise outer
+// rings and clockwise inner rings, as recommended by the GeoJSON spec.
+func compute(op operation, subject, clipping orb.Geometry) orb.MultiPolygon {
+	a := dissolve(normalize(polygons(subject)))
+	b := dissolve(normalize(polygons(clipping)))
+
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return nil
+	case op == intersection && (len(a) == 0 || len(b) == 0):
+		return nil
+	case op == difference && len(a) == 0:
+		return nil
+	case op == intersection && !a.Bound().Intersects(b.Bound()):
+		return nil
+	}
+
+	ea := dedupe(ringEdges(a))
+	eb := dedupe(ringEdges(b))
+
+	var bound orb.Bound
+	switch {
+	case len(a) == 0:
+		bound = b.Bound()
+	case len(b) == 0:
+		bound = a.Bound()
+	default:
+		bound = a.Bound().Union(b.Bound())
+	}
+
+	all := make([]edge, 0, len(ea)+len(eb))
+	all = append(all, ea...)
+	all = append(all, eb...)
+	edges, source := split(all, tolerance(bound))
+
+	inA := make(map[edge]bool, len(edges))
+	inB := make(map[edge]bool, len(edges))
+	for i, e := range edges {
+		if source[i] < len(ea) {
+			inA[e] = true
+		} else {
+			inB[e] = true
+		}
+	}
+
+	indexA, indexB := newPointIndex(a), newPointIndex(b)
+
+	var result []edge
+	for i, e := range edges {
+		fromA := source[i] < len(ea)
+
+		other, otherSet := indexB, inB
+		if !fromA {
+			other, otherSet = indexA, inA
+		}
+
+		switch {
+		case otherSet[e]:
+			// shared edge with the area on the same side,
+			// only consider it once.
+			if fromA && (op == union || op == intersection) {
+				result = append(result, e)
+			}
+		case otherSet[e.reversed()]:
+			// shared edge with the areas on opposite sides.
+			if fromA && op == difference {
+				result = append(result, e)
+			}
+		case other.contains(e.midpoint()):
+			switch {
+			case op == intersection:
+				result = append(result, e)
+			case op == difference && !fromA:
+				result = append(result, e.reversed())
+			case op == symDifference:
+				result = append(result, e.reversed())
+			}
+		default:
+			switch {
+			case op == union:
+				result = append(result, e)
+			case op == difference && fromA:
+				result = append(result, e)
+			case op == symDifference:
+				result = append(result, e)
+			}
+		}
+	}
+
+	return assemble(chain(dedupe(result)))
+}
+
+// dissolve merges the overlapping members of the multi-polygon so the
+// edges of each input to compute only bound its area once. The edges of
+// every member are split where they meet and the ones inside another
+// member, or shared in opposite directions, are removed.
+func dissolve(mp orb.MultiPolygon) orb.MultiPolygon {
+	bounds := make([]orb.Bound, len(mp))
+	for i, p := range mp {
+		bounds[i] = p.Bound()
+	}
+
+	overlaps := false
+	for i := range bounds {
+		for j := i + 1; j < len(bounds) && !overlaps; j++ {
+			overlaps = bounds[i].Intersects(bounds[j])
+		}
+	}
+
+	if !overlaps {
+		return mp
+	}
+
+	var (
+		all    []edge
+		member []int
+	)
+	for i, p := range mp {
+		for _, e := range ringEdges(orb.MultiPolygon{p}) {
+			all = append(all, e)
+			member = append(member, i)
+		}
+	}
+
+	edges, source := split(all, tolerance(mp.Bound()))
+
+	// the members with each edge, in order
+	members := make(map[edge][]int, len(edges))
+	for i, e := range edges {
+		m := member[source[i]]
+		if ms := members[e]; len(ms) == 0 || ms[len(ms)-1] != m {
+			members[e] = append(ms, m)
+		}
+	}
+
+	indexes := make([]*pointIndex, len(mp))
+
+	var result []edge
+	for i, e := range edges {
+		m := member[source[i]]
+		if len(members[e.reversed()]) > 0 {
+			// the area is on both sides
+			continue
+		}
+
+		shared := members[e]
+		if shared[0] != m {
+			// shared with the area on the same side, only keep it once
+			continue
+		}
+
+		inside := false
+		mid := e.midpoint()
+		for j := range mp {
+			if j == m || !bounds[j].Contains(mid) || hasMember(shared, j) {
+				continue
+			}
+
+			if indexes[j] == nil {
+				indexes[j] = newPointIndex(orb.MultiPolygon{mp[j]})
+			}
+
+			if indexes[j].contains(mid) {
+				inside = true
+				break
+			}
+		}
+
+		if !inside {
+			result = append(result, e)
+		}
+	}
+
+	return assemble(chain(dedupe(result)))
+}
+
+func hasMember(members []int, m int) bool {
+	for _, i := range members {
+		if i == m {
+			return true
+		}
+	}
+
+	return false
+}
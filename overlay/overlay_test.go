@@ -0,0 +1,391 @@
+package overlay
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestOverlay(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Union(g, g)
+		Intersection(g, g)
+		Difference(g, g)
+		SymDifference(g, g)
+	}
+}
+
+func TestOverlay_Area(t *testing.T) {
+	square := func(x, y, s float64) orb.Polygon {
+		return orb.Polygon{{{x, y}, {x + s, y}, {x + s, y + s}, {x, y + s}, {x, y}}}
+	}
+
+	cases := []struct {
+		name          string
+		subject       orb.Geometry
+		clipping      orb.Geometry
+		union         float64
+		intersection  float64
+		difference    float64
+		symDifference float64
+	}{
+		{
+			name:          "overlapping squares",
+			subject:       square(0, 0, 2),
+			clipping:      square(1, 1, 2),
+			union:         7,
+			intersection:  1,
+			difference:    3,
+			symDifference: 6,
+		},
+		{
+			name:          "disjoint squares",
+			subject:       square(0, 0, 1),
+			clipping:      square(5, 5, 1),
+			union:         2,
+			intersection:  0,
+			difference:    1,
+			symDifference: 2,
+		},
+		{
+			name:          "shared edge",
+			subject:       square(0, 0, 1),
+			clipping:      square(1, 0, 1),
+			union:         2,
+			intersection:  0,
+			difference:    1,
+			symDifference: 2,
+		},
+		{
+			name:          "touching corner",
+			subject:       square(0, 0, 1),
+			clipping:      square(1, 1, 1),
+			union:         2,
+			intersection:  0,
+			difference:    1,
+			symDifference: 2,
+		},
+		{
+			name:          "same polygon",
+			subject:       square(0, 0, 2),
+			clipping:      square(0, 0, 2),
+			union:         4,
+			intersection:  4,
+			difference:    0,
+			symDifference: 0,
+		},
+		{
+			name:          "contained",
+			subject:       square(0, 0, 4),
+			clipping:      square(1, 1, 2),
+			union:         16,
+			intersection:  4,
+			difference:    12,
+			symDifference: 12,
+		},
+		{
+			name:          "contained sharing an edge",
+			subject:       square(0, 0, 4),
+			clipping:      square(0, 1, 2),
+			union:         16,
+			intersection:  4,
+			difference:    12,
+			symDifference: 12,
+		},
+		{
+			name: "polygon with hole",
+			subject: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}},
+			},
+			clipping:      square(2, 0, 4),
+			union:         12 + 16 - 6,
+			intersection:  6,
+			difference:    12 - 6,
+			symDifference: 12 + 16 - 2*6,
+		},
+		{
+			name:          "clockwise input",
+			subject:       orb.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}},
+			clipping:      square(1, 1, 2),
+			union:         7,
+			intersection:  1,
+			difference:    3,
+			symDifference: 6,
+		},
+		{
+			name:          "crossing triangles",
+			subject:       orb.Polygon{{{0, 0}, {4, 0}, {2, 4}, {0, 0}}},
+			clipping:      orb.Polygon{{{0, 3}, {2, -1}, {4, 3}, {0, 3}}},
+			union:         10.75,
+			intersection:  5.25,
+			difference:    2.75,
+			symDifference: 5.5,
+		},
+		{
+			name:          "bounds",
+			subject:       orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}},
+			clipping:      orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{3, 3}},
+			union:         7,
+			intersection:  1,
+			difference:    3,
+			symDifference: 6,
+		},
+		{
+			name:          "overlapping members",
+			subject:       orb.MultiPolygon{square(0, 0, 2), square(1, 1, 2)},
+			clipping:      square(0, 0, 3),
+			union:         9,
+			intersection:  7,
+			difference:    0,
+			symDifference: 2,
+		},
+		{
+			name:          "overlapping members in both",
+			subject:       orb.MultiPolygon{square(0, 0, 2), square(1, 1, 2)},
+			clipping:      orb.MultiPolygon{square(0, 0, 2), square(0, 0, 2), square(2, 0, 1)},
+			union:         8,
+			intersection:  4,
+			difference:    3,
+			symDifference: 4,
+		},
+		{
+			name: "member in a hole",
+			subject: orb.MultiPolygon{
+				{
+					{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+					{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}},
+				},
+				square(0, 2, 2),
+			},
+			clipping:      orb.MultiPolygon{},
+			union:         13,
+			intersection:  0,
+			difference:    13,
+			symDifference: 13,
+		},
+		{
+			name:          "empty clipping",
+			subject:       square(0, 0, 2),
+			clipping:      orb.MultiPolygon{},
+			union:         4,
+			intersection:  0,
+			difference:    4,
+			symDifference: 4,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := func(name string, mp orb.MultiPolygon, expected float64) {
+				t.Helper()
+				if a := planar.Area(mp); math.Abs(a-expected) > 1e-9 {
+					t.Errorf("%s: incorrect area: %v != %v", name, a, expected)
+					t.Logf("%v", mp)
+				}
+
+				checkOrientation(t, mp)
+			}
+
+			check("union", Union(tc.subject, tc.clipping), tc.union)
+			check("intersection", Intersection(tc.subject, tc.clipping), tc.intersection)
+			check("difference", Difference(tc.subject, tc.clipping), tc.difference)
+			check("sym difference", SymDifference(tc.subject, tc.clipping), tc.symDifference)
+		})
+	}
+}
+
+func TestUnion(t *testing.T) {
+	t.Run("shared edge merges", func(t *testing.T) {
+		mp := Union(
+			orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+		)
+
+		if len(mp) != 1 || len(mp[0]) != 1 {
+			t.Errorf("should be one polygon without holes: %v", mp)
+		}
+	})
+
+	t.Run("ring around creates hole", func(t *testing.T) {
+		u := Union(
+			orb.Polygon{{{0, 0}, {3, 0}, {3, 1}, {0, 1}, {0, 0}}},
+			orb.MultiPolygon{
+				{{{0, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 1}}},
+				{{{2, 1}, {3, 1}, {3, 3}, {2, 3}, {2, 1}}},
+				{{{0, 3}, {3, 3}, {3, 4}, {0, 4}, {0, 3}}},
+			},
+		)
+
+		if len(u) != 1 || len(u[0]) != 2 {
+			t.Fatalf("should be one polygon with a hole: %v", u)
+		}
+
+		if a := planar.Area(u); a != 10 {
+			t.Errorf("incorrect area: %v", a)
+		}
+	})
+
+	t.Run("does not modify input", func(t *testing.T) {
+		p := orb.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}}
+		c := p.Clone()
+		Union(p, orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}})
+
+		if !p.Equal(c) {
+			t.Errorf("input modified: %v", p)
+		}
+	})
+}
+
+func TestUnionAll(t *testing.T) {
+	var squares []orb.MultiPolygon
+	for i := 0; i < 5; i++ {
+		x := float64(i)
+		squares = append(squares, orb.MultiPolygon{{{{x, 0}, {x + 1, 0}, {x + 1, 1}, {x, 1}, {x, 0}}}}, nil)
+	}
+
+	u := UnionAll(squares)
+	if len(u) != 1 || len(u[0]) != 1 {
+		t.Fatalf("should be one polygon without holes: %v", u)
+	}
+
+	if a := planar.Area(u); a != 5 {
+		t.Errorf("incorrect area: %v", a)
+	}
+
+	// a single input is normalized
+	u = UnionAll([]orb.MultiPolygon{{{{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}}}})
+	if o := u[0][0].Orientation(); o != orb.CCW {
+		t.Errorf("outer ring should be counter-clockwise: %v", o)
+	}
+
+	if u := UnionAll(nil); u != nil {
+		t.Errorf("should be nil: %v", u)
+	}
+}
+
+func TestUnion_overlappingMembers(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+	}
+
+	for name, u := range map[string]orb.MultiPolygon{
+		"union":     Union(mp, nil),
+		"union all": UnionAll([]orb.MultiPolygon{mp}),
+	} {
+		if len(u) != 1 || len(u[0]) != 1 || len(u[0][0]) != 9 {
+			t.Errorf("%s: should be one polygon without holes: %v", name, u)
+		}
+
+		if a := planar.Area(u); a != 7 {
+			t.Errorf("%s: incorrect area: %v", name, a)
+		}
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	mp := Intersection(
+		orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+	)
+
+	expected := orb.MultiPolygon{{{{2, 1}, {2, 2}, {1, 2}, {1, 1}, {2, 1}}}}
+	if !mp.Equal(expected) {
+		t.Errorf("incorrect result")
+		t.Logf("%v", mp)
+		t.Logf("%v", expected)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	t.Run("creates hole", func(t *testing.T) {
+		mp := Difference(
+			orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+			orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+		)
+
+		if len(mp) != 1 || len(mp[0]) != 2 {
+			t.Fatalf("should be one polygon with a hole: %v", mp)
+		}
+	})
+
+	t.Run("splits into two", func(t *testing.T) {
+		mp := Difference(
+			orb.Polygon{{{0, 0}, {3, 0}, {3, 1}, {0, 1}, {0, 0}}},
+			orb.Polygon{{{1, -1}, {2, -1}, {2, 2}, {1, 2}, {1, -1}}},
+		)
+
+		if len(mp) != 2 {
+			t.Fatalf("should be two polygons: %v", mp)
+		}
+	})
+}
+
+func TestOverlay_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	star := func(c orb.Point) orb.Polygon {
+		ring := orb.Ring{}
+		n := 5 + r.Intn(20)
+		for i := 0; i < n; i++ {
+			a := 2 * math.Pi * float64(i) / float64(n)
+			d := 1 + 4*r.Float64()
+			ring = append(ring, orb.Point{c[0] + d*math.Cos(a), c[1] + d*math.Sin(a)})
+		}
+		ring = append(ring, ring[0])
+
+		return orb.Polygon{ring}
+	}
+
+	for i := 0; i < 200; i++ {
+		a := star(orb.Point{r.Float64() * 4, r.Float64() * 4})
+		b := star(orb.Point{r.Float64() * 4, r.Float64() * 4})
+
+		aa, ab := planar.Area(a), planar.Area(b)
+		u := planar.Area(Union(a, b))
+		in := planar.Area(Intersection(a, b))
+		d := planar.Area(Difference(a, b))
+		x := planar.Area(SymDifference(a, b))
+
+		if v := u + in; math.Abs(v-aa-ab) > 1e-6 {
+			t.Errorf("%d: union + intersection != a + b: %v != %v", i, v, aa+ab)
+		}
+
+		if math.Abs(d-(aa-in)) > 1e-6 {
+			t.Errorf("%d: difference != a - intersection: %v != %v", i, d, aa-in)
+		}
+
+		if math.Abs(x-(u-in)) > 1e-6 {
+			t.Errorf("%d: sym difference != union - intersection: %v != %v", i, x, u-in)
+		}
+
+		// the same with the two as members of one multi-polygon
+		if v := planar.Area(Union(orb.MultiPolygon{a, b}, nil)); math.Abs(v-u) > 1e-6 {
+			t.Errorf("%d: union of members != union: %v != %v", i, v, u)
+		}
+	}
+}
+
+func checkOrientation(t testing.TB, mp orb.MultiPolygon) {
+	t.Helper()
+
+	for i, p := range mp {
+		for j, r := range p {
+			if !r.Closed() {
+				t.Errorf("polygon %d ring %d: not closed", i, j)
+			}
+
+			o := orb.CCW
+			if j > 0 {
+				o = orb.CW
+			}
+
+			if r.Orientation() != o {
+				t.Errorf("polygon %d ring %d: incorrect orientation", i, j)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package overlay
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// chain links the edges into closed rings. Where more than one edge
+// leaves a vertex the one making the sharpest left turn is taken. This keeps
+// the area on the left and splits rings that touch at a point, e.g. a hole
+// touching the outer ring, into separate rings.
+func chain(edges []edge) []orb.Ring {
+	outgoing := make(map[orb.Point][]int, len(edges))
+	for i, e := range edges {
+		outgoing[e.a] = append(outgoing[e.a], i)
+	}
+
+	used := make([]bool, len(edges))
+
+	var rings []orb.Ring
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+
+		used[i] = true
+		start := edges[i].a
+		r := orb.Ring{start}
+
+		current := edges[i]
+		for {
+			r = append(r, current.b)
+			if current.b == start {
+				break
+			}
+
+			next := nextEdge(edges, outgoing[current.b], used, current)
+			if next == -1 {
+				// dead end, should not happen with valid splitting.
+				r = nil
+				break
+			}
+
+			used[next] = true
+			current = edges[next]
+		}
+
+		if len(r) >= 4 {
+			rings = append(rings, r)
+		}
+	}
+
+	return rings
+}
+
+// nextEdge returns the unused outgoing edge with the smallest clockwise
+// angle from the reverse of the incoming edge.
+func nextEdge(edges []edge, candidates []int, used []bool, in edge) int {
+	back := orb.Point{in.a[0] - in.b[0], in.a[1] - in.b[1]}
+
+	best := -1
+	bestAngle := math.Inf(1)
+	for _, c := range candidates {
+		if used[c] {
+			continue
+		}
+
+		out := orb.Point{edges[c].b[0] - edges[c].a[0], edges[c].b[1] - edges[c].a[1]}
+		angle := -math.Atan2(cross(back, out), back[0]*out[0]+back[1]*out[1])
+		if angle <= 0 {
+			angle += 2 * math.Pi
+		}
+
+		if angle < bestAngle {
+			best = c
+			bestAngle = angle
+		}
+	}
+
+	return best
+}
+
+// assemble groups the rings into polygons. Counter-clockwise rings are
+// the outer rings and clockwise rings are holes placed in the smallest
+// outer ring that contains them.
+func assemble(rings []orb.Ring) orb.MultiPolygon {
+	type shell struct {
+		polygon orb.Polygon
+		area    float64
+	}
+
+	var (
+		shells []*shell
+		holes  []orb.Ring
+	)
+
+	for _, r := range rings {
+		switch r.Orientation() {
+		case orb.CCW:
+			shells = append(shells, &shell{
+				polygon: orb.Polygon{r},
+				area:    math.Abs(planar.Area(r)),
+			})
+		case orb.CW:
+			holes = append(holes, r)
+		}
+	}
+
+	// smallest first so the first match is the tightest.
+	bySize := make([]*shell, len(shells))
+	copy(bySize, shells)
+	sort.SliceStable(bySize, func(i, j int) bool {
+		return bySize[i].area < bySize[j].area
+	})
+
+	for _, h := range holes {
+		p := edge{a: h[0], b: h[1]}.midpoint()
+		for _, s := range bySize {
+			if planar.RingContains(s.polygon[0], p) {
+				s.polygon = append(s.polygon, h)
+				break
+			}
+		}
+	}
+
+	if len(shells) == 0 {
+		return nil
+	}
+
+	result := make(orb.MultiPolygon, 0, len(shells))
+	for _, s := range shells {
+		result = append(result, s.polygon)
+	}
+
+	return result
+}
@@ -0,0 +1,58 @@
+package overlay
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestChain(t *testing.T) {
+	t.Run("touching at a point", func(t *testing.T) {
+		// two squares touching at (1, 1)
+		edges := append(
+			ringEdges(orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}),
+			ringEdges(orb.MultiPolygon{{{{1, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}}}})...,
+		)
+
+		rings := chain(edges)
+		if len(rings) != 2 {
+			t.Fatalf("should have two rings: %v", rings)
+		}
+
+		for _, r := range rings {
+			if len(r) != 5 {
+				t.Errorf("incorrect ring: %v", r)
+			}
+		}
+	})
+
+	t.Run("dead end", func(t *testing.T) {
+		rings := chain([]edge{
+			{a: orb.Point{0, 0}, b: orb.Point{1, 0}},
+			{a: orb.Point{1, 0}, b: orb.Point{1, 1}},
+		})
+
+		if len(rings) != 0 {
+			t.Errorf("should not have rings: %v", rings)
+		}
+	})
+}
+
+func TestAssemble(t *testing.T) {
+	outer := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	hole := orb.Ring{{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}}
+	island := orb.Ring{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}
+	islandHole := orb.Ring{{3, 3}, {3, 7}, {7, 7}, {7, 3}, {3, 3}}
+
+	mp := assemble([]orb.Ring{islandHole, hole, outer, island})
+	expected := orb.MultiPolygon{
+		{outer, hole},
+		{island, islandHole},
+	}
+
+	if !mp.Equal(expected) {
+		t.Errorf("incorrect polygons")
+		t.Logf("%v", mp)
+		t.Logf("%v", expected)
+	}
+}
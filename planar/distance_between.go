@@ -0,0 +1,240 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// DistanceBetween returns the minimum distance between the two geometries
+// along with the closest point on each. The distance is zero if the geometries
+// intersect, this includes one being inside a polygon of the other.
+// If either geometry is empty the distance is +Inf.
+func DistanceBetween(g1, g2 orb.Geometry) (float64, orb.Point, orb.Point) {
+	s1 := boundarySegments(g1, nil, 0)
+	s2 := boundarySegments(g2, nil, 0)
+	if len(s1) == 0 || len(s2) == 0 {
+		return math.Inf(1), orb.Point{}, orb.Point{}
+	}
+
+	// if one is inside the other the boundaries may not touch.
+	if p, ok := inside(areaParts(g1, nil), s2); ok {
+		return 0, p, p
+	}
+
+	if p, ok := inside(areaParts(g2, nil), s1); ok {
+		return 0, p, p
+	}
+
+	best := math.Inf(1)
+	var p1, p2 orb.Point
+	for i := range s1 {
+		b1 := segmentBound(s1[i])
+		for j := range s2 {
+			if boundDistanceSquared(b1, segmentBound(s2[j])) >= best {
+				continue
+			}
+
+			d, a, b := segmentsClosest(s1[i].a, s1[i].b, s2[j].a, s2[j].b)
+			if d < best {
+				best, p1, p2 = d, a, b
+				if d == 0 {
+					return 0, p1, p2
+				}
+			}
+		}
+	}
+
+	return math.Sqrt(best), p1, p2
+}
+
+// HausdorffDistance returns the largest distance from a vertex of one
+// geometry to the closest point on the other. It's a measure of how similar
+// the shapes are, for example a GPS trace and the planned route. This is the
+// discrete Hausdorff distance, only the vertices are checked, so long segments
+// should be densified for a more accurate result.
+// If either geometry is empty the distance is +Inf.
+func HausdorffDistance(g1, g2 orb.Geometry) float64 {
+	s1 := boundarySegments(g1, nil, 0)
+	s2 := boundarySegments(g2, nil, 0)
+	if len(s1) == 0 || len(s2) == 0 {
+		return math.Inf(1)
+	}
+
+	return math.Sqrt(math.Max(directedHausdorff(s1, s2), directedHausdorff(s2, s1)))
+}
+
+func directedHausdorff(from, to []segment) float64 {
+	max := 0.0
+	check := func(p orb.Point) {
+		min := math.Inf(1)
+		for _, s := range to {
+			if d := DistanceFromSegmentSquared(s.a, s.b, p); d < min {
+				min = d
+				if min <= max {
+					// can't increase the max
+					return
+				}
+			}
+		}
+
+		if min > max {
+			max = min
+		}
+	}
+
+	for _, s := range from {
+		check(s.a)
+		check(s.b)
+	}
+
+	return max
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the two lines.
+// Unlike the Hausdorff distance this takes into account the direction and order
+// of the points, it is the shortest leash needed to walk a dog where the person
+// walks along one line and the dog the other, neither can go backwards.
+// If either line is empty the distance is +Inf.
+func FrechetDistance(ls1, ls2 orb.LineString) float64 {
+	return math.Sqrt(FrechetDistanceWith(ls1, ls2, DistanceSquared))
+}
+
+// FrechetDistanceWith returns the discrete Fréchet distance between the two
+// lines using the distance function, e.g. geo.Distance for lon/lat lines.
+// It uses the dynamic programming approach of Eiter and Mannila, only keeping
+// the previous row of the table. If either line is empty the distance is +Inf.
+func FrechetDistanceWith(ls1, ls2 orb.LineString, df orb.DistanceFunc) float64 {
+	if len(ls1) == 0 || len(ls2) == 0 {
+		return math.Inf(1)
+	}
+
+	prev := make([]float64, len(ls2))
+	curr := make([]float64, len(ls2))
+	for i := range ls1 {
+		for j := range ls2 {
+			d := df(ls1[i], ls2[j])
+			switch {
+			case i == 0 && j == 0:
+				curr[j] = d
+			case i == 0:
+				curr[j] = math.Max(curr[j-1], d)
+			case j == 0:
+				curr[j] = math.Max(prev[j], d)
+			default:
+				curr[j] = math.Max(math.Min(math.Min(prev[j], prev[j-1]), curr[j-1]), d)
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(ls2)-1]
+}
+
+// inside returns a point of the segments that is inside the polygons.
+func inside(mp orb.MultiPolygon, segs []segment) (orb.Point, bool) {
+	if len(mp) == 0 {
+		return orb.Point{}, false
+	}
+
+	b := mp.Bound()
+	for _, s := range segs {
+		if b.Contains(s.a) && MultiPolygonContains(mp, s.a) {
+			return s.a, true
+		}
+	}
+
+	return orb.Point{}, false
+}
+
+// segmentsClosest returns the squared distance between the segments
+// [a, b] and [c, d] along with the closest point on each.
+func segmentsClosest(a, b, c, d orb.Point) (float64, orb.Point, orb.Point) {
+	if points := SegmentIntersections(a, b, c, d); len(points) > 0 {
+		return 0, points[0], points[0]
+	}
+
+	best := math.Inf(1)
+	var p1, p2 orb.Point
+	check := func(p, q orb.Point) {
+		if d := DistanceSquared(p, q); d < best {
+			best, p1, p2 = d, p, q
+		}
+	}
+
+	check(a, closestOnSegment(c, d, a))
+	check(b, closestOnSegment(c, d, b))
+	check(closestOnSegment(a, b, c), c)
+	check(closestOnSegment(a, b, d), d)
+
+	return best, p1, p2
+}
+
+// closestOnSegment returns the point on the segment [a, b] closest to p.
+func closestOnSegment(a, b, p orb.Point) orb.Point {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return a
+	}
+
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	switch {
+	case t <= 0:
+		return a
+	case t >= 1:
+		return b
+	}
+
+	return orb.Point{a[0] + t*dx, a[1] + t*dy}
+}
+
+func segmentBound(s segment) orb.Bound {
+	return orb.Bound{Min: s.a, Max: s.a}.Extend(s.b)
+}
+
+// boundDistanceSquared returns the squared distance between the bounds,
+// zero if they intersect.
+func boundDistanceSquared(b1, b2 orb.Bound) float64 {
+	dx := math.Max(0, math.Max(b1.Min[0]-b2.Max[0], b2.Min[0]-b1.Max[0]))
+	dy := math.Max(0, math.Max(b1.Min[1]-b2.Max[1], b2.Min[1]-b1.Max[1]))
+
+	return dx*dx + dy*dy
+}
+
+// areaParts appends the polygons of the geometry.
+func areaParts(g orb.Geometry, mp orb.MultiPolygon) orb.MultiPolygon {
+	switch g := g.(type) {
+	case nil, orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
+		return mp
+	case orb.Ring:
+		return areaParts(orb.Polygon{g}, mp)
+	case orb.Polygon:
+		if len(g) == 0 || len(g[0]) == 0 {
+			return mp
+		}
+
+		// empty holes can't contain anything.
+		p := orb.Polygon{g[0]}
+		for _, r := range g[1:] {
+			if len(r) > 0 {
+				p = append(p, r)
+			}
+		}
+		return append(mp, p)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			mp = areaParts(p, mp)
+		}
+		return mp
+	case orb.Collection:
+		for _, c := range g {
+			mp = areaParts(c, mp)
+		}
+		return mp
+	case orb.Bound:
+		return append(mp, g.ToPolygon())
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
@@ -0,0 +1,233 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestDistanceBetween(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		for _, o := range orb.AllGeometries {
+			DistanceBetween(g, o)
+		}
+	}
+
+	square := orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}
+
+	cases := []struct {
+		name     string
+		g1, g2   orb.Geometry
+		distance float64
+		p1, p2   orb.Point
+	}{
+		{
+			name:     "points",
+			g1:       orb.Point{0, 0},
+			g2:       orb.Point{3, 4},
+			distance: 5,
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{3, 4},
+		},
+		{
+			name:     "point and line",
+			g1:       orb.Point{1, 1},
+			g2:       orb.LineString{{0, 0}, {2, 0}, {2, 2}},
+			distance: 1,
+			p1:       orb.Point{1, 1},
+			p2:       orb.Point{1, 0},
+		},
+		{
+			name:     "parallel lines",
+			g1:       orb.LineString{{0, 0}, {4, 0}},
+			g2:       orb.LineString{{1, 2}, {3, 2}},
+			distance: 2,
+			p1:       orb.Point{1, 0},
+			p2:       orb.Point{1, 2},
+		},
+		{
+			name:     "crossing lines",
+			g1:       orb.LineString{{0, 0}, {2, 2}},
+			g2:       orb.LineString{{0, 2}, {2, 0}},
+			distance: 0,
+			p1:       orb.Point{1, 1},
+			p2:       orb.Point{1, 1},
+		},
+		{
+			name:     "line inside polygon",
+			g1:       square,
+			g2:       orb.LineString{{1, 1}, {2, 2}},
+			distance: 0,
+			p1:       orb.Point{1, 1},
+			p2:       orb.Point{1, 1},
+		},
+		{
+			name:     "polygon inside polygon",
+			g1:       orb.Polygon{{{1, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}}},
+			g2:       square,
+			distance: 0,
+			p1:       orb.Point{1, 1},
+			p2:       orb.Point{1, 1},
+		},
+		{
+			name: "point in hole",
+			g1: orb.Polygon{
+				square[0],
+				{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+			},
+			g2:       orb.Point{2, 1.5},
+			distance: 0.5,
+			p1:       orb.Point{2, 1},
+			p2:       orb.Point{2, 1.5},
+		},
+		{
+			name:     "polygons",
+			g1:       square,
+			g2:       orb.MultiPolygon{{{{6, 5}, {7, 5}, {7, 6}, {6, 5}}}, {{{5, 1}, {6, 1}, {6, 2}, {5, 1}}}},
+			distance: 1,
+			p1:       orb.Point{4, 1},
+			p2:       orb.Point{5, 1},
+		},
+		{
+			name:     "collection",
+			g1:       orb.Collection{orb.Point{10, 10}, orb.LineString{{0, 6}, {4, 7}}},
+			g2:       square,
+			distance: 2,
+			p1:       orb.Point{0, 6},
+			p2:       orb.Point{0, 4},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, p1, p2 := DistanceBetween(tc.g1, tc.g2)
+			if math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if !p1.Equal(tc.p1) || !p2.Equal(tc.p2) {
+				t.Errorf("incorrect points: %v %v != %v %v", p1, p2, tc.p1, tc.p2)
+			}
+
+			// should be symmetric
+			d, p2, p1 = DistanceBetween(tc.g2, tc.g1)
+			if math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect reverse distance: %v != %v", d, tc.distance)
+			}
+
+			if math.Abs(Distance(p1, p2)-tc.distance) > epsilon {
+				t.Errorf("incorrect reverse points: %v %v", p1, p2)
+			}
+		})
+	}
+}
+
+func TestDistanceBetween_Empty(t *testing.T) {
+	d, _, _ := DistanceBetween(orb.LineString{}, orb.Point{1, 1})
+	if !math.IsInf(d, 1) {
+		t.Errorf("expected +Inf, got %v", d)
+	}
+}
+
+func TestHausdorffDistance(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		HausdorffDistance(g, g)
+	}
+
+	cases := []struct {
+		name     string
+		g1, g2   orb.Geometry
+		distance float64
+	}{
+		{
+			name:     "same line",
+			g1:       orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			g2:       orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			distance: 0,
+		},
+		{
+			name:     "offset line",
+			g1:       orb.LineString{{0, 0}, {4, 0}},
+			g2:       orb.LineString{{0, 1}, {4, 1}},
+			distance: 1,
+		},
+		{
+			name:     "detour",
+			g1:       orb.LineString{{0, 0}, {4, 0}},
+			g2:       orb.LineString{{0, 0}, {2, 3}, {4, 0}},
+			distance: 3,
+		},
+		{
+			name:     "shorter line",
+			g1:       orb.LineString{{0, 0}, {10, 0}},
+			g2:       orb.LineString{{0, 0}, {4, 0}},
+			distance: 6,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if d := HausdorffDistance(tc.g1, tc.g2); math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if d := HausdorffDistance(tc.g2, tc.g1); math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect reverse distance: %v != %v", d, tc.distance)
+			}
+		})
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	cases := []struct {
+		name     string
+		ls1, ls2 orb.LineString
+		distance float64
+	}{
+		{
+			name:     "same line",
+			ls1:      orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			ls2:      orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+			distance: 0,
+		},
+		{
+			name:     "offset line",
+			ls1:      orb.LineString{{0, 0}, {2, 0}, {4, 0}},
+			ls2:      orb.LineString{{0, 1}, {2, 1}, {4, 1}},
+			distance: 1,
+		},
+		{
+			name:     "reversed line",
+			ls1:      orb.LineString{{0, 0}, {2, 0}, {4, 0}},
+			ls2:      orb.LineString{{4, 0}, {2, 0}, {0, 0}},
+			distance: 4,
+		},
+		{
+			name:     "empty",
+			ls1:      orb.LineString{{0, 0}},
+			ls2:      orb.LineString{},
+			distance: math.Inf(1),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := FrechetDistance(tc.ls1, tc.ls2)
+			if d != tc.distance && math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			d = FrechetDistanceWith(tc.ls1, tc.ls2, Distance)
+			if d != tc.distance && math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect distance with func: %v != %v", d, tc.distance)
+			}
+		})
+	}
+
+	// the reversed line has the same Hausdorff distance
+	ls := orb.LineString{{0, 0}, {2, 0}, {4, 0}}
+	if d := HausdorffDistance(ls, orb.LineString{{4, 0}, {2, 0}, {0, 0}}); d != 0 {
+		t.Errorf("incorrect hausdorff distance: %v", d)
+	}
+}
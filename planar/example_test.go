@@ -44,3 +44,32 @@ func ExampleLength() {
 	// Output:
 	// 12
 }
+
+func ExampleConvexHull() {
+	mp := orb.MultiPoint{{0, 0}, {1, 1}, {2, 0}, {2, 2}, {0, 2}, {1, 0.5}}
+	hull := planar.ConvexHull(mp)
+
+	fmt.Println(hull)
+	// Output:
+	// [[[0 0] [2 0] [2 2] [0 2] [0 0]]]
+}
+
+func ExampleSelfIntersections() {
+	bowtie := orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}
+	points := planar.SelfIntersections(bowtie)
+
+	fmt.Println(points)
+	// Output:
+	// [[1 1]]
+}
+
+func ExampleDistanceBetween() {
+	line := orb.LineString{{0, 0}, {4, 0}}
+	poly := orb.Polygon{{{1, 2}, {3, 2}, {3, 4}, {1, 4}, {1, 2}}}
+
+	d, p1, p2 := planar.DistanceBetween(line, poly)
+
+	fmt.Println(d, p1, p2)
+	// Output:
+	// 2 [1 0] [1 2]
+}
@@ -0,0 +1,289 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// ConvexHull returns the smallest convex polygon that contains all the
+// points of the geometry. The ring is counter-clockwise and closed.
+// If all the points are collinear the ring will have no area and
+// if the geometry is empty nil is returned.
+func ConvexHull(g orb.Geometry) orb.Polygon {
+	hull := convexHull(hullPoints(g, nil))
+	if hull == nil {
+		return nil
+	}
+
+	return orb.Polygon{hull}
+}
+
+// ConcaveHull returns a polygon that contains all the points of the geometry
+// and follows their shape more closely than the convex hull. The concavity
+// is a relative measure, 1 will give a detailed, very concave, shape while
+// larger values tend towards the convex hull, which is returned for +Inf.
+// 2 is a good starting value. The ring is counter-clockwise and closed.
+//
+// Based on the algorithm in https://github.com/mapbox/concaveman: starting with
+// the convex hull, edges are repeatedly replaced by two edges through the
+// nearest inner point if that point is close enough, relative to the edge length,
+// and the new edges don't cross the current hull.
+func ConcaveHull(g orb.Geometry, concavity float64) orb.Polygon {
+	points := hullPoints(g, nil)
+	hull := convexHull(points)
+	if len(hull) < 4 || math.IsInf(concavity, 1) {
+		if hull == nil {
+			return nil
+		}
+
+		return orb.Polygon{hull}
+	}
+
+	onHull := make(map[orb.Point]bool, len(hull))
+	for _, p := range hull {
+		onHull[p] = true
+	}
+
+	var inner []orb.Point
+	for _, p := range points {
+		if !onHull[p] {
+			onHull[p] = true // prevents duplicates
+			inner = append(inner, p)
+		}
+	}
+	used := make([]bool, len(inner))
+
+	// the hull as a linked list, next[i] is the point after i.
+	ring := append([]orb.Point{}, hull[:len(hull)-1]...)
+	next := make([]int, len(ring))
+	prev := make([]int, len(ring))
+	for i := range ring {
+		next[i] = (i + 1) % len(ring)
+		prev[i] = (i - 1 + len(ring)) % len(ring)
+	}
+
+	maxRatio := 1 / (concavity * concavity)
+
+	queue := make([]int, len(ring))
+	for i := range queue {
+		queue[i] = i
+	}
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+
+		a, b := ring[i], ring[next[i]]
+		sqLen := DistanceSquared(a, b)
+		c := hullCandidate(ring, next, prev, i, inner, used)
+		if c == -1 {
+			continue
+		}
+
+		p := inner[c]
+		if math.Min(DistanceSquared(p, a), DistanceSquared(p, b)) > sqLen*maxRatio {
+			continue
+		}
+
+		if hullCrosses(ring, next, i, a, p) || hullCrosses(ring, next, i, p, b) {
+			continue
+		}
+
+		used[c] = true
+
+		// insert the point after i
+		ring = append(ring, p)
+		n := len(ring) - 1
+		next = append(next, next[i])
+		prev = append(prev, i)
+		prev[next[i]] = n
+		next[i] = n
+
+		queue = append(queue, i, n)
+	}
+
+	result := make(orb.Ring, 0, len(ring)+1)
+	for i, start := 0, true; start || i != 0; i, start = next[i], false {
+		result = append(result, ring[i])
+	}
+
+	return orb.Polygon{append(result, result[0])}
+}
+
+// hullCandidate returns the unused inner point closest to edge i of the
+// hull that is not closer to the neighboring edges.
+func hullCandidate(ring []orb.Point, next, prev []int, i int, inner []orb.Point, used []bool) int {
+	a, b := ring[i], ring[next[i]]
+	before, after := ring[prev[i]], ring[next[next[i]]]
+
+	best := -1
+	bestDist := math.Inf(1)
+	for j, p := range inner {
+		if used[j] {
+			continue
+		}
+
+		d := DistanceFromSegmentSquared(a, b, p)
+		if d >= bestDist {
+			continue
+		}
+
+		if d >= DistanceFromSegmentSquared(before, a, p) ||
+			d >= DistanceFromSegmentSquared(b, after, p) {
+			continue
+		}
+
+		best = j
+		bestDist = d
+	}
+
+	return best
+}
+
+// hullCrosses returns true if the segment [a, b] crosses any edge of the hull,
+// excluding edge i which is being replaced.
+func hullCrosses(ring []orb.Point, next []int, skip int, a, b orb.Point) bool {
+	for i, start := 0, true; start || i != 0; i, start = next[i], false {
+		if i == skip {
+			continue
+		}
+
+		c, d := ring[i], ring[next[i]]
+		if c == a || c == b || d == a || d == b {
+			continue
+		}
+
+		if segmentsCross(a, b, c, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// segmentsCross returns true if the segments intersect, including touching.
+func segmentsCross(a, b, c, d orb.Point) bool {
+	d1 := orient(c, d, a)
+	d2 := orient(c, d, b)
+	d3 := orient(a, b, c)
+	d4 := orient(a, b, d)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && inBound(c, d, a)) ||
+		(d2 == 0 && inBound(c, d, b)) ||
+		(d3 == 0 && inBound(a, b, c)) ||
+		(d4 == 0 && inBound(a, b, d))
+}
+
+// orient returns the orientation of the point p relative to the line a->b.
+// Positive if p is to the left, negative if to the right and zero if collinear.
+func orient(a, b, p orb.Point) float64 {
+	return (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+}
+
+// inBound returns true if p is within the bound of the segment [a, b].
+func inBound(a, b, p orb.Point) bool {
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+// convexHull uses Andrew's monotone chain algorithm to compute the
+// counter-clockwise hull of the points. The input is sorted in place.
+func convexHull(points []orb.Point) orb.Ring {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] == points[j][0] {
+			return points[i][1] < points[j][1]
+		}
+		return points[i][0] < points[j][0]
+	})
+
+	// remove duplicates
+	unique := points[:1]
+	for _, p := range points[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	points = unique
+
+	if len(points) < 3 {
+		r := append(orb.Ring{}, points...)
+		return append(r, points[0])
+	}
+
+	hull := make(orb.Ring, 0, 2*len(points))
+
+	// lower hull
+	for _, p := range points {
+		for len(hull) >= 2 && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	return hull
+}
+
+// hullPoints appends all the points of the geometry. Only the outer
+// rings of polygons are used since holes can not be on the hull.
+func hullPoints(g orb.Geometry, points []orb.Point) []orb.Point {
+	if g == nil {
+		return points
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return append(points, g)
+	case orb.MultiPoint:
+		return append(points, g...)
+	case orb.LineString:
+		return append(points, g...)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			points = append(points, ls...)
+		}
+		return points
+	case orb.Ring:
+		return append(points, g...)
+	case orb.Polygon:
+		if len(g) == 0 {
+			return points
+		}
+		return append(points, g[0]...)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			points = hullPoints(p, points)
+		}
+		return points
+	case orb.Collection:
+		for _, c := range g {
+			points = hullPoints(c, points)
+		}
+		return points
+	case orb.Bound:
+		return append(points, g.ToRing()...)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
@@ -0,0 +1,131 @@
+package planar
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestConvexHull(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		ConvexHull(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		result orb.Polygon
+	}{
+		{
+			name:   "square with inner points",
+			input:  orb.MultiPoint{{0, 0}, {1, 1}, {2, 2}, {2, 0}, {0, 2}, {1, 0}, {0.5, 1.5}},
+			result: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		},
+		{
+			name:   "clockwise polygon",
+			input:  orb.Polygon{{{0, 0}, {0, 2}, {1, 1}, {2, 2}, {2, 0}, {0, 0}}},
+			result: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		},
+		{
+			name:   "collinear",
+			input:  orb.LineString{{0, 0}, {1, 1}, {2, 2}},
+			result: orb.Polygon{{{0, 0}, {2, 2}, {0, 0}}},
+		},
+		{
+			name:   "single point",
+			input:  orb.MultiPoint{{1, 2}, {1, 2}},
+			result: orb.Polygon{{{1, 2}, {1, 2}}},
+		},
+		{
+			name:   "empty",
+			input:  orb.MultiPoint{},
+			result: nil,
+		},
+		{
+			name: "collection",
+			input: orb.Collection{
+				orb.Point{0, 0},
+				orb.LineString{{2, 0}, {1, 3}},
+			},
+			result: orb.Polygon{{{0, 0}, {2, 0}, {1, 3}, {0, 0}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ConvexHull(tc.input)
+			if !result.Equal(tc.result) {
+				t.Errorf("incorrect hull: %v != %v", result, tc.result)
+			}
+		})
+	}
+
+	t.Run("does not modify input", func(t *testing.T) {
+		mp := orb.MultiPoint{{2, 2}, {0, 0}, {1, 3}}
+		ConvexHull(mp)
+		if !mp.Equal(orb.MultiPoint{{2, 2}, {0, 0}, {1, 3}}) {
+			t.Errorf("input modified: %v", mp)
+		}
+	})
+}
+
+func TestConcaveHull(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		ConcaveHull(g, 2)
+	}
+
+	// points along the outline of a "U"
+	//
+	// +-+   +-+
+	// | |   | |
+	// | +---+ |
+	// +-------+
+	var mp orb.MultiPoint
+	outline := orb.LineString{{0, 0}, {5, 0}, {5, 3}, {4, 3}, {4, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0}}
+	for i := 0; i < len(outline)-1; i++ {
+		for f := 0.0; f < 1; f += 0.125 {
+			mp = append(mp, interpolate(outline[i], outline[i+1], f))
+		}
+	}
+
+	convex := ConcaveHull(mp, math.Inf(1))
+	if !convex.Equal(ConvexHull(mp)) {
+		t.Errorf("infinite concavity should be the convex hull")
+	}
+
+	concave := ConcaveHull(mp, 1)
+	if a := Area(concave); math.Abs(a-9) > 1e-9 {
+		t.Errorf("should follow the outline, area: %v", a)
+	}
+
+	if o := concave[0].Orientation(); o != orb.CCW {
+		t.Errorf("should be counter-clockwise: %v", o)
+	}
+
+	for _, p := range mp {
+		if !PolygonContains(concave, p) {
+			t.Errorf("point not in hull: %v", p)
+		}
+	}
+
+	t.Run("random points are contained", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		var mp orb.MultiPoint
+		for i := 0; i < 500; i++ {
+			mp = append(mp, orb.Point{r.Float64(), r.Float64()})
+		}
+
+		hull := ConcaveHull(mp, 2)
+		if Area(hull) >= Area(ConvexHull(mp)) {
+			t.Errorf("should be smaller than the convex hull")
+		}
+
+		for _, p := range mp {
+			if !PolygonContains(hull, p) {
+				t.Errorf("point not in hull: %v", p)
+			}
+		}
+	})
+}
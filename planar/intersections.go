@@ -0,0 +1,234 @@
+package planar
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+// Intersections returns the points where the boundaries of the two
+// geometries cross or touch. If segments overlap the end points of the
+// overlapping section are returned. Points are treated as zero length
+// segments so they are returned if on the other boundary.
+// The result is sorted by x and then y, and is nil if there are none.
+func Intersections(g1, g2 orb.Geometry) orb.MultiPoint {
+	s1 := boundarySegments(g1, nil, 0)
+	s2 := boundarySegments(g2, nil, 0)
+
+	for i := range s2 {
+		s2[i].geom = 1
+	}
+
+	return sweep(append(s1, s2...), func(s1, s2 *segment) ([]orb.Point, bool) {
+		if s1.geom == s2.geom {
+			return nil, false
+		}
+
+		return SegmentIntersections(s1.a, s1.b, s2.a, s2.b), true
+	})
+}
+
+// SelfIntersections returns the points where the boundary of the geometry
+// crosses or touches itself, for example where the two sides of a bow-tie
+// ring meet. Consecutive segments of a line or ring are only reported if
+// they overlap, i.e. the line doubles back on itself. The rings of a polygon
+// and the parts of multi geometries are checked against each other.
+// The result is sorted by x and then y, and is nil if there are none.
+//
+// The Bentley-Ottmann sweep line algorithm is used so each segment is only
+// compared to its neighbors along the line, O((n+k) log n) for n segments
+// with k intersections.
+func SelfIntersections(g orb.Geometry) orb.MultiPoint {
+	return sweep(boundarySegments(g, nil, 0), selfIntersections)
+}
+
+// selfIntersections compares two segments of the same geometry, ignoring
+// the end point shared by consecutive segments.
+func selfIntersections(s1, s2 *segment) ([]orb.Point, bool) {
+	points := SegmentIntersections(s1.a, s1.b, s2.a, s2.b)
+	if s1.part != s2.part {
+		return points, true
+	}
+
+	var shared orb.Point
+	switch {
+	case s1.index+1 == s2.index || (s1.closed && s1.index == s1.count-1 && s2.index == 0):
+		shared = s1.b
+	case s2.index+1 == s1.index || (s1.closed && s2.index == s2.count-1 && s1.index == 0):
+		shared = s2.b
+	default:
+		return points, true
+	}
+
+	// consecutive segments share an end point, this is expected.
+	result := points[:0]
+	for _, p := range points {
+		if p != shared {
+			result = append(result, p)
+		}
+	}
+
+	return result, true
+}
+
+// SegmentIntersections returns the points where the segments [a, b] and [c, d]
+// meet. If the segments are collinear and overlap the two end points of the
+// overlap are returned. Returns nil if the segments do not intersect.
+func SegmentIntersections(a, b, c, d orb.Point) []orb.Point {
+	if p, ok := properCrossing(a, b, c, d); ok {
+		return []orb.Point{p}
+	}
+
+	d1 := orient(c, d, a)
+	d2 := orient(c, d, b)
+	d3 := orient(a, b, c)
+	d4 := orient(a, b, d)
+
+	var result []orb.Point
+	add := func(p orb.Point) {
+		for _, r := range result {
+			if r == p {
+				return
+			}
+		}
+		result = append(result, p)
+	}
+
+	if d1 == 0 && onSegment(c, d, a) {
+		add(a)
+	}
+	if d2 == 0 && onSegment(c, d, b) {
+		add(b)
+	}
+	if d3 == 0 && onSegment(a, b, c) {
+		add(c)
+	}
+	if d4 == 0 && onSegment(a, b, d) {
+		add(d)
+	}
+
+	return result
+}
+
+// properCrossing returns the point where the segments cross if they do so
+// in the interior of both, not at an end point.
+func properCrossing(a, b, c, d orb.Point) (orb.Point, bool) {
+	d1 := orient(c, d, a)
+	d2 := orient(c, d, b)
+	d3 := orient(a, b, c)
+	d4 := orient(a, b, d)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		t := d1 / (d1 - d2)
+		return orb.Point{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}, true
+	}
+
+	return orb.Point{}, false
+}
+
+// onSegment returns true if the collinear point p is within the segment [a, b].
+// If the segment is a point, p must match exactly.
+func onSegment(a, b, p orb.Point) bool {
+	if a == b {
+		return a == p
+	}
+
+	return inBound(a, b, p)
+}
+
+type segment struct {
+	a, b orb.Point
+
+	geom  int
+	part  int // the line or ring the segment is from
+	index int // index of the segment in the part
+	count int // number of segments in the part
+
+	closed bool
+
+	// used by the sweep, l is the left, or lower, end point.
+	id   int
+	l, r orb.Point
+	node *sweepNode
+}
+
+// boundarySegments appends the segments of the geometry boundary.
+// Each line or ring is given a unique part number.
+func boundarySegments(g orb.Geometry, segs []segment, part int) []segment {
+	add := func(ls orb.LineString) {
+		if len(ls) == 0 {
+			return
+		}
+
+		// repeated points would create zero length segments
+		// that touch the segments around them.
+		deduped := make(orb.LineString, 0, len(ls))
+		for _, p := range ls {
+			if len(deduped) == 0 || deduped[len(deduped)-1] != p {
+				deduped = append(deduped, p)
+			}
+		}
+		ls = deduped
+
+		if len(ls) == 1 {
+			segs = append(segs, segment{a: ls[0], b: ls[0], part: part, count: 1})
+			part++
+			return
+		}
+
+		closed := len(ls) > 3 && ls[0] == ls[len(ls)-1]
+		for i := 0; i < len(ls)-1; i++ {
+			segs = append(segs, segment{
+				a:      ls[i],
+				b:      ls[i+1],
+				part:   part,
+				index:  i,
+				count:  len(ls) - 1,
+				closed: closed,
+			})
+		}
+		part++
+	}
+
+	switch g := g.(type) {
+	case nil:
+	case orb.Point:
+		add(orb.LineString{g})
+	case orb.MultiPoint:
+		for _, p := range g {
+			add(orb.LineString{p})
+		}
+	case orb.LineString:
+		add(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			add(ls)
+		}
+	case orb.Ring:
+		add(orb.LineString(g))
+	case orb.Polygon:
+		for _, r := range g {
+			add(orb.LineString(r))
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				add(orb.LineString(r))
+			}
+		}
+	case orb.Collection:
+		for _, c := range g {
+			segs = boundarySegments(c, segs, part)
+			if len(segs) > 0 {
+				part = segs[len(segs)-1].part + 1
+			}
+		}
+	case orb.Bound:
+		add(orb.LineString(g.ToRing()))
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+
+	return segs
+}
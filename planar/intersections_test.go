@@ -0,0 +1,214 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestSegmentIntersections(t *testing.T) {
+	cases := []struct {
+		name   string
+		a, b   orb.Point
+		c, d   orb.Point
+		result []orb.Point
+	}{
+		{
+			name: "crossing",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 2},
+			c: orb.Point{0, 2}, d: orb.Point{2, 0},
+			result: []orb.Point{{1, 1}},
+		},
+		{
+			name: "touching",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 0},
+			c: orb.Point{1, 0}, d: orb.Point{1, 1},
+			result: []orb.Point{{1, 0}},
+		},
+		{
+			name: "shared end point",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{1, 0}, d: orb.Point{1, 1},
+			result: []orb.Point{{1, 0}},
+		},
+		{
+			name: "collinear overlap",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 0},
+			c: orb.Point{3, 0}, d: orb.Point{1, 0},
+			result: []orb.Point{{2, 0}, {1, 0}},
+		},
+		{
+			name: "collinear disjoint",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{2, 0}, d: orb.Point{3, 0},
+			result: nil,
+		},
+		{
+			name: "parallel",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 0},
+			c: orb.Point{0, 1}, d: orb.Point{1, 1},
+			result: nil,
+		},
+		{
+			name: "not reaching",
+			a:    orb.Point{0, 0}, b: orb.Point{1, 1},
+			c: orb.Point{3, 0}, d: orb.Point{2, 1},
+			result: nil,
+		},
+		{
+			name: "point on segment",
+			a:    orb.Point{0, 0}, b: orb.Point{2, 2},
+			c: orb.Point{1, 1}, d: orb.Point{1, 1},
+			result: []orb.Point{{1, 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SegmentIntersections(tc.a, tc.b, tc.c, tc.d)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect intersections: %v != %v", result, tc.result)
+			}
+		})
+	}
+}
+
+func TestIntersections(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Intersections(g, g)
+	}
+
+	cases := []struct {
+		name   string
+		g1, g2 orb.Geometry
+		result orb.MultiPoint
+	}{
+		{
+			name:   "line through polygon",
+			g1:     orb.LineString{{-1, 0.5}, {3, 0.5}},
+			g2:     orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+			result: orb.MultiPoint{{0, 0.5}, {2, 0.5}},
+		},
+		{
+			name: "polygon with hole",
+			g1:   orb.LineString{{-1, 2}, {5, 2}},
+			g2: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+			},
+			result: orb.MultiPoint{{0, 2}, {1, 2}, {3, 2}, {4, 2}},
+		},
+		{
+			name:   "rings sharing an edge",
+			g1:     orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+			g2:     orb.Ring{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}},
+			result: orb.MultiPoint{{1, 0}, {1, 1}},
+		},
+		{
+			name:   "disjoint",
+			g1:     orb.LineString{{0, 0}, {1, 0}},
+			g2:     orb.LineString{{0, 1}, {1, 1}},
+			result: nil,
+		},
+		{
+			name:   "point on line",
+			g1:     orb.Point{0.5, 0},
+			g2:     orb.LineString{{0, 0}, {1, 0}},
+			result: orb.MultiPoint{{0.5, 0}},
+		},
+		{
+			name:   "self intersections ignored",
+			g1:     orb.LineString{{0, 0}, {2, 2}, {2, 0}, {0, 2}},
+			g2:     orb.LineString{{5, 5}, {6, 6}},
+			result: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Intersections(tc.g1, tc.g2)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect intersections: %v != %v", result, tc.result)
+			}
+		})
+	}
+}
+
+func TestSelfIntersections(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		SelfIntersections(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		result orb.MultiPoint
+	}{
+		{
+			name:   "bow-tie",
+			input:  orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}},
+			result: orb.MultiPoint{{1, 1}},
+		},
+		{
+			name:   "simple ring",
+			input:  orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+			result: nil,
+		},
+		{
+			name:   "triangle",
+			input:  orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+			result: nil,
+		},
+		{
+			name:   "repeated points",
+			input:  orb.Ring{{0, 0}, {1, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+			result: nil,
+		},
+		{
+			name:   "touching itself at a vertex",
+			input:  orb.Ring{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}, {1, 1}, {0, 0}},
+			result: orb.MultiPoint{{1, 1}},
+		},
+		{
+			name:   "spike",
+			input:  orb.LineString{{0, 0}, {2, 0}, {1, 0}},
+			result: orb.MultiPoint{{1, 0}},
+		},
+		{
+			name:   "open line crossing",
+			input:  orb.LineString{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, -1}},
+			result: orb.MultiPoint{{1, 0}},
+		},
+		{
+			name:   "doubles back on itself",
+			input:  orb.LineString{{0, 0}, {1, 0}, {0, 0}},
+			result: orb.MultiPoint{{0, 0}},
+		},
+		{
+			name: "hole crossing the shell",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {5, 1}, {5, 3}, {1, 3}, {1, 1}},
+			},
+			result: orb.MultiPoint{{4, 1}, {4, 3}},
+		},
+		{
+			name: "multi polygon overlap",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			result: orb.MultiPoint{{1, 2}, {2, 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SelfIntersections(tc.input)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect intersections: %v != %v", result, tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package planar
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// sweep finds the intersections of the segments using the Bentley-Ottmann
+// algorithm. A vertical line is moved across the segments, from left to right,
+// stopping at the end points and crossings. The segments that cross the line
+// are kept in a tree ordered by y and a segment is only compared to its
+// neighbors in the tree and to the segments that touch its end points.
+// This is O((n+k) log n) for n segments with k intersections.
+//
+// The compare function returns the intersections of two segments and
+// false if the pair should be skipped.
+func sweep(segs []segment, compare func(s1, s2 *segment) ([]orb.Point, bool)) orb.MultiPoint {
+	sw := &sweeper{
+		compare:  compare,
+		seen:     make(map[orb.Point]bool),
+		compared: make(map[[2]int]bool),
+		swapped:  make(map[[2]int]bool),
+		status:   &sweepStatus{seed: 2463534242},
+	}
+
+	for i := range segs {
+		s := &segs[i]
+		s.id = i
+		s.l, s.r = s.a, s.b
+		if lessPoint(s.r, s.l) {
+			s.l, s.r = s.r, s.l
+		}
+
+		sw.push(&sweepEvent{p: s.l, kind: startEvent, s: s})
+		if s.l != s.r {
+			sw.push(&sweepEvent{p: s.r, kind: endEvent, s: s})
+		}
+	}
+
+	for len(sw.events) > 0 {
+		sw.handle(heap.Pop(&sw.events).(*sweepEvent))
+	}
+
+	result := sw.result
+	sort.Slice(result, func(i, j int) bool {
+		return lessPoint(result[i], result[j])
+	})
+
+	return result
+}
+
+type sweeper struct {
+	compare func(s1, s2 *segment) ([]orb.Point, bool)
+	events  sweepEvents
+	seq     int
+
+	status    *sweepStatus
+	verticals []*segment // vertical segments at the current x
+
+	// the current event point and the segments with an
+	// end point there that have already been handled.
+	p    orb.Point
+	here []*segment
+
+	compared map[[2]int]bool
+	swapped  map[[2]int]bool
+
+	seen   map[orb.Point]bool
+	result orb.MultiPoint
+}
+
+func (sw *sweeper) push(e *sweepEvent) {
+	e.seq = sw.seq
+	sw.seq++
+	heap.Push(&sw.events, e)
+}
+
+func (sw *sweeper) handle(e *sweepEvent) {
+	if e.kind == crossEvent {
+		sw.cross(e.s, e.t)
+		return
+	}
+
+	if len(sw.here) == 0 || e.p != sw.p {
+		sw.p = e.p
+		sw.here = sw.here[:0]
+	}
+
+	// every segment that touches an end point is compared with this one,
+	// the neighbors in the tree are not enough when many segments meet.
+	s := e.s
+	for _, t := range sw.here {
+		sw.comparePair(t, s)
+	}
+
+	sw.containing(e.p, func(t *segment) {
+		if t != s {
+			sw.comparePair(t, s)
+		}
+	})
+	sw.here = append(sw.here, s)
+
+	vertical := s.l[0] == s.r[0] && s.l != s.r
+	switch {
+	case s.l == s.r:
+		// a point, it is only compared at its location
+	case e.kind == endEvent && vertical:
+		for i, v := range sw.verticals {
+			if v == s {
+				sw.verticals = append(sw.verticals[:i], sw.verticals[i+1:]...)
+				break
+			}
+		}
+	case e.kind == endEvent:
+		prev, next := sw.status.prev(s.node), sw.status.next(s.node)
+		sw.status.remove(s.node)
+		s.node = nil
+
+		sw.check(prev, next)
+	case vertical:
+		// vertical segments are not in the tree since they have no single y
+		// value on the sweep line. Compare them with all the segments in the
+		// tree they span, these all intersect.
+		sw.verticals = append(sw.verticals, s)
+
+		n := sw.status.first(func(t *segment) bool { return orient(t.l, t.r, s.l) > 0 })
+		for ; n != nil && orient(n.seg.l, n.seg.r, s.r) >= 0; n = sw.status.next(n) {
+			sw.comparePair(n.seg, s)
+		}
+	default:
+		s.node = sw.status.insert(s, func(t *segment) bool {
+			o := orient(t.l, t.r, s.l)
+			if o == 0 {
+				// starts on the other segment, order by where they go.
+				o = orient(t.l, t.r, s.r)
+			}
+
+			return o < 0
+		})
+
+		sw.check(sw.status.prev(s.node), s.node)
+		sw.check(s.node, sw.status.next(s.node))
+	}
+}
+
+// containing calls the function for the segments on the sweep line
+// that contain the point.
+func (sw *sweeper) containing(p orb.Point, f func(*segment)) {
+	for _, v := range sw.verticals {
+		if v.l[0] == p[0] && v.l[1] <= p[1] && p[1] <= v.r[1] {
+			f(v)
+		}
+	}
+
+	n := sw.status.first(func(t *segment) bool { return orient(t.l, t.r, p) > 0 })
+	for ; n != nil && orient(n.seg.l, n.seg.r, p) == 0; n = sw.status.next(n) {
+		f(n.seg)
+	}
+}
+
+// check compares two segments that are now next to each other in the
+// tree and adds an event to swap them if they cross.
+func (sw *sweeper) check(lower, upper *sweepNode) {
+	if lower == nil || upper == nil {
+		return
+	}
+
+	a, b := lower.seg, upper.seg
+	sw.comparePair(a, b)
+
+	if sw.swapped[pairKey(a, b)] {
+		return
+	}
+
+	if p, ok := properCrossing(a.a, a.b, b.a, b.b); ok {
+		sw.push(&sweepEvent{p: p, kind: crossEvent, s: a, t: b})
+	}
+}
+
+// cross swaps the order of the two segments in the tree if the lower
+// one is still just below the upper one. If not, the event will be
+// added again when they are next to each other.
+func (sw *sweeper) cross(lower, upper *segment) {
+	if lower.node == nil || upper.node == nil || sw.swapped[pairKey(lower, upper)] {
+		return
+	}
+
+	if sw.status.next(lower.node) != upper.node {
+		return
+	}
+
+	sw.swapped[pairKey(lower, upper)] = true
+	lower.node, upper.node = upper.node, lower.node
+	lower.node.seg, upper.node.seg = lower, upper
+
+	sw.check(sw.status.prev(upper.node), upper.node)
+	sw.check(lower.node, sw.status.next(lower.node))
+}
+
+func (sw *sweeper) comparePair(s1, s2 *segment) {
+	key := pairKey(s1, s2)
+	if sw.compared[key] {
+		return
+	}
+	sw.compared[key] = true
+
+	points, ok := sw.compare(s1, s2)
+	if !ok {
+		return
+	}
+
+	for _, p := range points {
+		if !sw.seen[p] {
+			sw.seen[p] = true
+			sw.result = append(sw.result, p)
+		}
+	}
+}
+
+func pairKey(s1, s2 *segment) [2]int {
+	if s1.id > s2.id {
+		return [2]int{s2.id, s1.id}
+	}
+
+	return [2]int{s1.id, s2.id}
+}
+
+func lessPoint(a, b orb.Point) bool {
+	if a[0] == b[0] {
+		return a[1] < b[1]
+	}
+
+	return a[0] < b[0]
+}
+
+const (
+	endEvent = iota
+	crossEvent
+	startEvent
+)
+
+// sweepEvent is a point where the sweep line stops. At the same point
+// segments are removed first, then crossings are swapped before new
+// segments are added.
+type sweepEvent struct {
+	p    orb.Point
+	kind int
+	seq  int
+
+	s, t *segment
+}
+
+type sweepEvents []*sweepEvent
+
+func (e sweepEvents) Len() int      { return len(e) }
+func (e sweepEvents) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e sweepEvents) Less(i, j int) bool {
+	if e[i].p != e[j].p {
+		return lessPoint(e[i].p, e[j].p)
+	}
+
+	if e[i].kind != e[j].kind {
+		return e[i].kind < e[j].kind
+	}
+
+	return e[i].seq < e[j].seq
+}
+
+func (e *sweepEvents) Push(x interface{}) {
+	*e = append(*e, x.(*sweepEvent))
+}
+
+func (e *sweepEvents) Pop() interface{} {
+	old := *e
+	n := len(old)
+	x := old[n-1]
+	*e = old[:n-1]
+	return x
+}
+
+// sweepStatus is a treap of the segments crossing the sweep line ordered
+// by y. Nodes have parent pointers so segments can be removed and swapped
+// by reference, without comparisons, since the order of two segments
+// changes as the line moves.
+type sweepStatus struct {
+	root *sweepNode
+	seed uint32
+}
+
+type sweepNode struct {
+	seg  *segment
+	prio uint32
+
+	left, right, parent *sweepNode
+}
+
+// insert adds the segment before the first node, in order,
+// where the before function is true.
+func (st *sweepStatus) insert(s *segment, before func(*segment) bool) *sweepNode {
+	// xorshift, a fixed seed keeps the results the same between runs.
+	st.seed ^= st.seed << 13
+	st.seed ^= st.seed >> 17
+	st.seed ^= st.seed << 5
+
+	n := &sweepNode{seg: s, prio: st.seed}
+
+	var parent *sweepNode
+	cur, left := st.root, false
+	for cur != nil {
+		parent = cur
+		left = before(cur.seg)
+		if left {
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+
+	n.parent = parent
+	switch {
+	case parent == nil:
+		st.root = n
+	case left:
+		parent.left = n
+	default:
+		parent.right = n
+	}
+
+	for n.parent != nil && n.prio < n.parent.prio {
+		st.rotateUp(n)
+	}
+
+	return n
+}
+
+func (st *sweepStatus) remove(n *sweepNode) {
+	for n.left != nil || n.right != nil {
+		child := n.left
+		if child == nil || (n.right != nil && n.right.prio < child.prio) {
+			child = n.right
+		}
+
+		st.rotateUp(child)
+	}
+
+	switch {
+	case n.parent == nil:
+		st.root = nil
+	case n.parent.left == n:
+		n.parent.left = nil
+	default:
+		n.parent.right = nil
+	}
+}
+
+// first returns the first node where the below function is false.
+func (st *sweepStatus) first(below func(*segment) bool) *sweepNode {
+	var result *sweepNode
+	for cur := st.root; cur != nil; {
+		if below(cur.seg) {
+			cur = cur.right
+		} else {
+			result = cur
+			cur = cur.left
+		}
+	}
+
+	return result
+}
+
+func (st *sweepStatus) next(n *sweepNode) *sweepNode {
+	if n == nil {
+		return nil
+	}
+
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+
+	for n.parent != nil && n.parent.right == n {
+		n = n.parent
+	}
+
+	return n.parent
+}
+
+func (st *sweepStatus) prev(n *sweepNode) *sweepNode {
+	if n == nil {
+		return nil
+	}
+
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+
+	for n.parent != nil && n.parent.left == n {
+		n = n.parent
+	}
+
+	return n.parent
+}
+
+// rotateUp moves the node above its parent.
+func (st *sweepStatus) rotateUp(n *sweepNode) {
+	p, g := n.parent, n.parent.parent
+	if p.left == n {
+		p.left = n.right
+		if n.right != nil {
+			n.right.parent = p
+		}
+		n.right = p
+	} else {
+		p.right = n.left
+		if n.left != nil {
+			n.left.parent = p
+		}
+		n.left = p
+	}
+
+	p.parent = n
+	n.parent = g
+
+	switch {
+	case g == nil:
+		st.root = n
+	case g.left == p:
+		g.left = n
+	default:
+		g.right = n
+	}
+}
@@ -0,0 +1,102 @@
+package planar
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestSweep(t *testing.T) {
+	// compare with checking every pair of segments. Small integer
+	// coordinates create many vertical, overlapping and touching segments.
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 2000; i++ {
+		var g orb.Geometry
+		switch i % 3 {
+		case 0:
+			g = randomLineString(r, 2+r.Intn(10), 6)
+		case 1:
+			ring := orb.Ring(randomLineString(r, 3+r.Intn(8), 6))
+			g = append(ring, ring[0])
+		default:
+			g = orb.MultiLineString{
+				randomLineString(r, 2+r.Intn(5), 1000),
+				randomLineString(r, 2+r.Intn(5), 1000),
+				randomLineString(r, 1, 6),
+			}
+		}
+
+		segs := boundarySegments(g, nil, 0)
+		expected := bruteForce(segs, selfIntersections)
+		result := sweep(segs, selfIntersections)
+
+		if !samePoints(result, expected) || !samePoints(expected, result) {
+			t.Fatalf("incorrect intersections for %v: %v != %v", g, result, expected)
+		}
+	}
+}
+
+func TestSweep_compared(t *testing.T) {
+	// a ring made of long horizontal segments, all of them overlap in x.
+	ring := orb.Ring{}
+	for i := 0; i < 1000; i++ {
+		ring = append(ring, orb.Point{0, float64(i)}, orb.Point{1000, float64(i) + 0.5})
+	}
+	ring = append(ring, ring[0])
+
+	compared := 0
+	segs := boundarySegments(ring, nil, 0)
+	sweep(segs, func(s1, s2 *segment) ([]orb.Point, bool) {
+		compared++
+		return selfIntersections(s1, s2)
+	})
+
+	// each segment should be compared to a few neighbors, not all of them.
+	if compared > 10*len(segs) {
+		t.Errorf("too many segments compared: %d", compared)
+	}
+}
+
+func randomLineString(r *rand.Rand, n int, size int) orb.LineString {
+	ls := make(orb.LineString, 0, n)
+	for i := 0; i < n; i++ {
+		ls = append(ls, orb.Point{float64(r.Intn(size)), float64(r.Intn(size))})
+	}
+
+	return ls
+}
+
+func bruteForce(segs []segment, compare func(s1, s2 *segment) ([]orb.Point, bool)) orb.MultiPoint {
+	var result orb.MultiPoint
+	for i := range segs {
+		for j := i + 1; j < len(segs); j++ {
+			points, _ := compare(&segs[i], &segs[j])
+			result = append(result, points...)
+		}
+	}
+
+	return result
+}
+
+// samePoints checks every point in a is also in b. Crossings computed from
+// different pairs of segments can be slightly different.
+func samePoints(a, b orb.MultiPoint) bool {
+	for _, p := range a {
+		found := false
+		for _, q := range b {
+			if math.Abs(p[0]-q[0]) < 1e-9 && math.Abs(p[1]-q[1]) < 1e-9 {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
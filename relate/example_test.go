@@ -0,0 +1,31 @@
+package relate_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/relate"
+)
+
+func ExampleRelate() {
+	a := orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}
+	b := orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}
+
+	m := relate.Relate(a, b)
+	fmt.Println(m)
+	fmt.Println(m.Matches("T*T***T**"))
+	// Output:
+	// 212101212
+	// true
+}
+
+func ExampleTouches() {
+	road := orb.LineString{{0, 0}, {1, 0}}
+	parcel := orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}
+
+	fmt.Println(relate.Touches(road, parcel))
+	fmt.Println(relate.Within(road, parcel))
+	// Output:
+	// true
+	// false
+}
@@ -0,0 +1,297 @@
+package relate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+// A graph is a geometry split into its area, line and point parts
+// so any point can be located relative to it.
+type graph struct {
+	area   orb.MultiPolygon
+	lines  []orb.LineString
+	points []orb.Point
+
+	// endpoints counts how many times a point is the end of a line,
+	// it is a boundary if this is odd, the mod 2 rule.
+	endpoints map[orb.Point]int
+
+	edges     []edge
+	bound     orb.Bound
+	tolerance float64
+}
+
+type edge struct {
+	a, b orb.Point
+}
+
+func (e edge) midpoint() orb.Point {
+	return orb.Point{(e.a[0] + e.b[0]) / 2, (e.a[1] + e.b[1]) / 2}
+}
+
+func newGraph(g orb.Geometry) *graph {
+	gr := &graph{
+		endpoints: make(map[orb.Point]int),
+		bound: orb.Bound{
+			Min: orb.Point{math.Inf(1), math.Inf(1)},
+			Max: orb.Point{math.Inf(-1), math.Inf(-1)},
+		},
+	}
+
+	var polygons orb.MultiPolygon
+	gr.add(g, &polygons)
+	if len(polygons) > 0 {
+		gr.area = overlay.Union(polygons, nil)
+	}
+
+	for _, p := range gr.area {
+		for _, r := range p {
+			gr.addEdges(orb.LineString(r))
+		}
+	}
+
+	for _, ls := range gr.lines {
+		gr.addEdges(ls)
+	}
+
+	for _, p := range gr.points {
+		gr.addEdges(orb.LineString{p})
+	}
+
+	return gr
+}
+
+func (gr *graph) add(g orb.Geometry, polygons *orb.MultiPolygon) {
+	switch g := g.(type) {
+	case nil:
+	case orb.Point:
+		gr.points = append(gr.points, g)
+	case orb.MultiPoint:
+		gr.points = append(gr.points, g...)
+	case orb.LineString:
+		gr.addLine(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			gr.addLine(ls)
+		}
+	case orb.Ring:
+		*polygons = append(*polygons, orb.Polygon{g})
+	case orb.Polygon:
+		*polygons = append(*polygons, g)
+	case orb.MultiPolygon:
+		*polygons = append(*polygons, g...)
+	case orb.Collection:
+		for _, c := range g {
+			gr.add(c, polygons)
+		}
+	case orb.Bound:
+		*polygons = append(*polygons, g.ToPolygon())
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (gr *graph) addLine(ls orb.LineString) {
+	if len(ls) == 0 {
+		return
+	}
+
+	gr.lines = append(gr.lines, ls)
+	if ls[0] != ls[len(ls)-1] {
+		gr.endpoints[ls[0]]++
+		gr.endpoints[ls[len(ls)-1]]++
+	}
+}
+
+// addEdges adds the segments of the line, a single point
+// is added as a zero length edge.
+func (gr *graph) addEdges(ls orb.LineString) {
+	for _, p := range ls {
+		gr.bound = gr.bound.Extend(p)
+	}
+
+	if len(ls) == 1 {
+		gr.edges = append(gr.edges, edge{a: ls[0], b: ls[0]})
+		return
+	}
+
+	for i := 0; i < len(ls)-1; i++ {
+		if ls[i] != ls[i+1] {
+			gr.edges = append(gr.edges, edge{a: ls[i], b: ls[i+1]})
+		}
+	}
+}
+
+// locate returns the location of the point relative to the geometry.
+// The highest dimension part containing the point is used.
+func (gr *graph) locate(p orb.Point) Location {
+	if !gr.bound.Pad(gr.tolerance).Contains(p) {
+		return Exterior
+	}
+
+	for _, poly := range gr.area {
+		for _, r := range poly {
+			if gr.onLine(orb.LineString(r), p) {
+				return Boundary
+			}
+		}
+	}
+
+	if planar.MultiPolygonContains(gr.area, p) {
+		return Interior
+	}
+
+	if gr.endpoints[p]%2 == 1 {
+		return Boundary
+	}
+
+	for _, ls := range gr.lines {
+		if gr.onLine(ls, p) {
+			return Interior
+		}
+	}
+
+	for _, q := range gr.points {
+		if q == p {
+			return Interior
+		}
+	}
+
+	return Exterior
+}
+
+// onLine returns true if the point is within the tolerance of the line.
+func (gr *graph) onLine(ls orb.LineString, p orb.Point) bool {
+	if len(ls) == 1 {
+		return ls[0] == p
+	}
+
+	tol := gr.tolerance * gr.tolerance
+	for i := 0; i < len(ls)-1; i++ {
+		a, b := ls[i], ls[i+1]
+		if p[0] < math.Min(a[0], b[0])-gr.tolerance || p[0] > math.Max(a[0], b[0])+gr.tolerance ||
+			p[1] < math.Min(a[1], b[1])-gr.tolerance || p[1] > math.Max(a[1], b[1])+gr.tolerance {
+			continue
+		}
+
+		if planar.DistanceFromSegmentSquared(a, b, p) <= tol {
+			return true
+		}
+	}
+
+	return false
+}
+
+// node splits the edges of each geometry where they meet the edges of
+// the other. Zero length edges, points, are kept if not touching.
+func node(ea, eb []edge, intersects bool) ([]edge, []edge) {
+	cutsA := make([][]orb.Point, len(ea))
+	cutsB := make([][]orb.Point, len(eb))
+
+	if intersects {
+		type item struct {
+			e     edge
+			geom  int
+			index int
+		}
+
+		items := make([]item, 0, len(ea)+len(eb))
+		for i, e := range ea {
+			items = append(items, item{e: e, geom: 0, index: i})
+		}
+		for i, e := range eb {
+			items = append(items, item{e: e, geom: 1, index: i})
+		}
+
+		sort.Slice(items, func(i, j int) bool {
+			return math.Min(items[i].e.a[0], items[i].e.b[0]) < math.Min(items[j].e.a[0], items[j].e.b[0])
+		})
+
+		var active []*item
+		for i := range items {
+			it := &items[i]
+			minX := math.Min(it.e.a[0], it.e.b[0])
+			minY, maxY := math.Min(it.e.a[1], it.e.b[1]), math.Max(it.e.a[1], it.e.b[1])
+
+			n := 0
+			for _, a := range active {
+				if math.Max(a.e.a[0], a.e.b[0]) >= minX {
+					active[n] = a
+					n++
+				}
+			}
+			active = active[:n]
+
+			for _, a := range active {
+				if a.geom == it.geom ||
+					math.Max(a.e.a[1], a.e.b[1]) < minY || math.Min(a.e.a[1], a.e.b[1]) > maxY {
+					continue
+				}
+
+				points := planar.SegmentIntersections(a.e.a, a.e.b, it.e.a, it.e.b)
+				if len(points) == 0 {
+					continue
+				}
+
+				x, y := a, it
+				if x.geom == 1 {
+					x, y = y, x
+				}
+				cutsA[x.index] = append(cutsA[x.index], points...)
+				cutsB[y.index] = append(cutsB[y.index], points...)
+			}
+
+			active = append(active, it)
+		}
+	}
+
+	return split(ea, cutsA), split(eb, cutsB)
+}
+
+// split breaks each edge at the cut points.
+func split(edges []edge, cuts [][]orb.Point) []edge {
+	result := make([]edge, 0, len(edges))
+	for i, e := range edges {
+		if len(cuts[i]) == 0 || e.a == e.b {
+			result = append(result, e)
+			continue
+		}
+
+		points := cuts[i]
+		sort.Slice(points, func(i, j int) bool {
+			return planar.DistanceSquared(e.a, points[i]) < planar.DistanceSquared(e.a, points[j])
+		})
+
+		prev := e.a
+		for _, p := range points {
+			if p != prev && p != e.b {
+				result = append(result, edge{a: prev, b: p})
+				prev = p
+			}
+		}
+		result = append(result, edge{a: prev, b: e.b})
+	}
+
+	return result
+}
+
+// tolerance is the distance within which points are considered on a line,
+// it is relative to the size and location of the geometries.
+func tolerance(b orb.Bound) float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+
+	size := math.Max(b.Max[0]-b.Min[0], b.Max[1]-b.Min[1])
+	mag := math.Max(
+		math.Max(math.Abs(b.Min[0]), math.Abs(b.Max[0])),
+		math.Max(math.Abs(b.Min[1]), math.Abs(b.Max[1])),
+	)
+
+	return 1e-12 * math.Max(size, mag)
+}
@@ -0,0 +1,95 @@
+package relate
+
+import "github.com/paulmach/orb"
+
+// Intersects returns true if the geometries have at least one point in common.
+func Intersects(a, b orb.Geometry) bool {
+	return !Disjoint(a, b)
+}
+
+// Disjoint returns true if the geometries have no points in common.
+func Disjoint(a, b orb.Geometry) bool {
+	if a == nil || b == nil {
+		return true
+	}
+
+	if !a.Bound().Intersects(b.Bound()) {
+		return true
+	}
+
+	return Relate(a, b).Matches("FF*FF****")
+}
+
+// Within returns true if a is completely inside b,
+// the interiors must intersect and a can not touch the exterior of b.
+func Within(a, b orb.Geometry) bool {
+	return Relate(a, b).Matches("T*F**F***")
+}
+
+// Contains returns true if b is completely inside a. This is the same as Within(b, a).
+func Contains(a, b orb.Geometry) bool {
+	return Relate(a, b).Matches("T*****FF*")
+}
+
+// Covers returns true if no point of b is outside of a. Unlike Contains,
+// b can be completely on the boundary of a.
+func Covers(a, b orb.Geometry) bool {
+	m := Relate(a, b)
+	return m.Matches("T*****FF*") ||
+		m.Matches("*T****FF*") ||
+		m.Matches("***T**FF*") ||
+		m.Matches("****T*FF*")
+}
+
+// Touches returns true if the geometries have a point in common
+// but their interiors do not intersect.
+func Touches(a, b orb.Geometry) bool {
+	m := Relate(a, b)
+	return m.Matches("FT*******") ||
+		m.Matches("F**T*****") ||
+		m.Matches("F***T****")
+}
+
+// Crosses returns true if the interiors intersect in a lower dimension than
+// the geometries, e.g. two lines crossing at a point or a line passing
+// through a polygon. Always false for two points or two polygons.
+func Crosses(a, b orb.Geometry) bool {
+	da, db := dimension(a), dimension(b)
+
+	m := Relate(a, b)
+	switch {
+	case da == 1 && db == 1:
+		return m.Matches("0********")
+	case da < db:
+		return m.Matches("T*T******")
+	case da > db:
+		return m.Matches("T*****T**")
+	}
+
+	return false
+}
+
+// Overlaps returns true if the geometries have the same dimension and
+// share some, but not all, of their interiors. The intersection of
+// the interiors must also have the same dimension.
+func Overlaps(a, b orb.Geometry) bool {
+	da, db := dimension(a), dimension(b)
+	if da != db {
+		return false
+	}
+
+	m := Relate(a, b)
+	if da == 1 {
+		return m.Matches("1*T***T**")
+	}
+
+	return m.Matches("T*T***T**")
+}
+
+func dimension(g orb.Geometry) int {
+	if g == nil {
+		return -1
+	}
+
+	return g.Dimensions()
+}
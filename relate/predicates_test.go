@@ -0,0 +1,129 @@
+package relate
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestPredicates(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b orb.Geometry
+
+		intersects, within, contains, covers bool
+		touches, crosses, overlaps           bool
+	}{
+		{
+			name:       "overlapping polygons",
+			a:          square(0, 0, 2),
+			b:          square(1, 1, 2),
+			intersects: true,
+			overlaps:   true,
+		},
+		{
+			name:       "adjacent polygons",
+			a:          square(0, 0, 1),
+			b:          square(1, 0, 1),
+			intersects: true,
+			touches:    true,
+		},
+		{
+			name: "disjoint polygons",
+			a:    square(0, 0, 1),
+			b:    square(5, 5, 1),
+		},
+		{
+			name:       "polygon inside polygon",
+			a:          square(0, 0, 4),
+			b:          square(1, 1, 1),
+			intersects: true,
+			contains:   true,
+			covers:     true,
+		},
+		{
+			name:       "polygon in polygon",
+			a:          square(1, 1, 1),
+			b:          square(0, 0, 4),
+			intersects: true,
+			within:     true,
+		},
+		{
+			name:       "line along polygon edge",
+			a:          square(0, 0, 2),
+			b:          orb.LineString{{0, 0}, {1, 0}},
+			intersects: true,
+			covers:     true,
+			touches:    true,
+		},
+		{
+			name:       "line through polygon",
+			a:          orb.LineString{{-1, 0.5}, {3, 0.5}},
+			b:          square(0, 0, 2),
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name:       "crossing lines",
+			a:          orb.LineString{{0, 0}, {2, 2}},
+			b:          orb.LineString{{0, 2}, {2, 0}},
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name:       "overlapping lines",
+			a:          orb.LineString{{0, 0}, {2, 0}},
+			b:          orb.LineString{{1, 0}, {3, 0}},
+			intersects: true,
+			overlaps:   true,
+		},
+		{
+			name:       "point at line end",
+			a:          orb.Point{0, 0},
+			b:          orb.LineString{{0, 0}, {1, 0}},
+			intersects: true,
+			touches:    true,
+		},
+		{
+			name:       "multi point partly in polygon",
+			a:          orb.MultiPoint{{1, 1}, {5, 5}},
+			b:          square(0, 0, 2),
+			intersects: true,
+			crosses:    true,
+		},
+		{
+			name: "nil",
+			a:    nil,
+			b:    square(0, 0, 2),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := Intersects(tc.a, tc.b); v != tc.intersects {
+				t.Errorf("incorrect intersects: %v", v)
+			}
+			if v := Disjoint(tc.a, tc.b); v == tc.intersects {
+				t.Errorf("incorrect disjoint: %v", v)
+			}
+			if v := Within(tc.a, tc.b); v != tc.within {
+				t.Errorf("incorrect within: %v", v)
+			}
+			if v := Contains(tc.a, tc.b); v != tc.contains {
+				t.Errorf("incorrect contains: %v", v)
+			}
+			if v := Covers(tc.a, tc.b); v != tc.covers {
+				t.Errorf("incorrect covers: %v", v)
+			}
+			if v := Touches(tc.a, tc.b); v != tc.touches {
+				t.Errorf("incorrect touches: %v", v)
+			}
+			if v := Crosses(tc.a, tc.b); v != tc.crosses {
+				t.Errorf("incorrect crosses: %v", v)
+			}
+			if v := Overlaps(tc.a, tc.b); v != tc.overlaps {
+				t.Errorf("incorrect overlaps: %v", v)
+			}
+		})
+	}
+}
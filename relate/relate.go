@@ -0,0 +1,149 @@
+// Package relate computes the DE-9IM intersection matrix between two
+// geometries and provides the standard spatial predicates based on it,
+// such as Intersects, Within and Touches. The geometries are assumed
+// to be in the 2d plane.
+package relate
+
+import (
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+// A Location is a part of a geometry.
+type Location int
+
+// The parts of a geometry used to index the matrix.
+const (
+	Interior Location = 0
+	Boundary Location = 1
+	Exterior Location = 2
+)
+
+// Empty is the matrix value for parts that do not intersect.
+const Empty = -1
+
+// Matrix is the DE-9IM intersection matrix. The value at [i][j] is the
+// dimension of the intersection of location i of the first geometry with
+// location j of the second, or Empty if they do not intersect.
+type Matrix [3][3]int
+
+// String returns the matrix in the usual 9 character form,
+// e.g. "212101212", with F for empty intersections.
+func (m Matrix) String() string {
+	var sb strings.Builder
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if m[i][j] == Empty {
+				sb.WriteByte('F')
+			} else {
+				sb.WriteByte(byte('0' + m[i][j]))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Matches returns true if the matrix matches the 9 character pattern.
+// Each character can be 'T' for any non empty intersection, 'F' for empty,
+// '*' for anything or '0', '1', '2' for the exact dimension.
+// Patterns of the wrong length never match.
+func (m Matrix) Matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+
+	for k := 0; k < 9; k++ {
+		v := m[k/3][k%3]
+		switch c := pattern[k]; c {
+		case '*':
+		case 'T', 't':
+			if v == Empty {
+				return false
+			}
+		case 'F', 'f':
+			if v != Empty {
+				return false
+			}
+		case '0', '1', '2':
+			if v != int(c-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Relate computes the intersection matrix of the two geometries.
+//
+// Points have no boundary, the boundary of a line is its end points,
+// excluding closed lines, and the boundary of a polygon is its rings.
+// The polygons of a geometry are merged, so the boundary between adjacent
+// polygons of a collection is considered interior. For collections, the
+// location in the highest dimension part is used.
+func Relate(a, b orb.Geometry) Matrix {
+	m := Matrix{
+		{Empty, Empty, Empty},
+		{Empty, Empty, Empty},
+		{Empty, Empty, 2},
+	}
+
+	ga, gb := newGraph(a), newGraph(b)
+	tol := tolerance(ga.bound.Union(gb.bound))
+	ga.tolerance, gb.tolerance = tol, tol
+
+	set := func(p orb.Point, dim int) {
+		la, lb := ga.locate(p), gb.locate(p)
+		if dim > m[la][lb] {
+			m[la][lb] = dim
+		}
+	}
+
+	// the end points and midpoints of the edges, once split where
+	// they meet the other geometry, capture the 0 and 1 dimensional
+	// parts of the matrix.
+	sa, sb := node(ga.edges, gb.edges, ga.bound.Intersects(gb.bound))
+	for _, edges := range [][]edge{sa, sb} {
+		for _, e := range edges {
+			set(e.a, 0)
+			set(e.b, 0)
+			if e.a != e.b {
+				set(e.midpoint(), 1)
+			}
+		}
+	}
+
+	// the area parts, the exterior is always 2 dimensional.
+	areaA := planar.Area(ga.area)
+	areaB := planar.Area(gb.area)
+	if areaA > 0 {
+		if areaB > 0 {
+			if planar.Area(overlay.Intersection(ga.area, gb.area)) > 0 {
+				m[Interior][Interior] = 2
+			}
+			if planar.Area(overlay.Difference(ga.area, gb.area)) > 0 {
+				m[Interior][Exterior] = 2
+			}
+		} else {
+			m[Interior][Exterior] = 2
+		}
+	}
+
+	if areaB > 0 {
+		if areaA > 0 {
+			if planar.Area(overlay.Difference(gb.area, ga.area)) > 0 {
+				m[Exterior][Interior] = 2
+			}
+		} else {
+			m[Exterior][Interior] = 2
+		}
+	}
+
+	return m
+}
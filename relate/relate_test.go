@@ -0,0 +1,182 @@
+package relate
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func square(x, y, s float64) orb.Polygon {
+	return orb.Polygon{{{x, y}, {x + s, y}, {x + s, y + s}, {x, y + s}, {x, y}}}
+}
+
+func TestRelate(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		for _, o := range orb.AllGeometries {
+			Relate(g, o)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected string
+	}{
+		{
+			name:     "overlapping polygons",
+			a:        square(0, 0, 2),
+			b:        square(1, 1, 2),
+			expected: "212101212",
+		},
+		{
+			name:     "adjacent polygons",
+			a:        square(0, 0, 1),
+			b:        square(1, 0, 1),
+			expected: "FF2F11212",
+		},
+		{
+			name:     "polygons touching at a corner",
+			a:        square(0, 0, 1),
+			b:        square(1, 1, 1),
+			expected: "FF2F01212",
+		},
+		{
+			name:     "polygon inside polygon",
+			a:        square(0, 0, 4),
+			b:        square(1, 1, 1),
+			expected: "212FF1FF2",
+		},
+		{
+			name:     "equal polygons",
+			a:        square(0, 0, 1),
+			b:        orb.Polygon{{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}},
+			expected: "2FFF1FFF2",
+		},
+		{
+			name:     "polygon with hole and point in hole",
+			a:        orb.Polygon{square(0, 0, 4)[0], {{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}}},
+			b:        orb.Point{2, 2},
+			expected: "FF2FF10F2",
+		},
+		{
+			name:     "polygon and point inside",
+			a:        square(0, 0, 2),
+			b:        orb.Point{1, 1},
+			expected: "0F2FF1FF2",
+		},
+		{
+			name:     "polygon and point on boundary",
+			a:        square(0, 0, 2),
+			b:        orb.Point{2, 1},
+			expected: "FF20F1FF2",
+		},
+		{
+			name:     "line through polygon",
+			a:        orb.LineString{{-1, 0.5}, {3, 0.5}},
+			b:        square(0, 0, 2),
+			expected: "101FF0212",
+		},
+		{
+			name:     "line along polygon edge",
+			a:        orb.LineString{{0, 0}, {1, 0}},
+			b:        square(0, 0, 2),
+			expected: "F1FF0F212",
+		},
+		{
+			name:     "crossing lines",
+			a:        orb.LineString{{0, 0}, {2, 2}},
+			b:        orb.LineString{{0, 2}, {2, 0}},
+			expected: "0F1FF0102",
+		},
+		{
+			name:     "overlapping lines",
+			a:        orb.LineString{{0, 0}, {2, 0}},
+			b:        orb.LineString{{1, 0}, {3, 0}},
+			expected: "1010F0102",
+		},
+		{
+			name:     "closed line has no boundary",
+			a:        orb.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+			b:        orb.Point{0, 0},
+			expected: "0F1FFFFF2",
+		},
+		{
+			name:     "point at line end",
+			a:        orb.Point{0, 0},
+			b:        orb.LineString{{0, 0}, {1, 0}},
+			expected: "F0FFFF102",
+		},
+		{
+			name:     "point at line middle",
+			a:        orb.Point{1, 0},
+			b:        orb.LineString{{0, 0}, {2, 0}},
+			expected: "0FFFFF102",
+		},
+		{
+			name:     "disjoint points",
+			a:        orb.MultiPoint{{0, 0}, {1, 1}},
+			b:        orb.Point{2, 2},
+			expected: "FF0FFF0F2",
+		},
+		{
+			name:     "collection merges polygons",
+			a:        orb.Collection{square(0, 0, 1), square(1, 0, 1)},
+			b:        orb.Point{1, 0.5},
+			expected: "0F2FF1FF2",
+		},
+		{
+			name:     "nil",
+			a:        nil,
+			b:        square(0, 0, 1),
+			expected: "FFFFFF212",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := Relate(tc.a, tc.b)
+			if v := m.String(); v != tc.expected {
+				t.Errorf("incorrect matrix: %v != %v", v, tc.expected)
+			}
+
+			// the transpose
+			var expected []byte
+			for j := 0; j < 3; j++ {
+				for i := 0; i < 3; i++ {
+					expected = append(expected, tc.expected[3*i+j])
+				}
+			}
+
+			if v := Relate(tc.b, tc.a).String(); v != string(expected) {
+				t.Errorf("incorrect transpose: %v != %v", v, string(expected))
+			}
+		})
+	}
+}
+
+func TestMatrix_Matches(t *testing.T) {
+	m := Relate(square(0, 0, 2), square(1, 1, 2)) // 212101212
+
+	cases := []struct {
+		pattern string
+		match   bool
+	}{
+		{pattern: "*********", match: true},
+		{pattern: "T*T***T**", match: true},
+		{pattern: "212101212", match: true},
+		{pattern: "2121F1212", match: false},
+		{pattern: "F********", match: false},
+		{pattern: "1********", match: false},
+		{pattern: "t*t***t**", match: true},
+		{pattern: "T*T", match: false},
+		{pattern: "X********", match: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			if v := m.Matches(tc.pattern); v != tc.match {
+				t.Errorf("incorrect match: %v != %v", v, tc.match)
+			}
+		})
+	}
+}
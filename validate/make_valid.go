@@ -0,0 +1,307 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+// MakeValid attempts to repair the common problems found by IsValid.
+// Invalid coordinates and repeated points are removed and rings are closed.
+// Self intersecting rings are split into simple loops that are combined,
+// so a bow-tie becomes two triangles. Holes are subtracted from their shell
+// and polygons of a multi-polygon are merged if they overlap. Rings will be
+// oriented using the GeoJSON right hand rule.
+//
+// Polygons may become multi-polygons, and vice versa. Parts that collapse
+// are removed, a line with one distinct point becomes a point. If nothing
+// is left nil is returned. The input is not modified.
+func MakeValid(g orb.Geometry) orb.Geometry {
+	switch g := g.(type) {
+	case nil:
+		return nil
+	case orb.Point:
+		if !finite(g) {
+			return nil
+		}
+		return g
+	case orb.MultiPoint:
+		var result orb.MultiPoint
+		for _, p := range g {
+			if finite(p) {
+				result = append(result, p)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case orb.LineString:
+		return makeValidLineString(g)
+	case orb.MultiLineString:
+		var result orb.MultiLineString
+		for _, ls := range g {
+			if l, ok := makeValidLineString(ls).(orb.LineString); ok {
+				result = append(result, l)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case orb.Ring:
+		return areal(makeValidPolygon(orb.Polygon{g}))
+	case orb.Polygon:
+		return areal(makeValidPolygon(g))
+	case orb.MultiPolygon:
+		var pieces []orb.MultiPolygon
+		for _, p := range g {
+			pieces = append(pieces, makeValidPolygon(p))
+		}
+		return areal(overlay.UnionAll(pieces))
+	case orb.Collection:
+		var result orb.Collection
+		for _, cg := range g {
+			if v := MakeValid(cg); v != nil {
+				result = append(result, v)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case orb.Bound:
+		if !finite(g.Min) || !finite(g.Max) {
+			return nil
+		}
+
+		return orb.Bound{Min: g.Min, Max: g.Min}.Extend(g.Max)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func makeValidLineString(ls orb.LineString) orb.Geometry {
+	var points orb.LineString
+	for _, p := range ls {
+		if finite(p) {
+			points = append(points, p)
+		}
+	}
+	points = dedupe(points)
+
+	switch len(points) {
+	case 0:
+		return nil
+	case 1:
+		return points[0]
+	}
+
+	return points
+}
+
+func makeValidPolygon(p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 {
+		return nil
+	}
+
+	shell := makeValidRing(p[0])
+	if len(shell) == 0 || len(p) == 1 {
+		return shell
+	}
+
+	var holes []orb.MultiPolygon
+	for _, r := range p[1:] {
+		holes = append(holes, makeValidRing(r))
+	}
+
+	return overlay.Difference(shell, overlay.UnionAll(holes))
+}
+
+// makeValidRing returns the area enclosed by the ring. Where the ring
+// crosses itself it is split into loops, each loop adds to the area
+// independent of its orientation.
+func makeValidRing(r orb.Ring) orb.MultiPolygon {
+	var points orb.LineString
+	for _, p := range r {
+		if finite(p) {
+			points = append(points, p)
+		}
+	}
+
+	points = dedupe(points)
+	if len(points) < 3 {
+		return nil
+	}
+
+	if points[0] != points[len(points)-1] {
+		points = append(points, points[0])
+	}
+
+	points = noding(points)
+
+	var pieces []orb.MultiPolygon
+	for _, loop := range loops(points) {
+		if len(loop) < 4 || planar.Area(loop) == 0 {
+			continue
+		}
+
+		if loop.Orientation() != orb.CCW {
+			loop.Reverse()
+		}
+		pieces = append(pieces, orb.MultiPolygon{{loop}})
+	}
+
+	return overlay.UnionAll(pieces)
+}
+
+// noding adds the points where the closed line crosses itself, so every
+// intersection is a vertex. The crossings are found once, with the sweep in
+// the planar package, and the same point is added to every segment it is on
+// so the loops can be matched up exactly.
+func noding(ls orb.LineString) orb.LineString {
+	crossings := planar.SelfIntersections(ls)
+	if len(crossings) == 0 {
+		return ls
+	}
+
+	// a crossing computed from different segments can be slightly
+	// different, or slightly off a vertex it should be at.
+	tol := tolerance(ls.Bound())
+	s := newSnapper(tol)
+	for _, p := range ls {
+		s.snap(p)
+	}
+
+	var points orb.MultiPoint
+	seen := make(map[orb.Point]bool, len(crossings))
+	for _, p := range crossings {
+		p = s.snap(p)
+		if !seen[p] {
+			seen[p] = true
+			points = append(points, p)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i][0] < points[j][0]
+	})
+
+	result := make(orb.LineString, 0, len(ls)+2*len(points))
+	for i := 0; i < len(ls)-1; i++ {
+		a, b := ls[i], ls[i+1]
+
+		// only the crossings within the x range of the segment
+		minX, maxX := math.Min(a[0], b[0])-tol, math.Max(a[0], b[0])+tol
+		k := sort.Search(len(points), func(k int) bool {
+			return points[k][0] >= minX
+		})
+
+		var splits []orb.Point
+		for ; k < len(points) && points[k][0] <= maxX; k++ {
+			p := points[k]
+			if p != a && p != b && planar.DistanceFromSegmentSquared(a, b, p) <= tol*tol {
+				splits = append(splits, p)
+			}
+		}
+
+		sort.Slice(splits, func(i, j int) bool {
+			return planar.DistanceSquared(a, splits[i]) < planar.DistanceSquared(a, splits[j])
+		})
+
+		result = append(result, a)
+		result = append(result, splits...)
+	}
+
+	result = append(result, ls[len(ls)-1])
+	return dedupe(result)
+}
+
+// tolerance returns the distance under which two points are considered
+// the same, relative to the size of the coordinates.
+func tolerance(b orb.Bound) float64 {
+	mag := math.Max(
+		math.Max(math.Abs(b.Min[0]), math.Abs(b.Max[0])),
+		math.Max(math.Abs(b.Min[1]), math.Abs(b.Max[1])),
+	)
+
+	if mag == 0 {
+		return 1e-12
+	}
+
+	return 1e-12 * mag
+}
+
+// A snapper replaces points within the tolerance of an already
+// seen point with that point.
+type snapper struct {
+	tol   float64
+	cells map[[2]int64][]orb.Point
+}
+
+func newSnapper(tol float64) *snapper {
+	return &snapper{
+		tol:   tol,
+		cells: make(map[[2]int64][]orb.Point),
+	}
+}
+
+func (s *snapper) snap(p orb.Point) orb.Point {
+	cx, cy := int64(math.Floor(p[0]/s.tol)), int64(math.Floor(p[1]/s.tol))
+	for x := cx - 1; x <= cx+1; x++ {
+		for y := cy - 1; y <= cy+1; y++ {
+			for _, q := range s.cells[[2]int64{x, y}] {
+				if math.Abs(p[0]-q[0]) <= s.tol && math.Abs(p[1]-q[1]) <= s.tol {
+					return q
+				}
+			}
+		}
+	}
+
+	c := [2]int64{cx, cy}
+	s.cells[c] = append(s.cells[c], p)
+	return p
+}
+
+// loops splits the closed line at the repeated vertices into closed rings
+// that do not touch themselves.
+func loops(ls orb.LineString) []orb.Ring {
+	var result []orb.Ring
+
+	stack := make([]orb.Point, 0, len(ls))
+	index := make(map[orb.Point]int, len(ls))
+	for _, p := range ls {
+		k, ok := index[p]
+		if !ok {
+			index[p] = len(stack)
+			stack = append(stack, p)
+			continue
+		}
+
+		loop := append(orb.Ring{}, stack[k:]...)
+		result = append(result, append(loop, p))
+
+		for _, q := range stack[k+1:] {
+			delete(index, q)
+		}
+		stack = stack[:k+1]
+	}
+
+	return result
+}
+
+// areal returns the polygon if there is only one.
+func areal(mp orb.MultiPolygon) orb.Geometry {
+	switch len(mp) {
+	case 0:
+		return nil
+	case 1:
+		return mp[0]
+	}
+
+	return mp
+}
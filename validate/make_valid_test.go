@@ -0,0 +1,231 @@
+package validate
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestMakeValid(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		MakeValid(g)
+	}
+}
+
+func TestMakeValid_Polygons(t *testing.T) {
+	cases := []struct {
+		name  string
+		input orb.Geometry
+		parts int
+		area  float64
+	}{
+		{
+			name:  "unclosed ring",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}},
+			parts: 1,
+			area:  4,
+		},
+		{
+			name:  "clockwise with repeated point",
+			input: orb.Polygon{{{0, 0}, {0, 2}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}},
+			parts: 1,
+			area:  4,
+		},
+		{
+			name:  "bow tie",
+			input: orb.Polygon{{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}},
+			parts: 2,
+			area:  2,
+		},
+		{
+			name:  "spike",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {3, 0}, {2, 0}, {2, 2}, {0, 0}}},
+			parts: 1,
+			area:  2,
+		},
+		{
+			name:  "nan coordinate",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {math.NaN(), 1}, {2, 2}, {0, 2}, {0, 0}}},
+			parts: 1,
+			area:  4,
+		},
+		{
+			name: "hole outside shell",
+			input: orb.Polygon{
+				{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+				{{5, 5}, {5, 6}, {6, 6}, {6, 5}, {5, 5}},
+			},
+			parts: 1,
+			area:  4,
+		},
+		{
+			name: "hole crossing shell",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{3, 1}, {5, 1}, {5, 3}, {3, 3}, {3, 1}},
+			},
+			parts: 1,
+			area:  14,
+		},
+		{
+			name: "overlapping polygons",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			parts: 1,
+			area:  7,
+		},
+		{
+			name: "crossing computed differently per segment",
+			input: orb.Polygon{{
+				{0.9143459303948515, 4.185630527566348},
+				{2.017469792144711, 8.53572904059949},
+				{2.127782178319697, 4.4856305275663475},
+				{0.7143459303948514, 8.100719189296175},
+				{0.9143459303948515, 4.185630527566348},
+			}},
+			parts: 2,
+			area:  2.531055176446534,
+		},
+		{
+			name:  "collapsed ring",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {0, 0}}},
+			parts: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := MakeValid(tc.input)
+
+			var parts int
+			switch r := result.(type) {
+			case nil:
+			case orb.Polygon:
+				parts = 1
+			case orb.MultiPolygon:
+				parts = len(r)
+			default:
+				t.Fatalf("incorrect type: %T", result)
+			}
+
+			if parts != tc.parts {
+				t.Errorf("incorrect parts: %d != %d", parts, tc.parts)
+			}
+
+			if parts == 0 {
+				return
+			}
+
+			if a := planar.Area(result); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+
+			if valid, reasons := IsValid(result, RightHandRule()); !valid {
+				t.Errorf("result not valid: %v", reasons)
+			}
+		})
+	}
+}
+
+func TestMakeValid_BowTies(t *testing.T) {
+	triangle := func(a, b, c orb.Point) float64 {
+		return math.Abs(planar.Area(orb.Ring{a, b, c, a}))
+	}
+
+	r := rand.New(rand.NewSource(42))
+	random := func() orb.Point {
+		return orb.Point{10 * r.Float64(), 10 * r.Float64()}
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b, c, d := random(), random(), random(), random()
+		input := orb.Polygon{{a, b, c, d, a}}
+
+		// the area of the loops on either side of the crossing
+		expected := math.Abs(planar.Area(input))
+		if x := planar.SegmentIntersections(a, b, c, d); len(x) == 1 {
+			expected = triangle(a, x[0], d) + triangle(x[0], b, c)
+		} else if x := planar.SegmentIntersections(b, c, d, a); len(x) == 1 {
+			expected = triangle(a, b, x[0]) + triangle(x[0], c, d)
+		}
+
+		result := MakeValid(input)
+		if valid, reasons := IsValid(result, RightHandRule()); !valid {
+			t.Fatalf("%d: result not valid: %v: %v", i, input, reasons)
+		}
+
+		if area := planar.Area(result); math.Abs(area-expected) > 1e-6 {
+			t.Fatalf("%d: incorrect area: %v: %v != %v", i, input, area, expected)
+		}
+	}
+}
+
+func TestMakeValid_Lines(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    orb.Geometry
+		expected orb.Geometry
+	}{
+		{
+			name:     "repeated points",
+			input:    orb.LineString{{0, 0}, {0, 0}, {1, 1}, {1, 1}},
+			expected: orb.LineString{{0, 0}, {1, 1}},
+		},
+		{
+			name:     "collapsed to point",
+			input:    orb.LineString{{1, 1}, {1, 1}},
+			expected: orb.Point{1, 1},
+		},
+		{
+			name:     "invalid coordinates",
+			input:    orb.LineString{{math.Inf(1), 0}},
+			expected: nil,
+		},
+		{
+			name:     "multi line string",
+			input:    orb.MultiLineString{{{1, 1}}, {{0, 0}, {1, 0}}},
+			expected: orb.MultiLineString{{{0, 0}, {1, 0}}},
+		},
+		{
+			name:     "multi point",
+			input:    orb.MultiPoint{{math.NaN(), 0}, {1, 2}},
+			expected: orb.MultiPoint{{1, 2}},
+		},
+		{
+			name:     "bound",
+			input:    orb.Bound{Min: orb.Point{2, 0}, Max: orb.Point{1, 1}},
+			expected: orb.Bound{Min: orb.Point{1, 0}, Max: orb.Point{2, 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := MakeValid(tc.input)
+			if tc.expected == nil {
+				if result != nil {
+					t.Errorf("expected nil: %v", result)
+				}
+				return
+			}
+
+			if result == nil || !orb.Equal(result, tc.expected) {
+				t.Errorf("incorrect result: %v != %v", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMakeValid_DoesNotModifyInput(t *testing.T) {
+	p := orb.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}}}
+	c := p.Clone()
+
+	MakeValid(p)
+	if !p.Equal(c) {
+		t.Errorf("input modified: %v", p)
+	}
+}
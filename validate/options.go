@@ -0,0 +1,17 @@
+package validate
+
+type options struct {
+	rightHand bool
+}
+
+// An Option is a possible parameter to IsValid.
+type Option func(*options)
+
+// RightHandRule also checks the orientation of polygon rings, outer rings
+// must be counter-clockwise and holes clockwise as required by GeoJSON.
+// The OGC rules do not define an orientation so by default it is not checked.
+func RightHandRule() Option {
+	return func(o *options) {
+		o.rightHand = true
+	}
+}
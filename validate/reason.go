@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+// A Code identifies the type of problem with the geometry.
+type Code int
+
+// The problems checked for by IsValid.
+const (
+	// InvalidCoordinate is a coordinate that is NaN or infinite.
+	InvalidCoordinate Code = iota + 1
+
+	// TooFewPoints is a line with less than 2 distinct points
+	// or a ring with less than 4 points.
+	TooFewPoints
+
+	// RingNotClosed is a ring where the first and last points do not match.
+	RingNotClosed
+
+	// RepeatedPoint is the same point repeated consecutively.
+	RepeatedPoint
+
+	// Spike is a point where the line goes back on itself.
+	Spike
+
+	// SelfIntersection is where a ring crosses or touches itself, or where the
+	// rings of a polygon cross each other or touch at more than one point.
+	SelfIntersection
+
+	// WrongOrientation is an outer ring that is not counter-clockwise or a hole
+	// that is not clockwise, as required by the GeoJSON spec. It is only
+	// checked with the RightHandRule option.
+	WrongOrientation
+
+	// HoleOutsideShell is a hole that is not inside the outer ring of its polygon.
+	HoleOutsideShell
+
+	// NestedHoles is a hole that is inside another hole of the same polygon.
+	NestedHoles
+
+	// OverlappingPolygons is where the polygons of a multi-polygon overlap.
+	OverlappingPolygons
+
+	// InvalidBound is a bound where the min is larger than the max.
+	InvalidBound
+)
+
+var codeNames = map[Code]string{
+	InvalidCoordinate:   "invalid coordinate",
+	TooFewPoints:        "too few points",
+	RingNotClosed:       "ring not closed",
+	RepeatedPoint:       "repeated point",
+	Spike:               "spike",
+	SelfIntersection:    "self intersection",
+	WrongOrientation:    "wrong orientation",
+	HoleOutsideShell:    "hole outside shell",
+	NestedHoles:         "nested holes",
+	OverlappingPolygons: "overlapping polygons",
+	InvalidBound:        "invalid bound",
+}
+
+// String returns a short description of the code.
+func (c Code) String() string {
+	if n, ok := codeNames[c]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("unknown code %d", int(c))
+}
+
+// A Reason describes what is wrong with the geometry and where.
+type Reason struct {
+	Code Code
+
+	// Point is the location of the problem, e.g. the repeated point
+	// or where the self intersection is.
+	Point orb.Point
+
+	// Path is the list of indexes into the geometry to the part with
+	// the problem. For example, [2, 1] for a multi-polygon is the first
+	// hole of the third polygon. For collections the first index is
+	// the geometry in the collection.
+	Path []int
+}
+
+// String returns a human readable version of the reason.
+func (r Reason) String() string {
+	return fmt.Sprintf("%v at %v, path %v", r.Code, r.Point, r.Path)
+}
@@ -0,0 +1,315 @@
+// Package validate checks geometries for problems, such as self
+// intersections or unclosed rings, and can repair the common ones.
+// The checks assume the geometry is in the 2d plane.
+package validate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/overlay"
+	"github.com/paulmach/orb/planar"
+)
+
+// IsValid checks the geometry against the OGC simple feature rules.
+// It returns false and the list of problems if the geometry is not valid.
+// Lines are allowed to cross themselves but repeated points and spikes
+// are reported. Use the RightHandRule option to also check the orientation
+// of polygon rings.
+func IsValid(g orb.Geometry, opts ...Option) (bool, []Reason) {
+	c := &checker{}
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+	c.geometry(g, nil)
+
+	return len(c.reasons) == 0, c.reasons
+}
+
+type checker struct {
+	options
+	reasons []Reason
+}
+
+func (c *checker) add(code Code, p orb.Point, path []int) {
+	c.reasons = append(c.reasons, Reason{
+		Code:  code,
+		Point: p,
+		Path:  append([]int(nil), path...),
+	})
+}
+
+func (c *checker) geometry(g orb.Geometry, path []int) {
+	switch g := g.(type) {
+	case nil:
+	case orb.Point:
+		c.point(g, path)
+	case orb.MultiPoint:
+		for i, p := range g {
+			c.point(p, sub(path, i))
+		}
+	case orb.LineString:
+		c.lineString(g, path)
+	case orb.MultiLineString:
+		for i, ls := range g {
+			c.lineString(ls, sub(path, i))
+		}
+	case orb.Ring:
+		c.polygon(orb.Polygon{g}, path)
+	case orb.Polygon:
+		c.polygon(g, path)
+	case orb.MultiPolygon:
+		c.multiPolygon(g, path)
+	case orb.Collection:
+		for i, cg := range g {
+			c.geometry(cg, sub(path, i))
+		}
+	case orb.Bound:
+		if !finite(g.Min) || !finite(g.Max) {
+			c.add(InvalidCoordinate, g.Min, path)
+		} else if g.IsEmpty() {
+			c.add(InvalidBound, g.Min, path)
+		}
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (c *checker) point(p orb.Point, path []int) {
+	if !finite(p) {
+		c.add(InvalidCoordinate, p, path)
+	}
+}
+
+// coordinates reports invalid coordinates and repeated points,
+// it returns false if there were invalid coordinates.
+func (c *checker) coordinates(ls orb.LineString, path []int) bool {
+	ok := true
+	for i, p := range ls {
+		if !finite(p) {
+			c.add(InvalidCoordinate, p, path)
+			ok = false
+			continue
+		}
+
+		if i > 0 && ls[i-1] == p {
+			c.add(RepeatedPoint, p, path)
+		}
+	}
+
+	return ok
+}
+
+func (c *checker) lineString(ls orb.LineString, path []int) {
+	if !c.coordinates(ls, path) {
+		return
+	}
+
+	points := dedupe(ls)
+	if len(points) < 2 {
+		var p orb.Point
+		if len(ls) > 0 {
+			p = ls[0]
+		}
+		c.add(TooFewPoints, p, path)
+		return
+	}
+
+	for _, p := range spikes(points, false) {
+		c.add(Spike, p, path)
+	}
+}
+
+// ring checks the ring on its own, it returns false if the ring is
+// too broken for checking it against other rings.
+func (c *checker) ring(r orb.Ring, path []int, o orb.Orientation) bool {
+	if !c.coordinates(orb.LineString(r), path) {
+		return false
+	}
+
+	if len(r) < 4 {
+		var p orb.Point
+		if len(r) > 0 {
+			p = r[0]
+		}
+		c.add(TooFewPoints, p, path)
+		return false
+	}
+
+	if !r.Closed() {
+		c.add(RingNotClosed, r[len(r)-1], path)
+		return false
+	}
+
+	points := dedupe(orb.LineString(r))
+	if len(points) < 4 {
+		c.add(TooFewPoints, r[0], path)
+		return false
+	}
+
+	ok := true
+	for _, p := range spikes(points, true) {
+		c.add(Spike, p, path)
+		ok = false
+	}
+
+	for _, p := range planar.SelfIntersections(r) {
+		c.add(SelfIntersection, p, path)
+		ok = false
+	}
+
+	if ro := r.Orientation(); c.rightHand && ro != 0 && ro != o {
+		c.add(WrongOrientation, r[0], path)
+	}
+
+	return ok
+}
+
+func (c *checker) polygon(p orb.Polygon, path []int) {
+	if len(p) == 0 {
+		return
+	}
+
+	valid := true
+	for i, r := range p {
+		o := orb.CW
+		if i == 0 {
+			o = orb.CCW
+		}
+
+		if !c.ring(r, sub(path, i), o) {
+			valid = false
+		}
+	}
+
+	if !valid {
+		return
+	}
+
+	shell := p[0]
+	for i := 1; i < len(p); i++ {
+		for _, v := range p[i] {
+			if !planar.RingContains(shell, v) {
+				c.add(HoleOutsideShell, v, sub(path, i))
+				break
+			}
+		}
+
+		for j := 1; j < len(p); j++ {
+			if i != j && nested(p[i], p[j]) {
+				c.add(NestedHoles, p[j][0], sub(path, j))
+			}
+		}
+	}
+
+	// rings can touch at one point, otherwise the interior
+	// would be disconnected.
+	for i := 0; i < len(p); i++ {
+		for j := i + 1; j < len(p); j++ {
+			if !p[i].Bound().Intersects(p[j].Bound()) {
+				continue
+			}
+
+			if points := planar.Intersections(p[i], p[j]); len(points) > 1 {
+				for _, pt := range points {
+					c.add(SelfIntersection, pt, sub(path, j))
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) multiPolygon(mp orb.MultiPolygon, path []int) {
+	for i, p := range mp {
+		c.polygon(p, sub(path, i))
+	}
+
+	// the overlap is checked even if the polygons have other problems
+	// so it is not hidden by them.
+	for i := 0; i < len(mp); i++ {
+		for j := i + 1; j < len(mp); j++ {
+			if len(mp[i]) == 0 || len(mp[j]) == 0 ||
+				!mp[i].Bound().Intersects(mp[j].Bound()) {
+				continue
+			}
+
+			inter := overlay.Intersection(mp[i], mp[j])
+			if planar.Area(inter) > 0 {
+				c.add(OverlappingPolygons, inter[0][0][0], sub(path, j))
+			}
+		}
+	}
+}
+
+// nested returns true if the inner ring is within the outer ring.
+func nested(outer, inner orb.Ring) bool {
+	ob, ib := outer.Bound(), inner.Bound()
+	if ob.Union(ib) != ob {
+		return false
+	}
+
+	for _, v := range inner {
+		if !planar.RingContains(outer, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// spikes returns the points where the line goes back on itself.
+// The line must not have repeated points.
+func spikes(ls orb.LineString, closed bool) []orb.Point {
+	var result []orb.Point
+
+	n := len(ls)
+	if closed {
+		n-- // the last point is the same as the first
+	}
+
+	for i := 0; i < n; i++ {
+		var prev, next orb.Point
+		switch {
+		case i > 0 && i < len(ls)-1:
+			prev, next = ls[i-1], ls[i+1]
+		case closed && i == 0:
+			prev, next = ls[n-1], ls[1]
+		default:
+			continue
+		}
+
+		if isSpike(prev, ls[i], next) {
+			result = append(result, ls[i])
+		}
+	}
+
+	return result
+}
+
+func isSpike(a, b, c orb.Point) bool {
+	cross := (b[0]-a[0])*(c[1]-b[1]) - (b[1]-a[1])*(c[0]-b[0])
+	dot := (b[0]-a[0])*(c[0]-b[0]) + (b[1]-a[1])*(c[1]-b[1])
+
+	return cross == 0 && dot < 0
+}
+
+func dedupe(ls orb.LineString) orb.LineString {
+	result := make(orb.LineString, 0, len(ls))
+	for _, p := range ls {
+		if len(result) == 0 || result[len(result)-1] != p {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func finite(p orb.Point) bool {
+	return !math.IsNaN(p[0]) && !math.IsNaN(p[1]) &&
+		!math.IsInf(p[0], 0) && !math.IsInf(p[1], 0)
+}
+
+func sub(path []int, i int) []int {
+	return append(path[:len(path):len(path)], i)
+}
@@ -0,0 +1,249 @@
+package validate
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestIsValid(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		IsValid(g)
+		IsValid(g, RightHandRule())
+	}
+}
+
+func TestIsValid_Reasons(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   orb.Geometry
+		opts    []Option
+		reasons []Reason
+	}{
+		{
+			name:  "valid polygon",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		},
+		{
+			name: "valid polygon with hole",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+			},
+		},
+		{
+			name: "hole touching shell at one point",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{0, 0}, {1, 3}, {3, 3}, {3, 1}, {0, 0}},
+			},
+		},
+		{
+			name:  "nan point",
+			input: orb.Point{math.NaN(), 1},
+			reasons: []Reason{
+				{Code: InvalidCoordinate, Point: orb.Point{math.NaN(), 1}},
+			},
+		},
+		{
+			name:  "line with one distinct point",
+			input: orb.LineString{{1, 1}, {1, 1}},
+			reasons: []Reason{
+				{Code: RepeatedPoint, Point: orb.Point{1, 1}},
+				{Code: TooFewPoints, Point: orb.Point{1, 1}},
+			},
+		},
+		{
+			name:  "line with spike",
+			input: orb.LineString{{0, 0}, {2, 0}, {1, 0}, {1, 1}},
+			reasons: []Reason{
+				{Code: Spike, Point: orb.Point{2, 0}},
+			},
+		},
+		{
+			name:  "unclosed ring",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}},
+			reasons: []Reason{
+				{Code: RingNotClosed, Point: orb.Point{0, 2}, Path: []int{0}},
+			},
+		},
+		{
+			name:  "too few points",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {0, 0}}},
+			reasons: []Reason{
+				{Code: TooFewPoints, Point: orb.Point{0, 0}, Path: []int{0}},
+			},
+		},
+		{
+			name:  "repeated point",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+			reasons: []Reason{
+				{Code: RepeatedPoint, Point: orb.Point{2, 0}, Path: []int{0}},
+			},
+		},
+		{
+			name:  "bow tie",
+			input: orb.Polygon{{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}},
+			reasons: []Reason{
+				{Code: SelfIntersection, Point: orb.Point{1, 1}, Path: []int{0}},
+			},
+		},
+		{
+			name:  "ring spike",
+			input: orb.Polygon{{{0, 0}, {2, 0}, {3, 0}, {2, 0}, {2, 2}, {0, 0}}},
+			reasons: []Reason{
+				{Code: Spike, Point: orb.Point{3, 0}, Path: []int{0}},
+				{Code: SelfIntersection, Point: orb.Point{2, 0}, Path: []int{0}},
+			},
+		},
+		{
+			name:  "clockwise shell",
+			input: orb.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}},
+		},
+		{
+			name:  "clockwise shell with right hand rule",
+			input: orb.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}},
+			opts:  []Option{RightHandRule()},
+			reasons: []Reason{
+				{Code: WrongOrientation, Point: orb.Point{0, 0}, Path: []int{0}},
+			},
+		},
+		{
+			name: "counter-clockwise hole with right hand rule",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}},
+			},
+			opts: []Option{RightHandRule()},
+			reasons: []Reason{
+				{Code: WrongOrientation, Point: orb.Point{1, 1}, Path: []int{1}},
+			},
+		},
+		{
+			name: "overlap with other problems",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			opts: []Option{RightHandRule()},
+			reasons: []Reason{
+				{Code: WrongOrientation, Point: orb.Point{0, 0}, Path: []int{0, 0}},
+				{Code: RepeatedPoint, Point: orb.Point{3, 1}, Path: []int{1, 0}},
+				{Code: OverlappingPolygons, Point: orb.Point{2, 1}, Path: []int{1}},
+			},
+		},
+		{
+			name: "hole outside shell",
+			input: orb.Polygon{
+				{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+				{{5, 5}, {5, 6}, {6, 6}, {6, 5}, {5, 5}},
+			},
+			reasons: []Reason{
+				{Code: HoleOutsideShell, Point: orb.Point{5, 5}, Path: []int{1}},
+			},
+		},
+		{
+			name: "nested holes",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{1, 1}, {1, 5}, {5, 5}, {5, 1}, {1, 1}},
+				{{2, 2}, {2, 3}, {3, 3}, {3, 2}, {2, 2}},
+			},
+			reasons: []Reason{
+				{Code: NestedHoles, Point: orb.Point{2, 2}, Path: []int{2}},
+			},
+		},
+		{
+			name: "hole crossing shell",
+			input: orb.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{3, 1}, {3, 3}, {5, 3}, {5, 1}, {3, 1}},
+			},
+			reasons: []Reason{
+				{Code: HoleOutsideShell, Point: orb.Point{5, 3}, Path: []int{1}},
+				{Code: SelfIntersection, Point: orb.Point{4, 1}, Path: []int{1}},
+				{Code: SelfIntersection, Point: orb.Point{4, 3}, Path: []int{1}},
+			},
+		},
+		{
+			name: "overlapping polygons",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			reasons: []Reason{
+				{Code: OverlappingPolygons, Point: orb.Point{2, 1}, Path: []int{1}},
+			},
+		},
+		{
+			name: "polygons sharing an edge",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+				{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+			},
+		},
+		{
+			name: "collection path",
+			input: orb.Collection{
+				orb.Point{1, 1},
+				orb.MultiPolygon{
+					{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+					{{{3, 0}, {5, 0}, {5, 2}, {3, 2}}},
+				},
+			},
+			reasons: []Reason{
+				{Code: RingNotClosed, Point: orb.Point{3, 2}, Path: []int{1, 1, 0}},
+			},
+		},
+		{
+			name:  "empty bound",
+			input: orb.Bound{Min: orb.Point{2, 0}, Max: orb.Point{1, 1}},
+			reasons: []Reason{
+				{Code: InvalidBound, Point: orb.Point{2, 0}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, reasons := IsValid(tc.input, tc.opts...)
+			if valid != (len(tc.reasons) == 0) {
+				t.Errorf("incorrect valid: %v", valid)
+			}
+
+			if len(reasons) != len(tc.reasons) {
+				t.Fatalf("incorrect reasons: %v", reasons)
+			}
+
+			for i, r := range reasons {
+				e := tc.reasons[i]
+				if r.Code != e.Code || !samePoint(r.Point, e.Point) || !reflect.DeepEqual(r.Path, e.Path) {
+					t.Errorf("incorrect reason %d: %v != %v", i, r, e)
+				}
+			}
+		})
+	}
+}
+
+func TestReason_String(t *testing.T) {
+	r := Reason{Code: Spike, Point: orb.Point{1, 2}, Path: []int{0, 1}}
+	if v := r.String(); v != "spike at [1 2], path [0 1]" {
+		t.Errorf("incorrect string: %v", v)
+	}
+
+	if v := Code(100).String(); v != "unknown code 100" {
+		t.Errorf("incorrect string: %v", v)
+	}
+}
+
+func samePoint(a, b orb.Point) bool {
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package orb
+
+import (
+	"fmt"
+	"math"
+)
+
+// Layout describes the ordinates of each point beyond x and y.
+// Z is usually an elevation and M a measure, like time or distance.
+type Layout uint8
+
+// The possible point layouts.
+const (
+	XY Layout = iota
+	XYZ
+	XYM
+	XYZM
+)
+
+// HasZ returns true if the layout has a Z value.
+func (l Layout) HasZ() bool {
+	return l == XYZ || l == XYZM
+}
+
+// HasM returns true if the layout has an M value.
+func (l Layout) HasM() bool {
+	return l == XYM || l == XYZM
+}
+
+// Stride returns the number of ordinates for each point.
+func (l Layout) Stride() int {
+	switch l {
+	case XYZ, XYM:
+		return 3
+	case XYZM:
+		return 4
+	}
+
+	return 2
+}
+
+// String returns the name of the layout, e.g. "XYZ".
+func (l Layout) String() string {
+	switch l {
+	case XY:
+		return "XY"
+	case XYZ:
+		return "XYZ"
+	case XYM:
+		return "XYM"
+	case XYZM:
+		return "XYZM"
+	}
+
+	return fmt.Sprintf("Layout(%d)", uint8(l))
+}
+
+// GeometryZM is a geometry with Z and/or M values for each point.
+// The orb geometry types are 2d so the extra values are kept in a parallel
+// slice in the order the points appear in the geometry. Polygons visit each
+// ring in order and collections each geometry in order. A Bound is its Min
+// then Max point.
+type GeometryZM struct {
+	Geometry Geometry
+	Layout   Layout
+
+	// Extra has Layout.Stride()-2 values for each point, Z before M.
+	Extra []float64
+}
+
+// Bound returns the 2d bound of the geometry, see ZRange and MRange
+// for the range of the other values.
+func (g GeometryZM) Bound() Bound {
+	if g.Geometry == nil {
+		return Bound{}
+	}
+
+	return g.Geometry.Bound()
+}
+
+// Z returns the Z value of the i-th point, NaN if the layout has no Z.
+func (g GeometryZM) Z(i int) float64 {
+	if !g.Layout.HasZ() {
+		return math.NaN()
+	}
+
+	return g.Extra[i*(g.Layout.Stride()-2)]
+}
+
+// M returns the M value of the i-th point, NaN if the layout has no M.
+func (g GeometryZM) M(i int) float64 {
+	if !g.Layout.HasM() {
+		return math.NaN()
+	}
+
+	stride := g.Layout.Stride() - 2
+	return g.Extra[i*stride+stride-1]
+}
+
+// ZRange returns the minimum and maximum Z values.
+// Both are NaN if there are no Z values.
+func (g GeometryZM) ZRange() (float64, float64) {
+	if !g.Layout.HasZ() {
+		return math.NaN(), math.NaN()
+	}
+
+	return g.extraRange(0)
+}
+
+// MRange returns the minimum and maximum M values.
+// Both are NaN if there are no M values.
+func (g GeometryZM) MRange() (float64, float64) {
+	if !g.Layout.HasM() {
+		return math.NaN(), math.NaN()
+	}
+
+	return g.extraRange(g.Layout.Stride() - 3)
+}
+
+func (g GeometryZM) extraRange(offset int) (float64, float64) {
+	stride := g.Layout.Stride() - 2
+
+	min, max := math.NaN(), math.NaN()
+	for i := offset; i < len(g.Extra); i += stride {
+		v := g.Extra[i]
+		if !(v >= min) {
+			min = v
+		}
+		if !(v <= max) {
+			max = v
+		}
+	}
+
+	return min, max
+}
+
+// Valid returns true if there are the correct number of extra values
+// for the points in the geometry and the layout.
+func (g GeometryZM) Valid() bool {
+	return len(g.Extra) == (g.Layout.Stride()-2)*PointCount(g.Geometry)
+}
+
+// PointCount returns the number of points in the geometry. For a bound this is 2.
+func PointCount(g Geometry) int {
+	switch g := g.(type) {
+	case nil:
+		return 0
+	case Point:
+		return 1
+	case MultiPoint:
+		return len(g)
+	case LineString:
+		return len(g)
+	case MultiLineString:
+		n := 0
+		for _, ls := range g {
+			n += len(ls)
+		}
+		return n
+	case Ring:
+		return len(g)
+	case Polygon:
+		n := 0
+		for _, r := range g {
+			n += len(r)
+		}
+		return n
+	case MultiPolygon:
+		n := 0
+		for _, p := range g {
+			n += PointCount(p)
+		}
+		return n
+	case Collection:
+		n := 0
+		for _, c := range g {
+			n += PointCount(c)
+		}
+		return n
+	case Bound:
+		return 2
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
@@ -0,0 +1,78 @@
+package orb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	cases := []struct {
+		layout Layout
+		z, m   bool
+		stride int
+		name   string
+	}{
+		{XY, false, false, 2, "XY"},
+		{XYZ, true, false, 3, "XYZ"},
+		{XYM, false, true, 3, "XYM"},
+		{XYZM, true, true, 4, "XYZM"},
+	}
+
+	for _, tc := range cases {
+		if v := tc.layout.HasZ(); v != tc.z {
+			t.Errorf("%v: incorrect has z: %v", tc.name, v)
+		}
+		if v := tc.layout.HasM(); v != tc.m {
+			t.Errorf("%v: incorrect has m: %v", tc.name, v)
+		}
+		if v := tc.layout.Stride(); v != tc.stride {
+			t.Errorf("%v: incorrect stride: %v", tc.name, v)
+		}
+		if v := tc.layout.String(); v != tc.name {
+			t.Errorf("incorrect string: %v", v)
+		}
+	}
+}
+
+func TestGeometryZM(t *testing.T) {
+	g := GeometryZM{
+		Geometry: Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		Layout:   XYM,
+		Extra:    []float64{4, 3, 2, 4},
+	}
+
+	if !g.Valid() {
+		t.Errorf("should be valid")
+	}
+
+	if v := g.M(1); v != 3 {
+		t.Errorf("incorrect m: %v", v)
+	}
+
+	if v := g.Z(1); !math.IsNaN(v) {
+		t.Errorf("z should be NaN: %v", v)
+	}
+
+	if min, max := g.MRange(); min != 2 || max != 4 {
+		t.Errorf("incorrect m range: %v %v", min, max)
+	}
+
+	if min, max := g.ZRange(); !math.IsNaN(min) || !math.IsNaN(max) {
+		t.Errorf("z range should be NaN: %v %v", min, max)
+	}
+
+	g.Extra = g.Extra[:3]
+	if g.Valid() {
+		t.Errorf("should not be valid")
+	}
+}
+
+func TestPointCount(t *testing.T) {
+	for _, g := range AllGeometries {
+		PointCount(g)
+	}
+
+	if v := PointCount(MultiPolygon{{{{0, 0}, {1, 1}}}, {{{0, 0}}, {{1, 1}, {2, 2}}}}); v != 5 {
+		t.Errorf("incorrect count: %v", v)
+	}
+}